@@ -2,31 +2,97 @@ package repository
 
 import (
 	"antrein/bc-dashboard/application/common/resource"
+	"antrein/bc-dashboard/internal/repository/analytic"
+	"antrein/bc-dashboard/internal/repository/asset"
+	"antrein/bc-dashboard/internal/repository/autoscale"
+	"antrein/bc-dashboard/internal/repository/bypass"
 	"antrein/bc-dashboard/internal/repository/configuration"
+	"antrein/bc-dashboard/internal/repository/event"
+	"antrein/bc-dashboard/internal/repository/health"
+	"antrein/bc-dashboard/internal/repository/history"
 	"antrein/bc-dashboard/internal/repository/infra"
 	"antrein/bc-dashboard/internal/repository/project"
+	"antrein/bc-dashboard/internal/repository/provisioning"
+	"antrein/bc-dashboard/internal/repository/storage"
+	"antrein/bc-dashboard/internal/repository/template"
 	"antrein/bc-dashboard/internal/repository/tenant"
+	"antrein/bc-dashboard/internal/repository/theme"
+	"antrein/bc-dashboard/internal/repository/webhook"
 	"antrein/bc-dashboard/model/config"
 )
 
 type CommonRepository struct {
-	TenantRepo  *tenant.Repository
-	ProjectRepo *project.Repository
-	ConfigRepo  *configuration.Repository
-	InfraRepo   *infra.Repository
+	TenantRepo       *tenant.Repository
+	ProjectRepo      *project.Repository
+	ConfigRepo       *configuration.Repository
+	InfraRepo        infra.InfraManager
+	TemplateRepo     *template.Repository
+	HistoryRepo      *history.Repository
+	BypassRepo       *bypass.Repository
+	AnalyticRepo     *analytic.Repository
+	AutoscaleRepo    *autoscale.Repository
+	ThemeRepo        *theme.Repository
+	AssetRepo        *asset.Repository
+	Storage          storage.Storage
+	ProvisioningRepo *provisioning.Repository
+	HealthRepo       *health.Repository
+	EventBus         *event.Bus
+	WebhookRepo      *webhook.Repository
+	WebhookSender    *webhook.Sender
 }
 
 func NewCommonRepository(cfg *config.Config, rsc *resource.CommonResource) (*CommonRepository, error) {
+	if err := infra.ValidateMode(cfg); err != nil {
+		return nil, err
+	}
 	tenantRepo := tenant.New(cfg, rsc.Db)
-	infraRepo := infra.New(cfg)
+	var infraRepo infra.InfraManager
+	if cfg.Infra.UseFake {
+		infraRepo = infra.NewFake(cfg.Infra.ManagerURL)
+	} else {
+		httpInfraRepo, err := infra.New(cfg)
+		if err != nil {
+			return nil, err
+		}
+		infraRepo = httpInfraRepo
+	}
 	projectRepo := project.New(cfg, rsc.Db, infraRepo)
 	configRepo := configuration.New(cfg, rsc.Db, infraRepo)
+	templateRepo := template.New(cfg, rsc.Db)
+	historyRepo := history.New(cfg, rsc.Db)
+	bypassRepo := bypass.New(cfg, rsc.Db)
+	analyticRepo := analytic.New(cfg, rsc.GRPC)
+	autoscaleRepo := autoscale.New(cfg, rsc.Db)
+	themeRepo := theme.New(cfg, rsc.Db)
+	assetRepo := asset.New(cfg, rsc.Db)
+	provisioningRepo := provisioning.New(cfg, rsc.Db)
+	healthRepo := health.New(cfg, rsc.Db)
+	eventBus := event.New()
+	webhookRepo := webhook.New(cfg, rsc.Db)
+	webhookSender := webhook.NewSender(cfg.Stage == config.StageDevelopment)
+	assetStorage, err := storage.New(cfg, infraRepo)
+	if err != nil {
+		return nil, err
+	}
 
 	commonRepo := CommonRepository{
-		TenantRepo:  tenantRepo,
-		ProjectRepo: projectRepo,
-		ConfigRepo:  configRepo,
-		InfraRepo:   infraRepo,
+		TenantRepo:       tenantRepo,
+		ProjectRepo:      projectRepo,
+		ConfigRepo:       configRepo,
+		InfraRepo:        infraRepo,
+		TemplateRepo:     templateRepo,
+		HistoryRepo:      historyRepo,
+		BypassRepo:       bypassRepo,
+		AnalyticRepo:     analyticRepo,
+		AutoscaleRepo:    autoscaleRepo,
+		ThemeRepo:        themeRepo,
+		AssetRepo:        assetRepo,
+		Storage:          assetStorage,
+		ProvisioningRepo: provisioningRepo,
+		HealthRepo:       healthRepo,
+		EventBus:         eventBus,
+		WebhookRepo:      webhookRepo,
+		WebhookSender:    webhookSender,
 	}
 	return &commonRepo, nil
 }
@@ -2,27 +2,53 @@ package usecase
 
 import (
 	"antrein/bc-dashboard/application/common/repository"
+	"antrein/bc-dashboard/internal/repository/event"
 	"antrein/bc-dashboard/internal/usecase/auth"
+	"antrein/bc-dashboard/internal/usecase/autoscale"
+	"antrein/bc-dashboard/internal/usecase/bypass"
 	"antrein/bc-dashboard/internal/usecase/configuration"
+	"antrein/bc-dashboard/internal/usecase/health"
+	"antrein/bc-dashboard/internal/usecase/manifest"
 	"antrein/bc-dashboard/internal/usecase/project"
+	"antrein/bc-dashboard/internal/usecase/provisioning"
+	"antrein/bc-dashboard/internal/usecase/webhook"
 	"antrein/bc-dashboard/model/config"
 )
 
 type CommonUsecase struct {
-	AuthUsecase    *auth.Usecase
-	ProjectUsecase *project.Usecase
-	ConfigUsecase  *configuration.Usecase
+	AuthUsecase         *auth.Usecase
+	ProjectUsecase      *project.Usecase
+	ConfigUsecase       *configuration.Usecase
+	ManifestUsecase     *manifest.Usecase
+	BypassUsecase       *bypass.Usecase
+	AutoscaleUsecase    *autoscale.Usecase
+	ProvisioningUsecase *provisioning.Usecase
+	HealthUsecase       *health.Usecase
+	WebhookUsecase      *webhook.Usecase
 }
 
 func NewCommonUsecase(cfg *config.Config, repo *repository.CommonRepository) (*CommonUsecase, error) {
 	authUsecase := auth.New(cfg, repo.TenantRepo)
-	configUsecase := configuration.New(cfg, repo.ConfigRepo, repo.InfraRepo)
-	projectUsecase := project.New(cfg, repo.ProjectRepo, repo.InfraRepo)
+	provisioningUsecase := provisioning.New(cfg, repo.ProvisioningRepo, repo.InfraRepo, repo.EventBus)
+	configUsecase := configuration.New(cfg, repo.ConfigRepo, repo.Storage, repo.HistoryRepo, repo.ThemeRepo, repo.AssetRepo, repo.AutoscaleRepo, provisioningUsecase, repo.EventBus)
+	projectUsecase := project.New(cfg, repo.ProjectRepo, repo.InfraRepo, repo.TemplateRepo, repo.HistoryRepo, configUsecase, provisioningUsecase, repo.EventBus)
+	manifestUsecase := manifest.New(cfg, repo.ProjectRepo, projectUsecase, configUsecase)
+	bypassUsecase := bypass.New(cfg, repo.BypassRepo, repo.ProjectRepo)
+	autoscaleUsecase := autoscale.New(cfg, repo.AutoscaleRepo, repo.ConfigRepo, repo.InfraRepo, repo.AnalyticRepo, repo.HistoryRepo, repo.EventBus)
+	healthUsecase := health.New(cfg, repo.HealthRepo, repo.ConfigRepo, repo.InfraRepo, repo.EventBus)
+	webhookUsecase := webhook.New(cfg, repo.WebhookRepo, repo.WebhookSender)
+	repo.EventBus.Subscribe(event.AllEvents, webhookUsecase.EnqueueEvent)
 
 	commonUC := CommonUsecase{
-		AuthUsecase:    authUsecase,
-		ProjectUsecase: projectUsecase,
-		ConfigUsecase:  configUsecase,
+		AuthUsecase:         authUsecase,
+		ProjectUsecase:      projectUsecase,
+		ConfigUsecase:       configUsecase,
+		ManifestUsecase:     manifestUsecase,
+		BypassUsecase:       bypassUsecase,
+		AutoscaleUsecase:    autoscaleUsecase,
+		ProvisioningUsecase: provisioningUsecase,
+		HealthUsecase:       healthUsecase,
+		WebhookUsecase:      webhookUsecase,
 	}
 	return &commonUC, nil
 }
@@ -0,0 +1,116 @@
+package usecase
+
+import (
+	"antrein/bc-dashboard/application/common/repository"
+	"antrein/bc-dashboard/application/common/resource"
+	"antrein/bc-dashboard/internal/repository/infra"
+	"antrein/bc-dashboard/model/config"
+	"antrein/bc-dashboard/model/dto"
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+)
+
+// TestProjectLifecycle creates, configures and health checks a project
+// against the in-memory infra manager. It needs an empty Postgres database
+// in BC_DASHBOARD_TEST_DB and is skipped without one.
+func TestProjectLifecycle(t *testing.T) {
+	dsn := os.Getenv("BC_DASHBOARD_TEST_DB")
+	if dsn == "" {
+		t.Skip("BC_DASHBOARD_TEST_DB is not set")
+	}
+
+	// Migrations are read relative to the repository root.
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir("../../.."); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	cfg := &config.Config{
+		Stage:      "test",
+		Database:   config.DatabaseConfig{PostgreDB: config.PostgreConfig{Host: dsn}},
+		Secrets:    config.SecretConfig{JWTSecret: "jwt-secret", BypassSecret: "bypass-secret"},
+		Infra:      config.InfraConfig{UseFake: true, ManagerURL: "http://infra.test"},
+		GRPCConfig: config.GRPCConfig{DashboardQueue: "localhost:1"},
+	}
+
+	ctx := context.Background()
+	rsc, err := resource.NewCommonResource(cfg, ctx)
+	if err != nil {
+		t.Fatalf("NewCommonResource() error = %v", err)
+	}
+	defer rsc.Db.Close()
+	repo, err := repository.NewCommonRepository(cfg, rsc)
+	if err != nil {
+		t.Fatalf("NewCommonRepository() error = %v", err)
+	}
+	uc, err := NewCommonUsecase(cfg, repo)
+	if err != nil {
+		t.Fatalf("NewCommonUsecase() error = %v", err)
+	}
+	fake := repo.InfraRepo.(*infra.Fake)
+
+	suffix := time.Now().UnixNano()
+	var tenantID string
+	err = rsc.Db.GetContext(ctx, &tenantID, `INSERT INTO tenants (email, password, name) VALUES ($1, 'x', 'Test') RETURNING id`,
+		fmt.Sprintf("lifecycle-%d@antrein.test", suffix))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rsc.Db.ExecContext(ctx, `DELETE FROM tenants WHERE id = $1`, tenantID)
+
+	projectID := fmt.Sprintf("lifecycle-%d", suffix)
+	_, errRes := uc.ProjectUsecase.RegisterNewProject(ctx, dto.CreateProjectRequest{ID: projectID, Name: "Lifecycle"}, tenantID)
+	if errRes != nil {
+		t.Fatalf("RegisterNewProject() error = %s", errRes.Error)
+	}
+	defer uc.ProjectUsecase.DeleteProject(ctx, projectID, tenantID)
+
+	queueStart := time.Now().Add(time.Hour)
+	errRes = uc.ConfigUsecase.UpdateProjectConfig(ctx, dto.UpdateProjectConfig{
+		ProjectID:       projectID,
+		Threshold:       100,
+		SessionTime:     10,
+		Host:            projectID + ".antrein.test",
+		BaseURL:         "/",
+		MaxUsersInQueue: 1000,
+		QueueStart:      queueStart.Format("2006-01-02T15:04:05"),
+		QueueEnd:        queueStart.Add(time.Hour).Format("2006-01-02T15:04:05"),
+	})
+	if errRes != nil {
+		t.Fatalf("UpdateProjectConfig() error = %s", errRes.Error)
+	}
+	body, ok := fake.Project(projectID)
+	if !ok {
+		t.Fatal("configured project was not created on the infra manager")
+	}
+	if body.ProjectDomain != projectID+".antrein.test" {
+		t.Errorf("infra project domain = %q, want %q", body.ProjectDomain, projectID+".antrein.test")
+	}
+
+	health, errRes := uc.ProjectUsecase.CheckHealthProject(ctx, projectID)
+	if errRes != nil {
+		t.Fatalf("CheckHealthProject() error = %s", errRes.Error)
+	}
+	if !health.Healthiness {
+		t.Error("CheckHealthProject() reports a new project as unhealthy")
+	}
+
+	uc.HealthUsecase.ProbeProjects(ctx)
+	fake.SetHealth(projectID, false)
+	uc.HealthUsecase.ProbeProjects(ctx)
+
+	incidents, errRes := uc.HealthUsecase.GetIncidents(ctx, projectID, tenantID, 1)
+	if errRes != nil {
+		t.Fatalf("GetIncidents() error = %s", errRes.Error)
+	}
+	if len(incidents.Incidents) != 1 || !incidents.Incidents[0].Ongoing {
+		t.Errorf("GetIncidents() = %+v, want one ongoing incident", incidents.Incidents)
+	}
+}
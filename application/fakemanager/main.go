@@ -0,0 +1,61 @@
+// Command fakemanager runs an in-memory infra manager for running and
+// integration testing the dashboard offline. Point infra.manager_url at it
+// and give it the same auth settings as infra.auth.
+package main
+
+import (
+	"antrein/bc-dashboard/internal/repository/infra"
+	"antrein/bc-dashboard/model/config"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+)
+
+func main() {
+	port := flag.String("port", "8000", "port to listen on")
+	publicURL := flag.String("public-url", "", "base URL of uploaded files, defaults to http(s)://localhost:<port>")
+	authMode := flag.String("auth-mode", infra.AuthModeNone, "none, bearer, hmac or mtls")
+	token := flag.String("token", "", "bearer token to expect")
+	hmacSecret := flag.String("hmac-secret", "", "secret of signed requests")
+	maxSkew := flag.Int("max-skew", 0, "allowed age of signed requests in seconds")
+	tlsCert := flag.String("tls-cert", "", "server certificate, serves HTTPS when set")
+	tlsKey := flag.String("tls-key", "", "server certificate key")
+	clientCA := flag.String("client-ca", "", "CA of client certificates, required for mtls")
+	flag.Parse()
+
+	if *authMode == infra.AuthModeMTLS && (*tlsCert == "" || *clientCA == "") {
+		log.Fatal("mtls needs -tls-cert, -tls-key and -client-ca")
+	}
+
+	scheme := "http"
+	if *tlsCert != "" {
+		scheme = "https"
+	}
+	baseURL := *publicURL
+	if baseURL == "" {
+		baseURL = scheme + "://localhost:" + *port
+	}
+
+	handler := infra.NewFakeServer(infra.NewFake(baseURL), config.InfraAuthConfig{
+		Mode:           *authMode,
+		Token:          *token,
+		HMACSecret:     *hmacSecret,
+		MaxSkewSeconds: *maxSkew,
+	})
+	server := &http.Server{
+		Addr:    ":" + *port,
+		Handler: handler,
+	}
+
+	fmt.Printf("Fake infra manager is starting on %s://localhost:%s\n", scheme, *port)
+	if *tlsCert == "" {
+		log.Fatal(server.ListenAndServe())
+	}
+	tlsConfig, err := infra.LoadTLSConfig(*tlsCert, *tlsKey, *clientCA, true)
+	if err != nil {
+		log.Fatal(err)
+	}
+	server.TLSConfig = tlsConfig
+	log.Fatal(server.ListenAndServeTLS("", ""))
+}
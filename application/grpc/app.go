@@ -3,6 +3,7 @@ package grpc
 import (
 	"antrein/bc-dashboard/application/common/resource"
 	"antrein/bc-dashboard/application/common/usecase"
+	"antrein/bc-dashboard/internal/handler/grpc/bypass"
 	"antrein/bc-dashboard/internal/handler/grpc/configuration"
 	"antrein/bc-dashboard/model/config"
 	"context"
@@ -30,5 +31,9 @@ func ApplicationDelegate(cfg *config.Config, uc *usecase.CommonUsecase, rsc *res
 	projectConfigServer := configuration.New(uc.ConfigUsecase)
 	pb.RegisterProjectConfigServiceServer(grpcServer, projectConfigServer)
 
+	// Bypass rule service
+	bypassServer := bypass.New(uc.BypassUsecase)
+	grpcServer.RegisterService(&bypass.ServiceDesc, bypassServer)
+
 	return grpcServer, nil
 }
@@ -6,6 +6,7 @@ import (
 	"antrein/bc-dashboard/application/common/usecase"
 	"antrein/bc-dashboard/application/grpc"
 	"antrein/bc-dashboard/application/rest"
+	"antrein/bc-dashboard/application/worker"
 	"antrein/bc-dashboard/model/config"
 	"context"
 	"log"
@@ -47,6 +48,17 @@ func main() {
 		}
 	}()
 
+	// Start background jobs concurrently
+	go func() {
+		worker_app, err := worker.ApplicationDelegate(cfg, uc)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := worker.StartServer(cfg, worker_app); err != nil {
+			log.Fatal(err)
+		}
+	}()
+
 	if err = rest.StartServer(cfg, rest_app); err != nil {
 		log.Fatal(err)
 	}
@@ -5,7 +5,14 @@ import (
 	"antrein/bc-dashboard/application/common/usecase"
 	"antrein/bc-dashboard/internal/handler/grpc/analytic"
 	"antrein/bc-dashboard/internal/handler/rest/auth"
+	"antrein/bc-dashboard/internal/handler/rest/autoscale"
+	"antrein/bc-dashboard/internal/handler/rest/bypass"
+	"antrein/bc-dashboard/internal/handler/rest/health"
+	"antrein/bc-dashboard/internal/handler/rest/manifest"
 	"antrein/bc-dashboard/internal/handler/rest/project"
+	"antrein/bc-dashboard/internal/handler/rest/theme"
+	"antrein/bc-dashboard/internal/handler/rest/webhook"
+	"antrein/bc-dashboard/internal/repository/storage"
 	"antrein/bc-dashboard/model/config"
 	"compress/gzip"
 	"fmt"
@@ -30,6 +37,14 @@ func (w gzipResponseWriter) Write(b []byte) (int, error) {
 	return w.Writer.Write(b)
 }
 
+// Flush lets server sent events through the compression.
+func (w gzipResponseWriter) Flush() {
+	w.Writer.Flush()
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
 func compressHandler(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
@@ -55,16 +70,47 @@ func ApplicationDelegate(cfg *config.Config, uc *usecase.CommonUsecase, rsc *res
 		fmt.Fprintln(w, "pong!")
 	})
 
+	// files of the local storage backend
+	if storageCfg := storage.StageConfig(cfg); storageCfg.Backend == storage.BackendLocal {
+		router.PathPrefix(storage.LocalRoute).Handler(http.StripPrefix(storage.LocalRoute, http.FileServer(http.Dir(storageCfg.LocalDir))))
+	}
+
 	// routes
 
 	// auth
 	authRoute := auth.New(cfg, uc.AuthUsecase, rsc.Vld)
 	authRoute.RegisterRoute(router)
 
-	// project
-	projectRoute := project.New(cfg, uc.ProjectUsecase, uc.ConfigUsecase, rsc.Vld)
+	// bypass
+	bypassRoute := bypass.New(cfg, uc.BypassUsecase, rsc.Vld)
+	bypassRoute.RegisterRoute(router)
+
+	// theme
+	themeRoute := theme.New(cfg, uc.ConfigUsecase, rsc.Vld)
+	themeRoute.RegisterRoute(router)
+
+	// autoscale
+	autoscaleRoute := autoscale.New(cfg, uc.AutoscaleUsecase, rsc.Vld)
+	autoscaleRoute.RegisterRoute(router)
+
+	// health
+	healthRoute := health.New(cfg, uc.HealthUsecase, rsc.Vld)
+	healthRoute.RegisterRoute(router)
+
+	// project, after the other fixed /project routes so a project id is never
+	// taken for one of them
+	projectRoute := project.New(cfg, uc.ProjectUsecase, uc.ConfigUsecase, uc.ProvisioningUsecase, rsc.Vld)
 	projectRoute.RegisterRoute(router)
 
+	// manifest, after every fixed /project route so the project id of an
+	// export is never taken for one of them
+	manifestRoute := manifest.New(cfg, uc.ManifestUsecase, rsc.Vld)
+	manifestRoute.RegisterRoute(router)
+
+	// webhook
+	webhookRoute := webhook.New(cfg, uc.WebhookUsecase, rsc.Vld)
+	webhookRoute.RegisterRoute(router)
+
 	// analytic
 	analyticRouter := analytic.New(cfg, rsc.GRPC)
 	analyticRouter.RegisterRoute(router)
@@ -0,0 +1,83 @@
+package worker
+
+import (
+	"antrein/bc-dashboard/application/common/usecase"
+	"antrein/bc-dashboard/model/config"
+	"context"
+	"time"
+)
+
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(ctx context.Context)
+}
+
+func ApplicationDelegate(cfg *config.Config, uc *usecase.CommonUsecase) ([]Job, error) {
+	jobs := []Job{}
+
+	// auto resume paused projects
+	jobs = append(jobs, Job{
+		Name:     "auto-resume",
+		Interval: 30 * time.Second,
+		Run:      uc.ConfigUsecase.ResumeDueProjects,
+	})
+
+	// adjust thresholds of projects with autoscaling
+	jobs = append(jobs, Job{
+		Name:     "autoscale",
+		Interval: 30 * time.Second,
+		Run:      uc.AutoscaleUsecase.AdjustThresholds,
+	})
+
+	// switch scheduled queue page themes
+	jobs = append(jobs, Job{
+		Name:     "theme-switch",
+		Interval: 30 * time.Second,
+		Run:      uc.ConfigUsecase.ApplyDueThemeSwitches,
+	})
+
+	// remove unreferenced page versions and logos
+	jobs = append(jobs, Job{
+		Name:     "asset-cleanup",
+		Interval: time.Hour,
+		Run:      uc.ConfigUsecase.CleanupAssetVersions,
+	})
+
+	// follow deployments that are coming up or going away
+	jobs = append(jobs, Job{
+		Name:     "provisioning-poll",
+		Interval: 15 * time.Second,
+		Run:      uc.ProvisioningUsecase.PollProvisioning,
+	})
+
+	// record the health of configured projects
+	jobs = append(jobs, Job{
+		Name:     "health-probe",
+		Interval: 30 * time.Second,
+		Run:      uc.HealthUsecase.ProbeProjects,
+	})
+
+	// remove old health checks
+	jobs = append(jobs, Job{
+		Name:     "health-cleanup",
+		Interval: time.Hour,
+		Run:      uc.HealthUsecase.CleanupHealthChecks,
+	})
+
+	// announce opened and closed queue windows
+	jobs = append(jobs, Job{
+		Name:     "queue-window",
+		Interval: 30 * time.Second,
+		Run:      uc.ConfigUsecase.NotifyQueueWindows,
+	})
+
+	// send due webhook deliveries
+	jobs = append(jobs, Job{
+		Name:     "webhook-delivery",
+		Interval: 5 * time.Second,
+		Run:      uc.WebhookUsecase.DeliverDueWebhooks,
+	})
+
+	return jobs, nil
+}
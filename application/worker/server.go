@@ -0,0 +1,30 @@
+package worker
+
+import (
+	"antrein/bc-dashboard/model/config"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+func StartServer(cfg *config.Config, jobs []Job) error {
+	ctx := context.Background()
+
+	fmt.Printf("Worker is starting with %d jobs\n", len(jobs))
+
+	var wg sync.WaitGroup
+	for _, job := range jobs {
+		wg.Add(1)
+		go func(job Job) {
+			defer wg.Done()
+			ticker := time.NewTicker(job.Interval)
+			defer ticker.Stop()
+			for range ticker.C {
+				job.Run(ctx)
+			}
+		}(job)
+	}
+	wg.Wait()
+	return nil
+}
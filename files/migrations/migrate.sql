@@ -38,4 +38,318 @@ CREATE TABLE IF NOT EXISTS configurations (
     queue_page_logo VARCHAR(155),
     is_configure boolean DEFAULT FALSE,
     updated_at timestamp
-);
\ No newline at end of file
+);
+
+CREATE TABLE IF NOT EXISTS project_templates (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id uuid NOT NULL REFERENCES tenants (id) ON DELETE CASCADE,
+    name VARCHAR(155) NOT NULL,
+    threshold INTEGER DEFAULT 0,
+    session_time INTEGER DEFAULT 5,
+    max_users_in_queue INTEGER DEFAULT 0,
+    queue_page_style style DEFAULT 'base',
+    queue_html_page TEXT,
+    queue_page_base_color VARCHAR(10),
+    queue_page_title VARCHAR(155),
+    queue_page_logo TEXT,
+    created_at timestamp NOT NULL DEFAULT now(),
+    updated_at timestamp
+);
+
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS description TEXT;
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS tags TEXT[];
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS environment VARCHAR(50);
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS contact_email VARCHAR(75);
+
+CREATE TABLE IF NOT EXISTS project_histories (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    project_id VARCHAR(75) NOT NULL REFERENCES projects (id) ON DELETE CASCADE,
+    actor VARCHAR(75),
+    action VARCHAR(50) NOT NULL,
+    detail jsonb,
+    created_at timestamp NOT NULL DEFAULT now()
+);
+
+DO $$ BEGIN
+    CREATE TYPE operational_state AS ENUM ('active', 'paused', 'drain_only', 'closed', 'maintenance');
+EXCEPTION
+    WHEN duplicate_object THEN null;
+END $$;
+
+ALTER TABLE configurations ADD COLUMN IF NOT EXISTS operational_state operational_state DEFAULT 'active';
+ALTER TABLE configurations ADD COLUMN IF NOT EXISTS state_reason VARCHAR(255);
+ALTER TABLE configurations ADD COLUMN IF NOT EXISTS state_changed_at timestamp;
+ALTER TABLE configurations ADD COLUMN IF NOT EXISTS auto_resume_at timestamp;
+
+DO $$ BEGIN
+    CREATE TYPE bypass_type AS ENUM ('invite_code', 'ip', 'header');
+EXCEPTION
+    WHEN duplicate_object THEN null;
+END $$;
+
+CREATE TABLE IF NOT EXISTS bypass_rules (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    project_id VARCHAR(75) NOT NULL REFERENCES projects (id) ON DELETE CASCADE,
+    type bypass_type NOT NULL,
+    name VARCHAR(155) NOT NULL,
+    code_hash VARCHAR(64) UNIQUE,
+    cidr VARCHAR(50),
+    header_name VARCHAR(100),
+    header_value VARCHAR(255),
+    max_uses INTEGER DEFAULT 0,
+    used_count INTEGER DEFAULT 0,
+    last_used_at timestamp,
+    expires_at timestamp,
+    is_active boolean DEFAULT TRUE,
+    created_at timestamp NOT NULL DEFAULT now(),
+    updated_at timestamp
+);
+
+CREATE TABLE IF NOT EXISTS priority_tiers (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    project_id VARCHAR(75) NOT NULL REFERENCES projects (id) ON DELETE CASCADE,
+    name VARCHAR(50) NOT NULL,
+    priority INTEGER NOT NULL DEFAULT 0,
+    weight INTEGER NOT NULL DEFAULT 1,
+    reserved_share INTEGER NOT NULL DEFAULT 0,
+    max_users_in_queue INTEGER NOT NULL DEFAULT 0,
+    created_at timestamp NOT NULL DEFAULT now(),
+    UNIQUE (project_id, name)
+);
+
+DO $$ BEGIN
+    CREATE TYPE queue_mode AS ENUM ('fifo', 'pre_queue_lottery');
+EXCEPTION
+    WHEN duplicate_object THEN null;
+END $$;
+
+ALTER TABLE configurations ADD COLUMN IF NOT EXISTS queue_mode queue_mode DEFAULT 'fifo';
+ALTER TABLE configurations ADD COLUMN IF NOT EXISTS pre_queue_open_at timestamp;
+ALTER TABLE configurations ADD COLUMN IF NOT EXISTS lottery_seed VARCHAR(64);
+
+ALTER TABLE configurations ADD COLUMN IF NOT EXISTS effective_threshold INTEGER;
+
+CREATE TABLE IF NOT EXISTS autoscale_policies (
+    project_id VARCHAR(75) PRIMARY KEY REFERENCES projects (id) ON DELETE CASCADE,
+    enabled boolean DEFAULT FALSE,
+    min_threshold INTEGER NOT NULL,
+    max_threshold INTEGER NOT NULL,
+    step_down INTEGER NOT NULL DEFAULT 1,
+    step_up INTEGER NOT NULL DEFAULT 1,
+    last_adjusted_at timestamp,
+    created_at timestamp NOT NULL DEFAULT now(),
+    updated_at timestamp
+);
+
+CREATE TABLE IF NOT EXISTS threshold_adjustments (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    project_id VARCHAR(75) NOT NULL REFERENCES projects (id) ON DELETE CASCADE,
+    from_threshold INTEGER NOT NULL,
+    to_threshold INTEGER NOT NULL,
+    healthiness boolean NOT NULL,
+    users_in_room INTEGER,
+    users_in_queue INTEGER,
+    reason VARCHAR(155),
+    created_at timestamp NOT NULL DEFAULT now()
+);
+
+DO $$ BEGIN
+    CREATE TYPE html_policy_mode AS ENUM ('strict', 'permissive');
+EXCEPTION
+    WHEN duplicate_object THEN null;
+END $$;
+
+ALTER TABLE tenants ADD COLUMN IF NOT EXISTS html_policy_mode html_policy_mode DEFAULT 'strict';
+
+CREATE TABLE IF NOT EXISTS themes (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id uuid NOT NULL REFERENCES tenants (id) ON DELETE CASCADE,
+    project_id VARCHAR(75) REFERENCES projects (id) ON DELETE CASCADE,
+    name VARCHAR(155) NOT NULL,
+    queue_page_style style DEFAULT 'base',
+    queue_html_page TEXT,
+    queue_page_base_color VARCHAR(10),
+    queue_page_title VARCHAR(155),
+    queue_page_logo TEXT,
+    created_at timestamp NOT NULL DEFAULT now(),
+    updated_at timestamp
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS themes_project_name_idx ON themes (project_id, name) WHERE project_id IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS themes_tenant_name_idx ON themes (tenant_id, name) WHERE project_id IS NULL;
+
+ALTER TABLE configurations ADD COLUMN IF NOT EXISTS active_theme_id uuid REFERENCES themes (id) ON DELETE SET NULL;
+
+-- move the single style of every project into its default theme
+DO $$ BEGIN
+    IF EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'configurations' AND column_name = 'queue_page_style') THEN
+        INSERT INTO themes (tenant_id, project_id, name, queue_page_style, queue_html_page, queue_page_base_color, queue_page_title, queue_page_logo)
+        SELECT p.tenant_id, c.project_id, 'default', c.queue_page_style, c.queue_html_page, c.queue_page_base_color, c.queue_page_title, c.queue_page_logo
+        FROM configurations c INNER JOIN projects p ON p.id = c.project_id
+        ON CONFLICT DO NOTHING;
+
+        UPDATE configurations c SET active_theme_id = t.id
+        FROM themes t WHERE t.project_id = c.project_id AND t.name = 'default';
+
+        ALTER TABLE configurations
+            DROP COLUMN queue_page_style,
+            DROP COLUMN queue_html_page,
+            DROP COLUMN queue_page_base_color,
+            DROP COLUMN queue_page_title,
+            DROP COLUMN queue_page_logo;
+    END IF;
+END $$;
+
+CREATE TABLE IF NOT EXISTS theme_schedules (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    project_id VARCHAR(75) NOT NULL REFERENCES projects (id) ON DELETE CASCADE,
+    theme_id uuid NOT NULL REFERENCES themes (id) ON DELETE CASCADE,
+    switch_at timestamp NOT NULL,
+    applied_at timestamp,
+    created_at timestamp NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS project_locales (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    project_id VARCHAR(75) NOT NULL REFERENCES projects (id) ON DELETE CASCADE,
+    locale VARCHAR(10) NOT NULL,
+    texts jsonb NOT NULL DEFAULT '{}',
+    created_at timestamp NOT NULL DEFAULT now(),
+    updated_at timestamp,
+    UNIQUE (project_id, locale)
+);
+
+CREATE TABLE IF NOT EXISTS logo_assets (
+    hash VARCHAR(64) PRIMARY KEY,
+    url TEXT NOT NULL,
+    content_type VARCHAR(50) NOT NULL,
+    size INTEGER NOT NULL,
+    created_at timestamp NOT NULL DEFAULT now()
+);
+
+ALTER TABLE logo_assets ADD COLUMN IF NOT EXISTS name VARCHAR(155);
+UPDATE logo_assets SET name = hash || CASE content_type WHEN 'image/jpeg' THEN '.jpg' WHEN 'image/svg+xml' THEN '.svg' ELSE '.png' END WHERE name IS NULL;
+ALTER TABLE logo_assets ALTER COLUMN name SET NOT NULL;
+
+CREATE TABLE IF NOT EXISTS page_versions (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id uuid NOT NULL REFERENCES tenants (id) ON DELETE CASCADE,
+    project_id VARCHAR(75) REFERENCES projects (id) ON DELETE SET NULL,
+    name VARCHAR(155) NOT NULL,
+    object_name VARCHAR(155) NOT NULL,
+    hash VARCHAR(64) NOT NULL,
+    url VARCHAR(255) NOT NULL,
+    logo_url TEXT,
+    published_at timestamp NOT NULL DEFAULT now(),
+    created_at timestamp NOT NULL DEFAULT now(),
+    UNIQUE (name, hash)
+);
+
+DO $$ BEGIN
+    CREATE TYPE infra_mode AS ENUM ('multi_tenant', 'single_tenant');
+EXCEPTION
+    WHEN duplicate_object THEN null;
+END $$;
+
+ALTER TABLE tenants ADD COLUMN IF NOT EXISTS is_premium boolean DEFAULT FALSE;
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS infra_mode infra_mode;
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS resource_size VARCHAR(20);
+
+DO $$ BEGIN
+    CREATE TYPE provisioning_state AS ENUM ('pending', 'provisioning', 'ready', 'failed', 'deprovisioning');
+EXCEPTION
+    WHEN duplicate_object THEN null;
+END $$;
+
+CREATE TABLE IF NOT EXISTS project_provisionings (
+    project_id VARCHAR(75) PRIMARY KEY REFERENCES projects (id) ON DELETE CASCADE,
+    state provisioning_state NOT NULL DEFAULT 'pending',
+    last_error TEXT,
+    state_changed_at timestamp NOT NULL DEFAULT now(),
+    created_at timestamp NOT NULL DEFAULT now(),
+    updated_at timestamp
+);
+
+CREATE TABLE IF NOT EXISTS provisioning_transitions (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    project_id VARCHAR(75) NOT NULL REFERENCES projects (id) ON DELETE CASCADE,
+    from_state provisioning_state NOT NULL,
+    to_state provisioning_state NOT NULL,
+    error TEXT,
+    created_at timestamp NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS provisioning_transitions_project_idx ON provisioning_transitions (project_id, created_at);
+
+CREATE TABLE IF NOT EXISTS health_checks (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    project_id VARCHAR(75) NOT NULL REFERENCES projects (id) ON DELETE CASCADE,
+    healthy boolean NOT NULL,
+    checked_at timestamp NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS health_checks_project_idx ON health_checks (project_id, checked_at);
+
+CREATE TABLE IF NOT EXISTS health_incidents (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    project_id VARCHAR(75) NOT NULL REFERENCES projects (id) ON DELETE CASCADE,
+    started_at timestamp NOT NULL,
+    ended_at timestamp
+);
+
+CREATE INDEX IF NOT EXISTS health_incidents_project_idx ON health_incidents (project_id, started_at);
+CREATE UNIQUE INDEX IF NOT EXISTS health_incidents_open_idx ON health_incidents (project_id) WHERE ended_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS webhooks (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id uuid NOT NULL REFERENCES tenants (id) ON DELETE CASCADE,
+    url VARCHAR(500) NOT NULL,
+    secret VARCHAR(100) NOT NULL,
+    events TEXT[] NOT NULL,
+    description VARCHAR(255),
+    is_active boolean DEFAULT TRUE,
+    created_at timestamp NOT NULL DEFAULT now(),
+    updated_at timestamp
+);
+
+DO $$ BEGIN
+    CREATE TYPE webhook_delivery_status AS ENUM ('pending', 'succeeded', 'failed');
+EXCEPTION
+    WHEN duplicate_object THEN null;
+END $$;
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    webhook_id uuid NOT NULL REFERENCES webhooks (id) ON DELETE CASCADE,
+    event_id VARCHAR(50) NOT NULL,
+    event_type VARCHAR(50) NOT NULL,
+    payload jsonb NOT NULL,
+    status webhook_delivery_status NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at timestamp,
+    last_status_code INTEGER,
+    last_error TEXT,
+    delivered_at timestamp,
+    created_at timestamp NOT NULL DEFAULT now(),
+    updated_at timestamp
+);
+
+CREATE INDEX IF NOT EXISTS webhook_deliveries_webhook_idx ON webhook_deliveries (webhook_id, created_at);
+CREATE INDEX IF NOT EXISTS webhook_deliveries_due_idx ON webhook_deliveries (next_attempt_at) WHERE status = 'pending';
+
+ALTER TABLE configurations ADD COLUMN IF NOT EXISTS notified_queue_start timestamp;
+ALTER TABLE configurations ADD COLUMN IF NOT EXISTS notified_queue_end timestamp;
+
+-- page and logo urls outgrow VARCHAR(155) with longer storage buckets
+DO $$ BEGIN
+    IF EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'themes' AND column_name = 'queue_page_logo' AND data_type = 'character varying') THEN
+        ALTER TABLE themes ALTER COLUMN queue_html_page TYPE TEXT, ALTER COLUMN queue_page_logo TYPE TEXT;
+        ALTER TABLE project_templates ALTER COLUMN queue_html_page TYPE TEXT, ALTER COLUMN queue_page_logo TYPE TEXT;
+        ALTER TABLE logo_assets ALTER COLUMN url TYPE TEXT;
+        ALTER TABLE page_versions ALTER COLUMN logo_url TYPE TEXT;
+    END IF;
+END $$;
+
+-- failed probes are not recorded, so checks and incidents never had an error
+ALTER TABLE health_checks DROP COLUMN IF EXISTS error;
+ALTER TABLE health_incidents DROP COLUMN IF EXISTS cause;
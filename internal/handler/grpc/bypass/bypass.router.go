@@ -0,0 +1,122 @@
+package bypass
+
+import (
+	"antrein/bc-dashboard/internal/usecase/bypass"
+	"context"
+	"encoding/json"
+	"errors"
+
+	pb "github.com/antrein/proto-repository/pb/bc"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// The shared proto repository has no bypass messages yet, so the service is
+// described by hand and uses well-known types on the wire:
+//
+//	service BypassRuleService {
+//	  rpc GetBypassRules(ConfigRequest) returns (google.protobuf.Struct);
+//	  rpc RedeemBypassCode(google.protobuf.Struct) returns (google.protobuf.Struct);
+//	  rpc RecordBypassUsage(google.protobuf.Struct) returns (google.protobuf.Struct);
+//	}
+type BypassRuleServiceServer interface {
+	GetBypassRules(context.Context, *pb.ConfigRequest) (*structpb.Struct, error)
+	RedeemBypassCode(context.Context, *structpb.Struct) (*structpb.Struct, error)
+	RecordBypassUsage(context.Context, *structpb.Struct) (*structpb.Struct, error)
+}
+
+var ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "bc.BypassRuleService",
+	HandlerType: (*BypassRuleServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetBypassRules",
+			Handler: func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+				in := new(pb.ConfigRequest)
+				if err := dec(in); err != nil {
+					return nil, err
+				}
+				handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+					return srv.(BypassRuleServiceServer).GetBypassRules(ctx, req.(*pb.ConfigRequest))
+				}
+				if interceptor == nil {
+					return handler(ctx, in)
+				}
+				return interceptor(ctx, in, &grpc.UnaryServerInfo{Server: srv, FullMethod: "/bc.BypassRuleService/GetBypassRules"}, handler)
+			},
+		},
+		{
+			MethodName: "RedeemBypassCode",
+			Handler:    structHandler("RedeemBypassCode", BypassRuleServiceServer.RedeemBypassCode),
+		},
+		{
+			MethodName: "RecordBypassUsage",
+			Handler:    structHandler("RecordBypassUsage", BypassRuleServiceServer.RecordBypassUsage),
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "bc/bypass.proto",
+}
+
+func structHandler(method string, call func(BypassRuleServiceServer, context.Context, *structpb.Struct) (*structpb.Struct, error)) func(interface{}, context.Context, func(interface{}) error, grpc.UnaryServerInterceptor) (interface{}, error) {
+	return func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+		in := new(structpb.Struct)
+		if err := dec(in); err != nil {
+			return nil, err
+		}
+		handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+			return call(srv.(BypassRuleServiceServer), ctx, req.(*structpb.Struct))
+		}
+		if interceptor == nil {
+			return handler(ctx, in)
+		}
+		return interceptor(ctx, in, &grpc.UnaryServerInfo{Server: srv, FullMethod: "/bc.BypassRuleService/" + method}, handler)
+	}
+}
+
+type Server struct {
+	usecase *bypass.Usecase
+}
+
+func New(usecase *bypass.Usecase) *Server {
+	return &Server{
+		usecase: usecase,
+	}
+}
+
+func toStruct(v interface{}) (*structpb.Struct, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	m := map[string]interface{}{}
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+	return structpb.NewStruct(m)
+}
+
+func (s *Server) GetBypassRules(ctx context.Context, in *pb.ConfigRequest) (*structpb.Struct, error) {
+	resp, err := s.usecase.GetActiveBypassRules(ctx, in.GetProjectId())
+	if err != nil {
+		return nil, errors.New(err.Error)
+	}
+	return toStruct(resp)
+}
+
+func (s *Server) RedeemBypassCode(ctx context.Context, in *structpb.Struct) (*structpb.Struct, error) {
+	fields := in.GetFields()
+	resp, err := s.usecase.RedeemBypassCode(ctx, fields["project_id"].GetStringValue(), fields["code"].GetStringValue())
+	if err != nil {
+		return nil, errors.New(err.Error)
+	}
+	return toStruct(resp)
+}
+
+func (s *Server) RecordBypassUsage(ctx context.Context, in *structpb.Struct) (*structpb.Struct, error) {
+	err := s.usecase.RecordBypassUsage(ctx, in.GetFields()["rule_id"].GetStringValue())
+	if err != nil {
+		return nil, errors.New(err.Error)
+	}
+	return &structpb.Struct{}, nil
+}
@@ -2,10 +2,17 @@ package configuration
 
 import (
 	"antrein/bc-dashboard/internal/usecase/configuration"
+	"antrein/bc-dashboard/model/dto"
 	"context"
+	"encoding/json"
 	"errors"
+	"strconv"
+	"strings"
+	"time"
 
 	pb "github.com/antrein/proto-repository/pb/bc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
@@ -20,15 +27,59 @@ func New(usecase *configuration.Usecase) *Server {
 	}
 }
 
+// configHeader carries the settings that ProjectConfigResponse has no field
+// for yet, because the message lives in the shared proto repository. Structured
+// values are JSON encoded under -bin keys. The response threshold is the
+// effective one, the configured threshold is sent alongside it.
+func configHeader(resp *dto.ProjectConfig) (metadata.MD, error) {
+	header := metadata.Pairs("operational-state", resp.OperationalState)
+	header.Set("configured-threshold", strconv.Itoa(resp.Threshold))
+	if resp.AutoResumeAt != nil {
+		header.Set("auto-resume-at", resp.AutoResumeAt.Format(time.RFC3339))
+	}
+	header.Set("queue-mode", resp.QueueMode)
+	if resp.PreQueueOpenAt != nil {
+		header.Set("pre-queue-open-at", resp.PreQueueOpenAt.Format(time.RFC3339))
+	}
+	if resp.LotterySeed != "" {
+		header.Set("lottery-seed", resp.LotterySeed)
+	}
+	if len(resp.Locales) > 0 {
+		header.Set("locales", strings.Join(resp.Locales, ","))
+		pages, err := json.Marshal(resp.LocalizedPages)
+		if err != nil {
+			return nil, err
+		}
+		header.Set("localized-pages-bin", string(pages))
+	}
+	if len(resp.PriorityTiers) > 0 {
+		tiers, err := json.Marshal(resp.PriorityTiers)
+		if err != nil {
+			return nil, err
+		}
+		header.Set("priority-tiers-bin", string(tiers))
+	}
+	return header, nil
+}
+
 func (s *Server) GetProjectConfig(ctx context.Context, in *pb.ConfigRequest) (*pb.ProjectConfigResponse, error) {
 	projectID := in.GetProjectId()
 	resp, err := s.usecase.GetProjectConfigByID(ctx, projectID)
 	if err != nil {
 		return nil, errors.New(err.Error)
 	}
+
+	header, errHeader := configHeader(resp)
+	if errHeader != nil {
+		return nil, errHeader
+	}
+	if errHeader = grpc.SetHeader(ctx, header); errHeader != nil {
+		return nil, errHeader
+	}
+
 	return &pb.ProjectConfigResponse{
 		ProjectId:       projectID,
-		Threshold:       int32(resp.Threshold),
+		Threshold:       int32(resp.EffectiveThreshold),
 		SessionTime:     int32(resp.SessionTime),
 		Host:            resp.Host,
 		BaseUrl:         resp.BaseURL,
@@ -30,6 +30,7 @@ func New(cfg *config.Config, usecase *auth.Usecase, vld *validator.Validate) *Ro
 func (r *Router) RegisterRoute(app *mux.Router) {
 	app.HandleFunc("/bc/dashboard/auth/register", guard.DefaultGuard(r.RegisterTenant))
 	app.HandleFunc("/bc/dashboard/auth/login", guard.DefaultGuard(r.LoginTenantAccount))
+	app.HandleFunc("/bc/dashboard/tenant/html-policy", guard.AuthGuard(r.cfg, r.HTMLPolicy))
 }
 
 func (r *Router) RegisterTenant(g *guard.GuardContext) error {
@@ -90,3 +91,40 @@ func (r *Router) LoginTenantAccount(g *guard.GuardContext) error {
 
 	return g.ReturnSuccess(resp)
 }
+
+func (r *Router) HTMLPolicy(g *guard.AuthGuardContext) error {
+	ctx := context.Background()
+	tenantID := g.Claims.UserID
+
+	if guard.IsMethod(g.Request, "GET") {
+		resp, errRes := r.usecase.GetHTMLPolicy(ctx, tenantID)
+		if errRes != nil {
+			return g.ReturnError(errRes.Status, errRes.Error)
+		}
+		return g.ReturnSuccess(resp)
+	}
+
+	ok := guard.IsMethod(g.Request, "PUT")
+	if !ok {
+		return g.ReturnError(http.StatusMethodNotAllowed, "Method not allowed")
+	}
+
+	req := dto.UpdateHTMLPolicyRequest{}
+
+	err := guard.BodyParser(g.Request, &req)
+	if err != nil {
+		return g.ReturnError(http.StatusBadRequest, "Request tidak sesuai format")
+	}
+
+	err = r.vld.StructCtx(ctx, &req)
+	if err != nil {
+		return g.ReturnError(http.StatusBadRequest, "Request tidak sesuai format")
+	}
+
+	errRes := r.usecase.UpdateHTMLPolicy(ctx, req, tenantID)
+	if errRes != nil {
+		return g.ReturnError(errRes.Status, errRes.Error)
+	}
+
+	return g.ReturnSuccess("Berhasil mengupdate kebijakan HTML")
+}
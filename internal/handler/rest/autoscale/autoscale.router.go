@@ -0,0 +1,78 @@
+package autoscale
+
+import (
+	guard "antrein/bc-dashboard/application/middleware"
+	"antrein/bc-dashboard/internal/usecase/autoscale"
+	"antrein/bc-dashboard/model/config"
+	"antrein/bc-dashboard/model/dto"
+	"context"
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gorilla/mux"
+)
+
+type Router struct {
+	cfg     *config.Config
+	usecase *autoscale.Usecase
+	vld     *validator.Validate
+}
+
+func New(cfg *config.Config, usecase *autoscale.Usecase, vld *validator.Validate) *Router {
+	return &Router{
+		cfg:     cfg,
+		usecase: usecase,
+		vld:     vld,
+	}
+}
+
+func (r *Router) RegisterRoute(app *mux.Router) {
+	app.HandleFunc("/bc/dashboard/project/autoscale", guard.AuthGuard(r.cfg, r.UpdateAutoscalePolicy))
+	app.HandleFunc("/bc/dashboard/project/autoscale/{id}", guard.AuthGuard(r.cfg, r.GetAutoscalePolicy))
+}
+
+func (r *Router) UpdateAutoscalePolicy(g *guard.AuthGuardContext) error {
+	ok := guard.IsMethod(g.Request, "PUT")
+	if !ok {
+		return g.ReturnError(http.StatusMethodNotAllowed, "Method not allowed")
+	}
+
+	req := dto.UpdateAutoscalePolicy{}
+
+	err := guard.BodyParser(g.Request, &req)
+	if err != nil {
+		return g.ReturnError(http.StatusBadRequest, "Request tidak sesuai format")
+	}
+
+	ctx := context.Background()
+
+	err = r.vld.StructCtx(ctx, &req)
+	if err != nil {
+		return g.ReturnError(http.StatusBadRequest, "Request tidak sesuai format")
+	}
+
+	tenantID := g.Claims.UserID
+	errRes := r.usecase.UpdateAutoscalePolicy(ctx, req, tenantID)
+	if errRes != nil {
+		return g.ReturnError(errRes.Status, errRes.Error)
+	}
+
+	return g.ReturnSuccess("Berhasil mengupdate kebijakan autoscale")
+}
+
+func (r *Router) GetAutoscalePolicy(g *guard.AuthGuardContext) error {
+	ok := guard.IsMethod(g.Request, "GET")
+	if !ok {
+		return g.ReturnError(http.StatusMethodNotAllowed, "Method not allowed")
+	}
+
+	projectID := guard.GetParam(g.Request, "id")
+	ctx := context.Background()
+	tenantID := g.Claims.UserID
+	resp, errRes := r.usecase.GetAutoscalePolicy(ctx, projectID, tenantID)
+	if errRes != nil {
+		return g.ReturnError(errRes.Status, errRes.Error)
+	}
+
+	return g.ReturnSuccess(resp)
+}
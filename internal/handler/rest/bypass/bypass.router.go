@@ -0,0 +1,144 @@
+package bypass
+
+import (
+	guard "antrein/bc-dashboard/application/middleware"
+	"antrein/bc-dashboard/internal/usecase/bypass"
+	validate "antrein/bc-dashboard/internal/utils/validator"
+	"antrein/bc-dashboard/model/config"
+	"antrein/bc-dashboard/model/dto"
+	"context"
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gorilla/mux"
+)
+
+type Router struct {
+	cfg     *config.Config
+	usecase *bypass.Usecase
+	vld     *validator.Validate
+}
+
+func New(cfg *config.Config, usecase *bypass.Usecase, vld *validator.Validate) *Router {
+	return &Router{
+		cfg:     cfg,
+		usecase: usecase,
+		vld:     vld,
+	}
+}
+
+func (r *Router) RegisterRoute(app *mux.Router) {
+	app.HandleFunc("/bc/dashboard/project/bypass", guard.AuthGuard(r.cfg, r.CreateBypassRule))
+	app.HandleFunc("/bc/dashboard/project/bypass/list/{id}", guard.AuthGuard(r.cfg, r.GetListBypassRules))
+	app.HandleFunc("/bc/dashboard/project/bypass/{id}", guard.AuthGuard(r.cfg, r.UpdateOrDeleteBypassRule))
+}
+
+func (r *Router) CreateBypassRule(g *guard.AuthGuardContext) error {
+	ok := guard.IsMethod(g.Request, "POST")
+	if !ok {
+		return g.ReturnError(http.StatusMethodNotAllowed, "Method not allowed")
+	}
+
+	req := dto.CreateBypassRuleRequest{}
+
+	err := guard.BodyParser(g.Request, &req)
+	if err != nil {
+		return g.ReturnError(http.StatusBadRequest, "Request tidak sesuai format")
+	}
+
+	ctx := context.Background()
+
+	err = r.vld.StructCtx(ctx, &req)
+	if err != nil {
+		return g.ReturnError(http.StatusBadRequest, "Request tidak sesuai format")
+	}
+
+	err = validate.ValidateCreateBypassRule(req)
+	if err != nil {
+		return g.ReturnError(http.StatusBadRequest, err.Error())
+	}
+
+	tenantID := g.Claims.UserID
+	resp, errRes := r.usecase.CreateBypassRule(ctx, req, tenantID)
+	if errRes != nil {
+		return g.ReturnError(errRes.Status, errRes.Error)
+	}
+
+	return g.ReturnCreated(resp)
+}
+
+func (r *Router) GetListBypassRules(g *guard.AuthGuardContext) error {
+	ok := guard.IsMethod(g.Request, "GET")
+	if !ok {
+		return g.ReturnError(http.StatusMethodNotAllowed, "Method not allowed")
+	}
+
+	projectID := guard.GetParam(g.Request, "id")
+	ctx := context.Background()
+	tenantID := g.Claims.UserID
+	resp, errRes := r.usecase.GetListBypassRule(ctx, projectID, tenantID)
+	if errRes != nil {
+		return g.ReturnError(errRes.Status, errRes.Error)
+	}
+
+	return g.ReturnSuccess(resp)
+}
+
+func (r *Router) UpdateOrDeleteBypassRule(g *guard.AuthGuardContext) error {
+	if guard.IsMethod(g.Request, "DELETE") {
+		return r.DeleteBypassRule(g)
+	}
+	return r.UpdateBypassRule(g)
+}
+
+func (r *Router) UpdateBypassRule(g *guard.AuthGuardContext) error {
+	ok := guard.IsMethod(g.Request, "PUT")
+	if !ok {
+		return g.ReturnError(http.StatusMethodNotAllowed, "Method not allowed")
+	}
+
+	req := dto.UpdateBypassRuleRequest{}
+
+	err := guard.BodyParser(g.Request, &req)
+	if err != nil {
+		return g.ReturnError(http.StatusBadRequest, "Request tidak sesuai format")
+	}
+
+	ctx := context.Background()
+
+	err = r.vld.StructCtx(ctx, &req)
+	if err != nil {
+		return g.ReturnError(http.StatusBadRequest, "Request tidak sesuai format")
+	}
+
+	err = validate.ValidateUpdateBypassRule(req)
+	if err != nil {
+		return g.ReturnError(http.StatusBadRequest, err.Error())
+	}
+
+	ruleID := guard.GetParam(g.Request, "id")
+	tenantID := g.Claims.UserID
+	resp, errRes := r.usecase.UpdateBypassRule(ctx, ruleID, req, tenantID)
+	if errRes != nil {
+		return g.ReturnError(errRes.Status, errRes.Error)
+	}
+
+	return g.ReturnSuccess(resp)
+}
+
+func (r *Router) DeleteBypassRule(g *guard.AuthGuardContext) error {
+	ok := guard.IsMethod(g.Request, "DELETE")
+	if !ok {
+		return g.ReturnError(http.StatusMethodNotAllowed, "Method not allowed")
+	}
+
+	ruleID := guard.GetParam(g.Request, "id")
+	ctx := context.Background()
+	tenantID := g.Claims.UserID
+	errRes := r.usecase.DeleteBypassRule(ctx, ruleID, tenantID)
+	if errRes != nil {
+		return g.ReturnError(errRes.Status, errRes.Error)
+	}
+
+	return g.ReturnSuccess("Berhasil menghapus aturan bypass")
+}
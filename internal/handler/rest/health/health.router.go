@@ -0,0 +1,112 @@
+package health
+
+import (
+	guard "antrein/bc-dashboard/application/middleware"
+	"antrein/bc-dashboard/internal/usecase/health"
+	"antrein/bc-dashboard/model/config"
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gorilla/mux"
+)
+
+type Router struct {
+	cfg     *config.Config
+	usecase *health.Usecase
+	vld     *validator.Validate
+}
+
+func New(cfg *config.Config, usecase *health.Usecase, vld *validator.Validate) *Router {
+	return &Router{
+		cfg:     cfg,
+		usecase: usecase,
+		vld:     vld,
+	}
+}
+
+func (r *Router) RegisterRoute(app *mux.Router) {
+	app.HandleFunc("/bc/dashboard/project/health/uptime/{id}", guard.AuthGuard(r.cfg, r.GetUptime))
+	app.HandleFunc("/bc/dashboard/project/health/incident/list/{id}", guard.AuthGuard(r.cfg, r.GetIncidents))
+	app.HandleFunc("/bc/dashboard/project/health/timeline/{id}", guard.AuthGuard(r.cfg, r.GetTimeline))
+}
+
+func parseIntQuery(req *http.Request, key string, def, min, max int) (int, error) {
+	val := req.URL.Query().Get(key)
+	if val == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(val)
+	if err != nil || n < min || n > max {
+		return 0, fmt.Errorf("Parameter %s harus di antara %d sampai %d", key, min, max)
+	}
+	return n, nil
+}
+
+func (r *Router) GetUptime(g *guard.AuthGuardContext) error {
+	ok := guard.IsMethod(g.Request, "GET")
+	if !ok {
+		return g.ReturnError(http.StatusMethodNotAllowed, "Method not allowed")
+	}
+
+	days, err := parseIntQuery(g.Request, "days", 7, 1, 90)
+	if err != nil {
+		return g.ReturnError(http.StatusBadRequest, err.Error())
+	}
+
+	projectID := guard.GetParam(g.Request, "id")
+	ctx := context.Background()
+	tenantID := g.Claims.UserID
+	resp, errRes := r.usecase.GetUptime(ctx, projectID, tenantID, days)
+	if errRes != nil {
+		return g.ReturnError(errRes.Status, errRes.Error)
+	}
+
+	return g.ReturnSuccess(resp)
+}
+
+func (r *Router) GetIncidents(g *guard.AuthGuardContext) error {
+	ok := guard.IsMethod(g.Request, "GET")
+	if !ok {
+		return g.ReturnError(http.StatusMethodNotAllowed, "Method not allowed")
+	}
+
+	days, err := parseIntQuery(g.Request, "days", 30, 1, 90)
+	if err != nil {
+		return g.ReturnError(http.StatusBadRequest, err.Error())
+	}
+
+	projectID := guard.GetParam(g.Request, "id")
+	ctx := context.Background()
+	tenantID := g.Claims.UserID
+	resp, errRes := r.usecase.GetIncidents(ctx, projectID, tenantID, days)
+	if errRes != nil {
+		return g.ReturnError(errRes.Status, errRes.Error)
+	}
+
+	return g.ReturnSuccess(resp)
+}
+
+func (r *Router) GetTimeline(g *guard.AuthGuardContext) error {
+	ok := guard.IsMethod(g.Request, "GET")
+	if !ok {
+		return g.ReturnError(http.StatusMethodNotAllowed, "Method not allowed")
+	}
+
+	limit, err := parseIntQuery(g.Request, "limit", 100, 1, 1000)
+	if err != nil {
+		return g.ReturnError(http.StatusBadRequest, err.Error())
+	}
+
+	projectID := guard.GetParam(g.Request, "id")
+	ctx := context.Background()
+	tenantID := g.Claims.UserID
+	resp, errRes := r.usecase.GetTimeline(ctx, projectID, tenantID, limit)
+	if errRes != nil {
+		return g.ReturnError(errRes.Status, errRes.Error)
+	}
+
+	return g.ReturnSuccess(resp)
+}
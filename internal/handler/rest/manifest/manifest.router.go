@@ -0,0 +1,128 @@
+package manifest
+
+import (
+	guard "antrein/bc-dashboard/application/middleware"
+	"antrein/bc-dashboard/internal/usecase/manifest"
+	validate "antrein/bc-dashboard/internal/utils/validator"
+	"antrein/bc-dashboard/model/config"
+	"antrein/bc-dashboard/model/dto"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gorilla/mux"
+	"gopkg.in/yaml.v3"
+)
+
+type Router struct {
+	cfg     *config.Config
+	usecase *manifest.Usecase
+	vld     *validator.Validate
+}
+
+func New(cfg *config.Config, usecase *manifest.Usecase, vld *validator.Validate) *Router {
+	return &Router{
+		cfg:     cfg,
+		usecase: usecase,
+		vld:     vld,
+	}
+}
+
+func (r *Router) RegisterRoute(app *mux.Router) {
+	app.HandleFunc("/bc/dashboard/project/import", guard.AuthGuard(r.cfg, r.ImportProject)).Methods("POST")
+	app.HandleFunc("/bc/dashboard/project/{id}/export", guard.AuthGuard(r.cfg, r.ExportProject)).Methods("GET")
+}
+
+func isYAML(req *http.Request) bool {
+	format := req.URL.Query().Get("format")
+	if format != "" {
+		return format == "yaml" || format == "yml"
+	}
+	return strings.Contains(req.Header.Get("Content-Type"), "yaml")
+}
+
+func (r *Router) ExportProject(g *guard.AuthGuardContext) error {
+	ok := guard.IsMethod(g.Request, "GET")
+	if !ok {
+		return g.ReturnError(http.StatusMethodNotAllowed, "Method not allowed")
+	}
+
+	projectID := guard.GetParam(g.Request, "id")
+	ctx := context.Background()
+	tenantID := g.Claims.UserID
+	resp, errRes := r.usecase.ExportProject(ctx, projectID, tenantID)
+	if errRes != nil {
+		return g.ReturnError(errRes.Status, errRes.Error)
+	}
+
+	var body []byte
+	var err error
+	ext := "json"
+	if isYAML(g.Request) {
+		ext = "yaml"
+		body, err = yaml.Marshal(resp)
+		g.ResponseWriter.Header().Set("Content-Type", "application/yaml")
+	} else {
+		body, err = json.MarshalIndent(resp, "", "  ")
+		g.ResponseWriter.Header().Set("Content-Type", "application/json")
+	}
+	if err != nil {
+		return g.ReturnError(http.StatusInternalServerError, "Gagal export project")
+	}
+
+	g.ResponseWriter.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s.%s", projectID, ext))
+	g.ResponseWriter.WriteHeader(http.StatusOK)
+	_, err = g.ResponseWriter.Write(body)
+	return err
+}
+
+func (r *Router) ImportProject(g *guard.AuthGuardContext) error {
+	ok := guard.IsMethod(g.Request, "POST")
+	if !ok {
+		return g.ReturnError(http.StatusMethodNotAllowed, "Method not allowed")
+	}
+
+	defer g.Request.Body.Close()
+	body, err := io.ReadAll(g.Request.Body)
+	if err != nil {
+		return g.ReturnError(http.StatusBadRequest, "Request tidak sesuai format")
+	}
+
+	req := dto.ProjectManifest{}
+	if isYAML(g.Request) {
+		err = yaml.Unmarshal(body, &req)
+	} else {
+		err = json.Unmarshal(body, &req)
+	}
+	if err != nil {
+		return g.ReturnError(http.StatusBadRequest, "Request tidak sesuai format")
+	}
+
+	ctx := context.Background()
+
+	err = r.vld.StructCtx(ctx, &req)
+	if err != nil {
+		return g.ReturnError(http.StatusBadRequest, "Request tidak sesuai format")
+	}
+
+	err = validate.ValidateCreateProject(dto.CreateProjectRequest{
+		ID:   req.Metadata.ID,
+		Name: req.Metadata.Name,
+	})
+	if err != nil {
+		return g.ReturnError(http.StatusBadRequest, err.Error())
+	}
+
+	dryRun := g.Request.URL.Query().Get("dry_run") == "true"
+	tenantID := g.Claims.UserID
+	resp, errRes := r.usecase.ImportProject(ctx, req, tenantID, dryRun)
+	if errRes != nil {
+		return g.ReturnError(errRes.Status, errRes.Error)
+	}
+
+	return g.ReturnSuccess(resp)
+}
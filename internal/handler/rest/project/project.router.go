@@ -4,41 +4,66 @@ import (
 	guard "antrein/bc-dashboard/application/middleware"
 	"antrein/bc-dashboard/internal/usecase/configuration"
 	"antrein/bc-dashboard/internal/usecase/project"
+	"antrein/bc-dashboard/internal/usecase/provisioning"
 	validate "antrein/bc-dashboard/internal/utils/validator"
 	"antrein/bc-dashboard/model/config"
 	"antrein/bc-dashboard/model/dto"
 	"context"
+	"errors"
+	"log"
 	"mime/multipart"
 	"net/http"
+	"strconv"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/gorilla/mux"
 )
 
 type Router struct {
-	cfg           *config.Config
-	usecase       *project.Usecase
-	configUsecase *configuration.Usecase
-	vld           *validator.Validate
+	cfg                 *config.Config
+	usecase             *project.Usecase
+	configUsecase       *configuration.Usecase
+	provisioningUsecase *provisioning.Usecase
+	vld                 *validator.Validate
 }
 
-func New(cfg *config.Config, usecase *project.Usecase, configUsecase *configuration.Usecase, vld *validator.Validate) *Router {
+func New(cfg *config.Config, usecase *project.Usecase, configUsecase *configuration.Usecase, provisioningUsecase *provisioning.Usecase, vld *validator.Validate) *Router {
 	return &Router{
-		cfg:           cfg,
-		usecase:       usecase,
-		configUsecase: configUsecase,
-		vld:           vld,
+		cfg:                 cfg,
+		usecase:             usecase,
+		configUsecase:       configUsecase,
+		provisioningUsecase: provisioningUsecase,
+		vld:                 vld,
 	}
 }
 
 func (r *Router) RegisterRoute(app *mux.Router) {
-	app.HandleFunc("/bc/dashboard/project/list", guard.AuthGuard(r.cfg, r.GetListProjects))
-	app.HandleFunc("/bc/dashboard/project/health/{id}", guard.AuthGuard(r.cfg, r.CheckHealthProject))
-	app.HandleFunc("/bc/dashboard/project/detail/{id}", guard.AuthGuard(r.cfg, r.GetProjectDetail))
-	app.HandleFunc("/bc/dashboard/project", guard.AuthGuard(r.cfg, r.CreateProject))
-	app.HandleFunc("/bc/dashboard/project/config", guard.AuthGuard(r.cfg, r.UpdateProjectConfig))
-	app.HandleFunc("/bc/dashboard/project/style", guard.AuthGuard(r.cfg, r.UpdateProjectStyle))
-	app.HandleFunc("/bc/dashboard/project/clear", guard.DefaultGuard(r.ClearAllProjects))
+	app.HandleFunc("/bc/dashboard/project/list", guard.AuthGuard(r.cfg, r.GetListProjects)).Methods("GET")
+	app.HandleFunc("/bc/dashboard/project/health", guard.AuthGuard(r.cfg, r.CheckHealthProjects)).Methods("GET")
+	app.HandleFunc("/bc/dashboard/project/health/{id}", guard.AuthGuard(r.cfg, r.CheckHealthProject)).Methods("GET")
+	app.HandleFunc("/bc/dashboard/project/detail/{id}", guard.AuthGuard(r.cfg, r.GetProjectDetail)).Methods("GET")
+	app.HandleFunc("/bc/dashboard/project", guard.AuthGuard(r.cfg, r.CreateProject)).Methods("POST")
+	app.HandleFunc("/bc/dashboard/project/config", guard.AuthGuard(r.cfg, r.UpdateProjectConfig)).Methods("PUT")
+	app.HandleFunc("/bc/dashboard/project/style", guard.AuthGuard(r.cfg, r.UpdateProjectStyle)).Methods("PUT")
+	app.HandleFunc("/bc/dashboard/project/state", guard.AuthGuard(r.cfg, r.UpdateProjectState)).Methods("PUT")
+	app.HandleFunc("/bc/dashboard/project/tier", guard.AuthGuard(r.cfg, r.UpdatePriorityTiers)).Methods("PUT")
+	app.HandleFunc("/bc/dashboard/project/tier/{id}", guard.AuthGuard(r.cfg, r.GetPriorityTiers)).Methods("GET")
+	app.HandleFunc("/bc/dashboard/project/page/rollback", guard.AuthGuard(r.cfg, r.RollbackPage)).Methods("PUT")
+	app.HandleFunc("/bc/dashboard/project/page/version/list/{id}", guard.AuthGuard(r.cfg, r.GetPageVersions)).Methods("GET")
+	app.HandleFunc("/bc/dashboard/project/locale", guard.AuthGuard(r.cfg, r.UpdateProjectLocale)).Methods("PUT")
+	app.HandleFunc("/bc/dashboard/project/locale/list/{id}", guard.AuthGuard(r.cfg, r.GetProjectLocales)).Methods("GET")
+	app.HandleFunc("/bc/dashboard/project/locale/{id}/{locale}", guard.AuthGuard(r.cfg, r.DeleteProjectLocale)).Methods("DELETE")
+	app.HandleFunc("/bc/dashboard/project/clear", guard.DefaultGuard(r.ClearAllProjects)).Methods("DELETE")
+	app.HandleFunc("/bc/dashboard/project/template", guard.AuthGuard(r.cfg, r.CreateProjectTemplate)).Methods("POST")
+	app.HandleFunc("/bc/dashboard/project/template/list", guard.AuthGuard(r.cfg, r.GetListProjectTemplates)).Methods("GET")
+	app.HandleFunc("/bc/dashboard/project/template/{id}", guard.AuthGuard(r.cfg, r.DeleteProjectTemplate)).Methods("DELETE")
+	app.HandleFunc("/bc/dashboard/project/history/{id}", guard.AuthGuard(r.cfg, r.GetProjectHistory)).Methods("GET")
+	app.HandleFunc("/bc/dashboard/project/{id}/clone", guard.AuthGuard(r.cfg, r.CloneProject)).Methods("POST")
+	app.HandleFunc("/bc/dashboard/project/{id}/style/preview", guard.AuthGuard(r.cfg, r.PreviewProjectStyle)).Methods("POST")
+	app.HandleFunc("/bc/dashboard/project/{id}/deployment", guard.AuthGuard(r.cfg, r.DeprovisionProject)).Methods("DELETE")
+	app.HandleFunc("/bc/dashboard/project/{id}/provisioning/stream", guard.AuthGuard(r.cfg, r.StreamProvisioning)).Methods("GET")
+	app.HandleFunc("/bc/dashboard/project/{id}", guard.AuthGuard(r.cfg, r.UpdateProject)).Methods("PATCH")
+	app.HandleFunc("/bc/dashboard/project/{id}", guard.AuthGuard(r.cfg, r.DeleteProject)).Methods("DELETE")
 }
 
 func (r *Router) CreateProject(g *guard.AuthGuardContext) error {
@@ -75,6 +100,148 @@ func (r *Router) CreateProject(g *guard.AuthGuardContext) error {
 	return g.ReturnCreated(resp)
 }
 
+func (r *Router) CloneProject(g *guard.AuthGuardContext) error {
+	ok := guard.IsMethod(g.Request, "POST")
+	if !ok {
+		return g.ReturnError(http.StatusMethodNotAllowed, "Method not allowed")
+	}
+
+	req := dto.CloneProjectRequest{}
+
+	err := guard.BodyParser(g.Request, &req)
+	if err != nil {
+		return g.ReturnError(http.StatusBadRequest, "Request tidak sesuai format")
+	}
+
+	ctx := context.Background()
+
+	err = r.vld.StructCtx(ctx, &req)
+	if err != nil {
+		return g.ReturnError(http.StatusBadRequest, "Request tidak sesuai format")
+	}
+
+	err = validate.ValidateCloneProject(req)
+	if err != nil {
+		return g.ReturnError(http.StatusBadRequest, err.Error())
+	}
+
+	sourceID := guard.GetParam(g.Request, "id")
+	userID := g.Claims.UserID
+	resp, errRes := r.usecase.CloneProject(ctx, sourceID, req, userID)
+	if errRes != nil {
+		return g.ReturnError(errRes.Status, errRes.Error)
+	}
+
+	return g.ReturnCreated(resp)
+}
+
+func (r *Router) DeprovisionProject(g *guard.AuthGuardContext) error {
+	ok := guard.IsMethod(g.Request, "DELETE")
+	if !ok {
+		return g.ReturnError(http.StatusMethodNotAllowed, "Method not allowed")
+	}
+
+	ctx := context.Background()
+	projectID := guard.GetParam(g.Request, "id")
+	userID := g.Claims.UserID
+	resp, errRes := r.usecase.DeprovisionProject(ctx, projectID, userID)
+	if errRes != nil {
+		return g.ReturnError(errRes.Status, errRes.Error)
+	}
+
+	return g.ReturnSuccess(resp)
+}
+
+// StreamProvisioning sends the provisioning status of the project as server
+// sent events, first the current one and then every transition, until the
+// client goes away.
+func (r *Router) StreamProvisioning(g *guard.AuthGuardContext) error {
+	ok := guard.IsMethod(g.Request, "GET")
+	if !ok {
+		return g.ReturnError(http.StatusMethodNotAllowed, "Method not allowed")
+	}
+
+	ctx := g.Request.Context()
+	projectID := guard.GetParam(g.Request, "id")
+	userID := g.Claims.UserID
+
+	updates, cancel := r.provisioningUsecase.Subscribe(projectID)
+	defer cancel()
+
+	status, errRes := r.usecase.GetProvisioningStatus(ctx, projectID, userID)
+	if errRes != nil {
+		return g.ReturnError(errRes.Status, errRes.Error)
+	}
+
+	g.ResponseWriter.Header().Set("Content-Type", "text/event-stream")
+	g.ResponseWriter.Header().Set("Cache-Control", "no-cache")
+	g.ResponseWriter.Header().Set("Connection", "keep-alive")
+
+	err := g.ReturnEvent(status)
+	for err == nil {
+		select {
+		case <-ctx.Done():
+			return nil
+		case update := <-updates:
+			err = g.ReturnEvent(update)
+		}
+	}
+	log.Println("Error gagal mengirim status provisioning", projectID, err)
+	return nil
+}
+
+func (r *Router) UpdateProject(g *guard.AuthGuardContext) error {
+	ok := guard.IsMethod(g.Request, "PATCH")
+	if !ok {
+		return g.ReturnError(http.StatusMethodNotAllowed, "Method not allowed")
+	}
+
+	req := dto.UpdateProjectRequest{}
+
+	err := guard.BodyParser(g.Request, &req)
+	if err != nil {
+		return g.ReturnError(http.StatusBadRequest, "Request tidak sesuai format")
+	}
+
+	ctx := context.Background()
+
+	err = r.vld.StructCtx(ctx, &req)
+	if err != nil {
+		return g.ReturnError(http.StatusBadRequest, "Request tidak sesuai format")
+	}
+
+	err = validate.ValidateUpdateProject(req)
+	if err != nil {
+		return g.ReturnError(http.StatusBadRequest, err.Error())
+	}
+
+	projectID := guard.GetParam(g.Request, "id")
+	tenantID := g.Claims.UserID
+	resp, errRes := r.usecase.UpdateProject(ctx, projectID, req, tenantID)
+	if errRes != nil {
+		return g.ReturnError(errRes.Status, errRes.Error)
+	}
+
+	return g.ReturnSuccess(resp)
+}
+
+func (r *Router) DeleteProject(g *guard.AuthGuardContext) error {
+	ok := guard.IsMethod(g.Request, "DELETE")
+	if !ok {
+		return g.ReturnError(http.StatusMethodNotAllowed, "Method not allowed")
+	}
+
+	ctx := context.Background()
+	projectID := guard.GetParam(g.Request, "id")
+	tenantID := g.Claims.UserID
+	errRes := r.usecase.DeleteProject(ctx, projectID, tenantID)
+	if errRes != nil {
+		return g.ReturnError(errRes.Status, errRes.Error)
+	}
+
+	return g.ReturnSuccess("Berhasil menghapus project")
+}
+
 func (r *Router) UpdateProjectConfig(g *guard.AuthGuardContext) error {
 	ok := guard.IsMethod(g.Request, "PUT")
 	if !ok {
@@ -103,6 +270,196 @@ func (r *Router) UpdateProjectConfig(g *guard.AuthGuardContext) error {
 	return g.ReturnSuccess("Berhasil mengupdate konfigurasi project")
 }
 
+func (r *Router) UpdateProjectState(g *guard.AuthGuardContext) error {
+	ok := guard.IsMethod(g.Request, "PUT")
+	if !ok {
+		return g.ReturnError(http.StatusMethodNotAllowed, "Method not allowed")
+	}
+
+	req := dto.UpdateProjectState{}
+
+	err := guard.BodyParser(g.Request, &req)
+	if err != nil {
+		return g.ReturnError(http.StatusBadRequest, "Request tidak sesuai format")
+	}
+
+	ctx := context.Background()
+
+	err = r.vld.StructCtx(ctx, &req)
+	if err != nil {
+		return g.ReturnError(http.StatusBadRequest, "Request tidak sesuai format")
+	}
+
+	tenantID := g.Claims.UserID
+	errRes := r.configUsecase.UpdateProjectState(ctx, req, tenantID)
+	if errRes != nil {
+		return g.ReturnError(errRes.Status, errRes.Error)
+	}
+
+	return g.ReturnSuccess("Berhasil mengubah status project")
+}
+
+func (r *Router) UpdatePriorityTiers(g *guard.AuthGuardContext) error {
+	ok := guard.IsMethod(g.Request, "PUT")
+	if !ok {
+		return g.ReturnError(http.StatusMethodNotAllowed, "Method not allowed")
+	}
+
+	req := dto.UpdatePriorityTiers{}
+
+	err := guard.BodyParser(g.Request, &req)
+	if err != nil {
+		return g.ReturnError(http.StatusBadRequest, "Request tidak sesuai format")
+	}
+
+	ctx := context.Background()
+
+	err = r.vld.StructCtx(ctx, &req)
+	if err != nil {
+		return g.ReturnError(http.StatusBadRequest, "Request tidak sesuai format")
+	}
+
+	tenantID := g.Claims.UserID
+	errRes := r.configUsecase.UpdatePriorityTiers(ctx, req, tenantID)
+	if errRes != nil {
+		return g.ReturnError(errRes.Status, errRes.Error)
+	}
+
+	return g.ReturnSuccess("Berhasil mengupdate tier prioritas")
+}
+
+func (r *Router) GetPriorityTiers(g *guard.AuthGuardContext) error {
+	ok := guard.IsMethod(g.Request, "GET")
+	if !ok {
+		return g.ReturnError(http.StatusMethodNotAllowed, "Method not allowed")
+	}
+
+	projectID := guard.GetParam(g.Request, "id")
+	ctx := context.Background()
+	tenantID := g.Claims.UserID
+	resp, errRes := r.configUsecase.GetPriorityTiers(ctx, projectID, tenantID)
+	if errRes != nil {
+		return g.ReturnError(errRes.Status, errRes.Error)
+	}
+
+	return g.ReturnSuccess(resp)
+}
+
+func (r *Router) GetPageVersions(g *guard.AuthGuardContext) error {
+	ok := guard.IsMethod(g.Request, "GET")
+	if !ok {
+		return g.ReturnError(http.StatusMethodNotAllowed, "Method not allowed")
+	}
+
+	projectID := guard.GetParam(g.Request, "id")
+	ctx := context.Background()
+	tenantID := g.Claims.UserID
+	resp, errRes := r.configUsecase.GetPageVersions(ctx, projectID, tenantID)
+	if errRes != nil {
+		return g.ReturnError(errRes.Status, errRes.Error)
+	}
+
+	return g.ReturnSuccess(resp)
+}
+
+func (r *Router) RollbackPage(g *guard.AuthGuardContext) error {
+	ok := guard.IsMethod(g.Request, "PUT")
+	if !ok {
+		return g.ReturnError(http.StatusMethodNotAllowed, "Method not allowed")
+	}
+
+	req := dto.RollbackPageRequest{}
+
+	err := guard.BodyParser(g.Request, &req)
+	if err != nil {
+		return g.ReturnError(http.StatusBadRequest, "Request tidak sesuai format")
+	}
+
+	ctx := context.Background()
+
+	err = r.vld.StructCtx(ctx, &req)
+	if err != nil || req.ProjectID == "" || req.VersionID == "" {
+		return g.ReturnError(http.StatusBadRequest, "Request tidak sesuai format")
+	}
+
+	tenantID := g.Claims.UserID
+	errRes := r.configUsecase.RollbackPage(ctx, req, tenantID)
+	if errRes != nil {
+		return g.ReturnError(errRes.Status, errRes.Error)
+	}
+
+	return g.ReturnSuccess("Berhasil mengembalikan versi halaman")
+}
+
+func (r *Router) UpdateProjectLocale(g *guard.AuthGuardContext) error {
+	ok := guard.IsMethod(g.Request, "PUT")
+	if !ok {
+		return g.ReturnError(http.StatusMethodNotAllowed, "Method not allowed")
+	}
+
+	req := dto.UpdateProjectLocaleRequest{}
+
+	err := guard.BodyParser(g.Request, &req)
+	if err != nil {
+		return g.ReturnError(http.StatusBadRequest, "Request tidak sesuai format")
+	}
+
+	ctx := context.Background()
+
+	err = r.vld.StructCtx(ctx, &req)
+	if err != nil {
+		return g.ReturnError(http.StatusBadRequest, "Request tidak sesuai format")
+	}
+
+	err = validate.ValidateUpdateProjectLocale(req)
+	if err != nil {
+		return g.ReturnError(http.StatusBadRequest, err.Error())
+	}
+
+	tenantID := g.Claims.UserID
+	errRes := r.configUsecase.UpdateProjectLocale(ctx, req, tenantID)
+	if errRes != nil {
+		return g.ReturnError(errRes.Status, errRes.Error)
+	}
+
+	return g.ReturnSuccess("Berhasil mengupdate bahasa project")
+}
+
+func (r *Router) GetProjectLocales(g *guard.AuthGuardContext) error {
+	ok := guard.IsMethod(g.Request, "GET")
+	if !ok {
+		return g.ReturnError(http.StatusMethodNotAllowed, "Method not allowed")
+	}
+
+	projectID := guard.GetParam(g.Request, "id")
+	ctx := context.Background()
+	tenantID := g.Claims.UserID
+	resp, errRes := r.configUsecase.GetProjectLocales(ctx, projectID, tenantID)
+	if errRes != nil {
+		return g.ReturnError(errRes.Status, errRes.Error)
+	}
+
+	return g.ReturnSuccess(resp)
+}
+
+func (r *Router) DeleteProjectLocale(g *guard.AuthGuardContext) error {
+	ok := guard.IsMethod(g.Request, "DELETE")
+	if !ok {
+		return g.ReturnError(http.StatusMethodNotAllowed, "Method not allowed")
+	}
+
+	projectID := guard.GetParam(g.Request, "id")
+	locale := guard.GetParam(g.Request, "locale")
+	ctx := context.Background()
+	tenantID := g.Claims.UserID
+	errRes := r.configUsecase.DeleteProjectLocale(ctx, projectID, locale, tenantID)
+	if errRes != nil {
+		return g.ReturnError(errRes.Status, errRes.Error)
+	}
+
+	return g.ReturnSuccess("Berhasil menghapus bahasa project")
+}
+
 func (r *Router) UpdateProjectStyle(g *guard.AuthGuardContext) error {
 	ok := guard.IsMethod(g.Request, "PUT")
 	if !ok {
@@ -159,12 +516,110 @@ func (r *Router) UpdateProjectStyle(g *guard.AuthGuardContext) error {
 		htmlFile = nil
 	}
 
-	errRes := r.configUsecase.UpdateProjectStyle(ctx, req, imageFile, htmlFile)
+	resp, errRes := r.configUsecase.UpdateProjectStyle(ctx, req, imageFile, htmlFile)
 	if errRes != nil {
 		return g.ReturnError(errRes.Status, errRes.Error)
 	}
 
-	return g.ReturnSuccess("Berhasil mengupdate tampilan project")
+	return g.ReturnSuccess(resp)
+}
+
+func (r *Router) PreviewProjectStyle(g *guard.AuthGuardContext) error {
+	ok := guard.IsMethod(g.Request, "POST")
+	if !ok {
+		return g.ReturnError(http.StatusMethodNotAllowed, "Method not allowed")
+	}
+
+	req := dto.UpdateProjectStyle{
+		ProjectID: guard.GetParam(g.Request, "id"),
+	}
+
+	err := g.Request.ParseMultipartForm(10 << 20)
+	if err != nil {
+		return g.ReturnError(http.StatusBadRequest, "Request tidak sesuai format")
+	}
+
+	form := g.Request.MultipartForm
+
+	if val, ok := form.Value["queue_page_style"]; ok && len(val) > 0 {
+		req.QueuePageStyle = val[0]
+	}
+
+	if val, ok := form.Value["queue_page_base_color"]; ok && len(val) > 0 {
+		req.QueuePageBaseColor = val[0]
+	}
+
+	if val, ok := form.Value["queue_page_title"]; ok && len(val) > 0 {
+		req.QueuePageTitle = val[0]
+	}
+
+	if val, ok := form.Value["queue_page_logo"]; ok && len(val) > 0 {
+		req.QueuePageLogo = val[0]
+	}
+
+	ctx := context.Background()
+
+	var imageFile *multipart.FileHeader
+	_, imageFile, err = g.Request.FormFile("image")
+	if err != nil {
+		if err != http.ErrMissingFile {
+			return g.ReturnError(http.StatusBadRequest, "Gagal mendapatkan file logo")
+		}
+		imageFile = nil
+	}
+
+	var htmlFile *multipart.FileHeader
+	_, htmlFile, err = g.Request.FormFile("file")
+	if err != nil {
+		if err != http.ErrMissingFile {
+			return g.ReturnError(http.StatusBadRequest, "Gagal mendapatkan file html")
+		}
+		htmlFile = nil
+	}
+
+	tenantID := g.Claims.UserID
+	resp, errRes := r.configUsecase.PreviewProjectStyle(ctx, req, tenantID, imageFile, htmlFile)
+	if errRes != nil {
+		return g.ReturnError(errRes.Status, errRes.Error)
+	}
+
+	return g.ReturnSuccess(resp)
+}
+
+func parseListProjectRequest(req *http.Request) (dto.ListProjectRequest, error) {
+	query := req.URL.Query()
+	listReq := dto.ListProjectRequest{
+		Page:      1,
+		PageSize:  10,
+		Search:    query.Get("search"),
+		Health:    query.Get("health"),
+		SortBy:    query.Get("sort_by"),
+		SortOrder: query.Get("sort_order"),
+	}
+
+	if val := query.Get("page"); val != "" {
+		page, err := strconv.Atoi(val)
+		if err != nil {
+			return listReq, errors.New("Parameter page tidak valid")
+		}
+		listReq.Page = page
+	}
+	if val := query.Get("page_size"); val != "" {
+		pageSize, err := strconv.Atoi(val)
+		if err != nil {
+			return listReq, errors.New("Parameter page_size tidak valid")
+		}
+		listReq.PageSize = pageSize
+	}
+	if val := query.Get("configured"); val != "" {
+		configured, err := strconv.ParseBool(val)
+		if err != nil {
+			return listReq, errors.New("Parameter configured tidak valid")
+		}
+		listReq.Configured = &configured
+	}
+
+	return listReq, validate.ValidateListProject(listReq)
 }
 
 func (r *Router) GetListProjects(g *guard.AuthGuardContext) error {
@@ -173,9 +628,14 @@ func (r *Router) GetListProjects(g *guard.AuthGuardContext) error {
 		return g.ReturnError(http.StatusMethodNotAllowed, "Method not allowed")
 	}
 
+	req, err := parseListProjectRequest(g.Request)
+	if err != nil {
+		return g.ReturnError(http.StatusBadRequest, err.Error())
+	}
+
 	ctx := context.Background()
 	tenantID := g.Claims.UserID
-	resp, errRes := r.usecase.GetListProject(ctx, tenantID)
+	resp, errRes := r.usecase.GetListProject(ctx, req, tenantID)
 	if errRes != nil {
 		return g.ReturnError(errRes.Status, errRes.Error)
 	}
@@ -200,6 +660,39 @@ func (r *Router) GetProjectDetail(g *guard.AuthGuardContext) error {
 	return g.ReturnSuccess(resp)
 }
 
+func (r *Router) GetProjectHistory(g *guard.AuthGuardContext) error {
+	ok := guard.IsMethod(g.Request, "GET")
+	if !ok {
+		return g.ReturnError(http.StatusMethodNotAllowed, "Method not allowed")
+	}
+
+	projectID := guard.GetParam(g.Request, "id")
+	ctx := context.Background()
+	tenantID := g.Claims.UserID
+	resp, errRes := r.usecase.GetProjectHistory(ctx, projectID, tenantID)
+	if errRes != nil {
+		return g.ReturnError(errRes.Status, errRes.Error)
+	}
+
+	return g.ReturnSuccess(resp)
+}
+
+func (r *Router) CheckHealthProjects(g *guard.AuthGuardContext) error {
+	ok := guard.IsMethod(g.Request, "GET")
+	if !ok {
+		return g.ReturnError(http.StatusMethodNotAllowed, "Method not allowed")
+	}
+
+	ctx := context.Background()
+	tenantID := g.Claims.UserID
+	resp, errRes := r.usecase.CheckHealthProjects(ctx, tenantID)
+	if errRes != nil {
+		return g.ReturnError(errRes.Status, errRes.Error)
+	}
+
+	return g.ReturnSuccess(resp)
+}
+
 func (r *Router) CheckHealthProject(g *guard.AuthGuardContext) error {
 	ok := guard.IsMethod(g.Request, "GET")
 	if !ok {
@@ -230,3 +723,70 @@ func (r *Router) ClearAllProjects(g *guard.GuardContext) error {
 
 	return g.ReturnSuccess("Berhasil clear semua project")
 }
+
+func (r *Router) CreateProjectTemplate(g *guard.AuthGuardContext) error {
+	ok := guard.IsMethod(g.Request, "POST")
+	if !ok {
+		return g.ReturnError(http.StatusMethodNotAllowed, "Method not allowed")
+	}
+
+	req := dto.CreateProjectTemplateRequest{}
+
+	err := guard.BodyParser(g.Request, &req)
+	if err != nil {
+		return g.ReturnError(http.StatusBadRequest, "Request tidak sesuai format")
+	}
+
+	ctx := context.Background()
+
+	err = r.vld.StructCtx(ctx, &req)
+	if err != nil {
+		return g.ReturnError(http.StatusBadRequest, "Request tidak sesuai format")
+	}
+
+	err = validate.ValidateCreateProjectTemplate(req)
+	if err != nil {
+		return g.ReturnError(http.StatusBadRequest, err.Error())
+	}
+
+	tenantID := g.Claims.UserID
+	resp, errRes := r.usecase.CreateProjectTemplate(ctx, req, tenantID)
+	if errRes != nil {
+		return g.ReturnError(errRes.Status, errRes.Error)
+	}
+
+	return g.ReturnCreated(resp)
+}
+
+func (r *Router) GetListProjectTemplates(g *guard.AuthGuardContext) error {
+	ok := guard.IsMethod(g.Request, "GET")
+	if !ok {
+		return g.ReturnError(http.StatusMethodNotAllowed, "Method not allowed")
+	}
+
+	ctx := context.Background()
+	tenantID := g.Claims.UserID
+	resp, errRes := r.usecase.GetListProjectTemplate(ctx, tenantID)
+	if errRes != nil {
+		return g.ReturnError(errRes.Status, errRes.Error)
+	}
+
+	return g.ReturnSuccess(resp)
+}
+
+func (r *Router) DeleteProjectTemplate(g *guard.AuthGuardContext) error {
+	ok := guard.IsMethod(g.Request, "DELETE")
+	if !ok {
+		return g.ReturnError(http.StatusMethodNotAllowed, "Method not allowed")
+	}
+
+	templateID := guard.GetParam(g.Request, "id")
+	ctx := context.Background()
+	tenantID := g.Claims.UserID
+	errRes := r.usecase.DeleteProjectTemplate(ctx, templateID, tenantID)
+	if errRes != nil {
+		return g.ReturnError(errRes.Status, errRes.Error)
+	}
+
+	return g.ReturnSuccess("Berhasil menghapus template")
+}
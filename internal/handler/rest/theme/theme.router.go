@@ -0,0 +1,238 @@
+package theme
+
+import (
+	guard "antrein/bc-dashboard/application/middleware"
+	"antrein/bc-dashboard/internal/usecase/configuration"
+	validate "antrein/bc-dashboard/internal/utils/validator"
+	"antrein/bc-dashboard/model/config"
+	"antrein/bc-dashboard/model/dto"
+	"context"
+	"mime/multipart"
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gorilla/mux"
+)
+
+type Router struct {
+	cfg     *config.Config
+	usecase *configuration.Usecase
+	vld     *validator.Validate
+}
+
+func New(cfg *config.Config, usecase *configuration.Usecase, vld *validator.Validate) *Router {
+	return &Router{
+		cfg:     cfg,
+		usecase: usecase,
+		vld:     vld,
+	}
+}
+
+func (r *Router) RegisterRoute(app *mux.Router) {
+	app.HandleFunc("/bc/dashboard/project/theme", guard.AuthGuard(r.cfg, r.CreateTheme))
+	app.HandleFunc("/bc/dashboard/project/theme/list/{id}", guard.AuthGuard(r.cfg, r.GetListTheme))
+	app.HandleFunc("/bc/dashboard/project/theme/activate", guard.AuthGuard(r.cfg, r.ActivateTheme))
+	app.HandleFunc("/bc/dashboard/project/theme/schedule", guard.AuthGuard(r.cfg, r.ScheduleThemeSwitch))
+	app.HandleFunc("/bc/dashboard/project/theme/schedule/list/{id}", guard.AuthGuard(r.cfg, r.GetThemeSchedules))
+	app.HandleFunc("/bc/dashboard/project/theme/schedule/{id}", guard.AuthGuard(r.cfg, r.DeleteThemeSchedule))
+	app.HandleFunc("/bc/dashboard/project/theme/{id}", guard.AuthGuard(r.cfg, r.DeleteTheme))
+}
+
+func (r *Router) CreateTheme(g *guard.AuthGuardContext) error {
+	ok := guard.IsMethod(g.Request, "POST")
+	if !ok {
+		return g.ReturnError(http.StatusMethodNotAllowed, "Method not allowed")
+	}
+
+	req := dto.CreateThemeRequest{}
+
+	err := g.Request.ParseMultipartForm(10 << 20)
+	if err != nil {
+		return g.ReturnError(http.StatusBadRequest, "Request tidak sesuai format")
+	}
+
+	form := g.Request.MultipartForm
+
+	if val, ok := form.Value["project_id"]; ok && len(val) > 0 {
+		req.ProjectID = val[0]
+	}
+
+	if val, ok := form.Value["name"]; ok && len(val) > 0 {
+		req.Name = val[0]
+	}
+
+	if val, ok := form.Value["queue_page_style"]; ok && len(val) > 0 {
+		req.QueuePageStyle = val[0]
+	}
+
+	if val, ok := form.Value["queue_page_base_color"]; ok && len(val) > 0 {
+		req.QueuePageBaseColor = val[0]
+	}
+
+	if val, ok := form.Value["queue_page_title"]; ok && len(val) > 0 {
+		req.QueuePageTitle = val[0]
+	}
+
+	if val, ok := form.Value["queue_page_logo"]; ok && len(val) > 0 {
+		req.QueuePageLogo = val[0]
+	}
+
+	ctx := context.Background()
+
+	err = validate.ValidateCreateTheme(req)
+	if err != nil {
+		return g.ReturnError(http.StatusBadRequest, err.Error())
+	}
+
+	var imageFile *multipart.FileHeader
+	_, imageFile, err = g.Request.FormFile("image")
+	if err != nil {
+		if err != http.ErrMissingFile {
+			return g.ReturnError(http.StatusBadRequest, "Gagal mendapatkan file logo")
+		}
+		imageFile = nil
+	}
+
+	var htmlFile *multipart.FileHeader
+	_, htmlFile, err = g.Request.FormFile("file")
+	if err != nil {
+		if err != http.ErrMissingFile {
+			return g.ReturnError(http.StatusBadRequest, "Gagal mendapatkan file html")
+		}
+		htmlFile = nil
+	}
+
+	tenantID := g.Claims.UserID
+	resp, errRes := r.usecase.CreateTheme(ctx, req, tenantID, imageFile, htmlFile)
+	if errRes != nil {
+		return g.ReturnError(errRes.Status, errRes.Error)
+	}
+
+	return g.ReturnCreated(resp)
+}
+
+func (r *Router) GetListTheme(g *guard.AuthGuardContext) error {
+	ok := guard.IsMethod(g.Request, "GET")
+	if !ok {
+		return g.ReturnError(http.StatusMethodNotAllowed, "Method not allowed")
+	}
+
+	projectID := guard.GetParam(g.Request, "id")
+	ctx := context.Background()
+	tenantID := g.Claims.UserID
+	resp, errRes := r.usecase.GetListTheme(ctx, projectID, tenantID)
+	if errRes != nil {
+		return g.ReturnError(errRes.Status, errRes.Error)
+	}
+
+	return g.ReturnSuccess(resp)
+}
+
+func (r *Router) DeleteTheme(g *guard.AuthGuardContext) error {
+	ok := guard.IsMethod(g.Request, "DELETE")
+	if !ok {
+		return g.ReturnError(http.StatusMethodNotAllowed, "Method not allowed")
+	}
+
+	themeID := guard.GetParam(g.Request, "id")
+	ctx := context.Background()
+	tenantID := g.Claims.UserID
+	errRes := r.usecase.DeleteTheme(ctx, themeID, tenantID)
+	if errRes != nil {
+		return g.ReturnError(errRes.Status, errRes.Error)
+	}
+
+	return g.ReturnSuccess("Berhasil menghapus tema")
+}
+
+func (r *Router) ActivateTheme(g *guard.AuthGuardContext) error {
+	ok := guard.IsMethod(g.Request, "PUT")
+	if !ok {
+		return g.ReturnError(http.StatusMethodNotAllowed, "Method not allowed")
+	}
+
+	req := dto.ActivateThemeRequest{}
+
+	err := guard.BodyParser(g.Request, &req)
+	if err != nil {
+		return g.ReturnError(http.StatusBadRequest, "Request tidak sesuai format")
+	}
+
+	ctx := context.Background()
+
+	err = r.vld.StructCtx(ctx, &req)
+	if err != nil {
+		return g.ReturnError(http.StatusBadRequest, "Request tidak sesuai format")
+	}
+
+	tenantID := g.Claims.UserID
+	errRes := r.usecase.ActivateTheme(ctx, req, tenantID)
+	if errRes != nil {
+		return g.ReturnError(errRes.Status, errRes.Error)
+	}
+
+	return g.ReturnSuccess("Berhasil mengaktifkan tema")
+}
+
+func (r *Router) ScheduleThemeSwitch(g *guard.AuthGuardContext) error {
+	ok := guard.IsMethod(g.Request, "POST")
+	if !ok {
+		return g.ReturnError(http.StatusMethodNotAllowed, "Method not allowed")
+	}
+
+	req := dto.ScheduleThemeRequest{}
+
+	err := guard.BodyParser(g.Request, &req)
+	if err != nil {
+		return g.ReturnError(http.StatusBadRequest, "Request tidak sesuai format")
+	}
+
+	ctx := context.Background()
+
+	err = r.vld.StructCtx(ctx, &req)
+	if err != nil {
+		return g.ReturnError(http.StatusBadRequest, "Request tidak sesuai format")
+	}
+
+	tenantID := g.Claims.UserID
+	resp, errRes := r.usecase.ScheduleThemeSwitch(ctx, req, tenantID)
+	if errRes != nil {
+		return g.ReturnError(errRes.Status, errRes.Error)
+	}
+
+	return g.ReturnCreated(resp)
+}
+
+func (r *Router) GetThemeSchedules(g *guard.AuthGuardContext) error {
+	ok := guard.IsMethod(g.Request, "GET")
+	if !ok {
+		return g.ReturnError(http.StatusMethodNotAllowed, "Method not allowed")
+	}
+
+	projectID := guard.GetParam(g.Request, "id")
+	ctx := context.Background()
+	tenantID := g.Claims.UserID
+	resp, errRes := r.usecase.GetThemeSchedules(ctx, projectID, tenantID)
+	if errRes != nil {
+		return g.ReturnError(errRes.Status, errRes.Error)
+	}
+
+	return g.ReturnSuccess(resp)
+}
+
+func (r *Router) DeleteThemeSchedule(g *guard.AuthGuardContext) error {
+	ok := guard.IsMethod(g.Request, "DELETE")
+	if !ok {
+		return g.ReturnError(http.StatusMethodNotAllowed, "Method not allowed")
+	}
+
+	scheduleID := guard.GetParam(g.Request, "id")
+	ctx := context.Background()
+	tenantID := g.Claims.UserID
+	errRes := r.usecase.DeleteThemeSchedule(ctx, scheduleID, tenantID)
+	if errRes != nil {
+		return g.ReturnError(errRes.Status, errRes.Error)
+	}
+
+	return g.ReturnSuccess("Berhasil menghapus jadwal tema")
+}
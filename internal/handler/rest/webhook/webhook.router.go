@@ -0,0 +1,197 @@
+package webhook
+
+import (
+	guard "antrein/bc-dashboard/application/middleware"
+	"antrein/bc-dashboard/internal/usecase/webhook"
+	validate "antrein/bc-dashboard/internal/utils/validator"
+	"antrein/bc-dashboard/model/config"
+	"antrein/bc-dashboard/model/dto"
+	"context"
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gorilla/mux"
+)
+
+type Router struct {
+	cfg     *config.Config
+	usecase *webhook.Usecase
+	vld     *validator.Validate
+}
+
+func New(cfg *config.Config, usecase *webhook.Usecase, vld *validator.Validate) *Router {
+	return &Router{
+		cfg:     cfg,
+		usecase: usecase,
+		vld:     vld,
+	}
+}
+
+func (r *Router) RegisterRoute(app *mux.Router) {
+	app.HandleFunc("/bc/dashboard/webhook", guard.AuthGuard(r.cfg, r.CreateWebhook))
+	app.HandleFunc("/bc/dashboard/webhook/list", guard.AuthGuard(r.cfg, r.GetListWebhooks))
+	app.HandleFunc("/bc/dashboard/webhook/delivery/list/{id}", guard.AuthGuard(r.cfg, r.GetListDeliveries))
+	app.HandleFunc("/bc/dashboard/webhook/delivery/{id}/redeliver", guard.AuthGuard(r.cfg, r.Redeliver))
+	app.HandleFunc("/bc/dashboard/webhook/{id}/ping", guard.AuthGuard(r.cfg, r.Ping))
+	app.HandleFunc("/bc/dashboard/webhook/{id}", guard.AuthGuard(r.cfg, r.UpdateOrDeleteWebhook))
+}
+
+func (r *Router) CreateWebhook(g *guard.AuthGuardContext) error {
+	ok := guard.IsMethod(g.Request, "POST")
+	if !ok {
+		return g.ReturnError(http.StatusMethodNotAllowed, "Method not allowed")
+	}
+
+	req := dto.CreateWebhookRequest{}
+
+	err := guard.BodyParser(g.Request, &req)
+	if err != nil {
+		return g.ReturnError(http.StatusBadRequest, "Request tidak sesuai format")
+	}
+
+	ctx := context.Background()
+
+	err = r.vld.StructCtx(ctx, &req)
+	if err != nil {
+		return g.ReturnError(http.StatusBadRequest, "Request tidak sesuai format")
+	}
+
+	err = validate.ValidateCreateWebhook(req, r.cfg.Stage == config.StageDevelopment)
+	if err != nil {
+		return g.ReturnError(http.StatusBadRequest, err.Error())
+	}
+
+	tenantID := g.Claims.UserID
+	resp, errRes := r.usecase.CreateWebhook(ctx, req, tenantID)
+	if errRes != nil {
+		return g.ReturnError(errRes.Status, errRes.Error)
+	}
+
+	return g.ReturnCreated(resp)
+}
+
+func (r *Router) GetListWebhooks(g *guard.AuthGuardContext) error {
+	ok := guard.IsMethod(g.Request, "GET")
+	if !ok {
+		return g.ReturnError(http.StatusMethodNotAllowed, "Method not allowed")
+	}
+
+	ctx := context.Background()
+	tenantID := g.Claims.UserID
+	resp, errRes := r.usecase.GetListWebhook(ctx, tenantID)
+	if errRes != nil {
+		return g.ReturnError(errRes.Status, errRes.Error)
+	}
+
+	return g.ReturnSuccess(resp)
+}
+
+func (r *Router) UpdateOrDeleteWebhook(g *guard.AuthGuardContext) error {
+	if guard.IsMethod(g.Request, "DELETE") {
+		return r.DeleteWebhook(g)
+	}
+	return r.UpdateWebhook(g)
+}
+
+func (r *Router) UpdateWebhook(g *guard.AuthGuardContext) error {
+	ok := guard.IsMethod(g.Request, "PUT")
+	if !ok {
+		return g.ReturnError(http.StatusMethodNotAllowed, "Method not allowed")
+	}
+
+	req := dto.UpdateWebhookRequest{}
+
+	err := guard.BodyParser(g.Request, &req)
+	if err != nil {
+		return g.ReturnError(http.StatusBadRequest, "Request tidak sesuai format")
+	}
+
+	ctx := context.Background()
+
+	err = r.vld.StructCtx(ctx, &req)
+	if err != nil {
+		return g.ReturnError(http.StatusBadRequest, "Request tidak sesuai format")
+	}
+
+	err = validate.ValidateUpdateWebhook(req, r.cfg.Stage == config.StageDevelopment)
+	if err != nil {
+		return g.ReturnError(http.StatusBadRequest, err.Error())
+	}
+
+	webhookID := guard.GetParam(g.Request, "id")
+	tenantID := g.Claims.UserID
+	resp, errRes := r.usecase.UpdateWebhook(ctx, webhookID, req, tenantID)
+	if errRes != nil {
+		return g.ReturnError(errRes.Status, errRes.Error)
+	}
+
+	return g.ReturnSuccess(resp)
+}
+
+func (r *Router) DeleteWebhook(g *guard.AuthGuardContext) error {
+	ok := guard.IsMethod(g.Request, "DELETE")
+	if !ok {
+		return g.ReturnError(http.StatusMethodNotAllowed, "Method not allowed")
+	}
+
+	webhookID := guard.GetParam(g.Request, "id")
+	ctx := context.Background()
+	tenantID := g.Claims.UserID
+	errRes := r.usecase.DeleteWebhook(ctx, webhookID, tenantID)
+	if errRes != nil {
+		return g.ReturnError(errRes.Status, errRes.Error)
+	}
+
+	return g.ReturnSuccess("Berhasil menghapus webhook")
+}
+
+func (r *Router) GetListDeliveries(g *guard.AuthGuardContext) error {
+	ok := guard.IsMethod(g.Request, "GET")
+	if !ok {
+		return g.ReturnError(http.StatusMethodNotAllowed, "Method not allowed")
+	}
+
+	webhookID := guard.GetParam(g.Request, "id")
+	ctx := context.Background()
+	tenantID := g.Claims.UserID
+	resp, errRes := r.usecase.GetListDelivery(ctx, webhookID, tenantID)
+	if errRes != nil {
+		return g.ReturnError(errRes.Status, errRes.Error)
+	}
+
+	return g.ReturnSuccess(resp)
+}
+
+func (r *Router) Redeliver(g *guard.AuthGuardContext) error {
+	ok := guard.IsMethod(g.Request, "POST")
+	if !ok {
+		return g.ReturnError(http.StatusMethodNotAllowed, "Method not allowed")
+	}
+
+	deliveryID := guard.GetParam(g.Request, "id")
+	ctx := context.Background()
+	tenantID := g.Claims.UserID
+	resp, errRes := r.usecase.Redeliver(ctx, deliveryID, tenantID)
+	if errRes != nil {
+		return g.ReturnError(errRes.Status, errRes.Error)
+	}
+
+	return g.ReturnCreated(resp)
+}
+
+func (r *Router) Ping(g *guard.AuthGuardContext) error {
+	ok := guard.IsMethod(g.Request, "POST")
+	if !ok {
+		return g.ReturnError(http.StatusMethodNotAllowed, "Method not allowed")
+	}
+
+	webhookID := guard.GetParam(g.Request, "id")
+	ctx := context.Background()
+	tenantID := g.Claims.UserID
+	resp, errRes := r.usecase.Ping(ctx, webhookID, tenantID)
+	if errRes != nil {
+		return g.ReturnError(errRes.Status, errRes.Error)
+	}
+
+	return g.ReturnSuccess(resp)
+}
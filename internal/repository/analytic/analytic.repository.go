@@ -0,0 +1,48 @@
+package analytic
+
+import (
+	"antrein/bc-dashboard/model/config"
+	"antrein/bc-dashboard/model/dto"
+	"context"
+
+	pb "github.com/antrein/proto-repository/pb/bc"
+	"google.golang.org/grpc"
+)
+
+type Repository struct {
+	cfg        *config.Config
+	grpcClient *grpc.ClientConn
+}
+
+func New(cfg *config.Config, gc *grpc.ClientConn) *Repository {
+	return &Repository{
+		cfg:        cfg,
+		grpcClient: gc,
+	}
+}
+
+// GetRealtimeData reads a single sample from the realtime analytic stream.
+func (r *Repository) GetRealtimeData(ctx context.Context, projectID string) (*dto.Analytic, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	client := pb.NewAnalyticServiceClient(r.grpcClient)
+	stream, err := client.StreamRealtimeData(ctx, &pb.AnalyticRequest{
+		ProjectId: projectID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	analyticData, err := stream.Recv()
+	if err != nil {
+		return nil, err
+	}
+	return &dto.Analytic{
+		ProjectID:         projectID,
+		TimeStamp:         analyticData.GetTimestamp().AsTime(),
+		TotalUsersInQueue: int(analyticData.TotalUsersInQueue),
+		TotalUsersInRoom:  int(analyticData.TotalUsersInRoom),
+		TotalUsers:        int(analyticData.TotalUsers),
+	}, nil
+}
@@ -0,0 +1,171 @@
+package asset
+
+import (
+	"antrein/bc-dashboard/model/config"
+	"antrein/bc-dashboard/model/entity"
+	"context"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type Repository struct {
+	cfg *config.Config
+	db  *sqlx.DB
+}
+
+func New(cfg *config.Config, db *sqlx.DB) *Repository {
+	return &Repository{
+		cfg: cfg,
+		db:  db,
+	}
+}
+
+func (r *Repository) GetLogoByHash(ctx context.Context, hash string) (*entity.LogoAsset, error) {
+	logo := entity.LogoAsset{}
+	q := `SELECT * FROM logo_assets WHERE hash = $1`
+	err := r.db.GetContext(ctx, &logo, q, hash)
+	if err != nil {
+		return nil, err
+	}
+	return &logo, nil
+}
+
+func (r *Repository) CreateNewLogo(ctx context.Context, req entity.LogoAsset) error {
+	q := `INSERT INTO logo_assets (hash, name, url, content_type, size, created_at) VALUES ($1, $2, $3, $4, $5, $6)
+		  ON CONFLICT (hash) DO NOTHING`
+	_, err := r.db.ExecContext(ctx, q, req.Hash, req.Name, req.URL, req.ContentType, req.Size, req.CreatedAt)
+	return err
+}
+
+// GetExpiredLogos returns logos uploaded before the given time that no theme,
+// template or kept page version points at anymore.
+func (r *Repository) GetExpiredLogos(ctx context.Context, before time.Time) ([]entity.LogoAsset, error) {
+	logos := []entity.LogoAsset{}
+	q := `SELECT * FROM logo_assets l
+		  WHERE l.created_at < $1
+		  AND NOT EXISTS (SELECT 1 FROM themes t WHERE t.queue_page_logo = l.url)
+		  AND NOT EXISTS (SELECT 1 FROM project_templates pt WHERE pt.queue_page_logo = l.url)
+		  AND NOT EXISTS (SELECT 1 FROM page_versions v WHERE v.logo_url = l.url)`
+	err := r.db.SelectContext(ctx, &logos, q, before)
+	return logos, err
+}
+
+func (r *Repository) DeleteLogo(ctx context.Context, hash string) error {
+	q := `DELETE FROM logo_assets WHERE hash = $1`
+	_, err := r.db.ExecContext(ctx, q, hash)
+	return err
+}
+
+func (r *Repository) GetPageVersion(ctx context.Context, name, hash string) (*entity.PageVersion, error) {
+	version := entity.PageVersion{}
+	q := `SELECT * FROM page_versions WHERE name = $1 AND hash = $2`
+	err := r.db.GetContext(ctx, &version, q, name, hash)
+	if err != nil {
+		return nil, err
+	}
+	return &version, nil
+}
+
+// GetPageVersionByURL returns the latest version published under url.
+func (r *Repository) GetPageVersionByURL(ctx context.Context, url string) (*entity.PageVersion, error) {
+	version := entity.PageVersion{}
+	q := `SELECT * FROM page_versions WHERE url = $1 ORDER BY published_at DESC LIMIT 1`
+	err := r.db.GetContext(ctx, &version, q, url)
+	if err != nil {
+		return nil, err
+	}
+	return &version, nil
+}
+
+// CreateNewPageVersion stores the version, the tenant is taken from the
+// project when it is not given.
+func (r *Repository) CreateNewPageVersion(ctx context.Context, req entity.PageVersion) error {
+	q := `INSERT INTO page_versions (tenant_id, project_id, name, object_name, hash, url, logo_url, published_at, created_at)
+		  VALUES (COALESCE(NULLIF($1, '')::uuid, (SELECT tenant_id FROM projects WHERE id = $2)), $2, $3, $4, $5, $6, $7, $8, $8)
+		  ON CONFLICT (name, hash) DO UPDATE SET published_at = EXCLUDED.published_at`
+	_, err := r.db.ExecContext(ctx, q, req.TenantID, req.ProjectID, req.Name, req.ObjectName, req.Hash, req.URL, req.LogoURL, req.PublishedAt)
+	return err
+}
+
+// PublishPageVersion makes an existing version the current one of its page.
+func (r *Repository) PublishPageVersion(ctx context.Context, id string, publishedAt time.Time) error {
+	q := `UPDATE page_versions SET published_at = $1 WHERE id = $2`
+	_, err := r.db.ExecContext(ctx, q, publishedAt, id)
+	return err
+}
+
+// GetCurrentPageURLs maps each page name to the URL of its latest version.
+func (r *Repository) GetCurrentPageURLs(ctx context.Context, names []string) (map[string]string, error) {
+	rows := []entity.PageVersion{}
+	q := `SELECT DISTINCT ON (name) * FROM page_versions WHERE name = ANY($1) ORDER BY name, published_at DESC`
+	err := r.db.SelectContext(ctx, &rows, q, pq.Array(names))
+	if err != nil {
+		return nil, err
+	}
+	urls := map[string]string{}
+	for _, row := range rows {
+		urls[row.Name] = row.URL
+	}
+	return urls, nil
+}
+
+func (r *Repository) GetProjectPageVersions(ctx context.Context, projectID string) ([]entity.PageVersion, error) {
+	versions := []entity.PageVersion{}
+	q := `SELECT * FROM page_versions WHERE project_id = $1 ORDER BY name, published_at DESC`
+	err := r.db.SelectContext(ctx, &versions, q, projectID)
+	return versions, err
+}
+
+func (r *Repository) GetTenantPageVersionByID(ctx context.Context, id, tenantID string) (*entity.PageVersion, error) {
+	version := entity.PageVersion{}
+	q := `SELECT * FROM page_versions WHERE id = $1 AND tenant_id = $2`
+	err := r.db.GetContext(ctx, &version, q, id, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return &version, nil
+}
+
+// RollbackPage points every theme that shows a version of the page at the
+// given version and makes it the current one.
+func (r *Repository) RollbackPage(ctx context.Context, version entity.PageVersion, now time.Time) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	q := `UPDATE themes SET queue_html_page = $1, updated_at = now()
+		  WHERE queue_html_page IN (SELECT url FROM page_versions WHERE name = $2)`
+	if _, err = tx.ExecContext(ctx, q, version.URL, version.Name); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	q = `UPDATE page_versions SET published_at = $1 WHERE id = $2`
+	if _, err = tx.ExecContext(ctx, q, now, version.ID); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetExpiredPageVersions returns versions published before the given time
+// that are neither the current version of their page nor shown by a theme.
+func (r *Repository) GetExpiredPageVersions(ctx context.Context, before time.Time) ([]entity.PageVersion, error) {
+	versions := []entity.PageVersion{}
+	q := `SELECT * FROM page_versions v
+		  WHERE v.published_at < $1
+		  AND NOT EXISTS (SELECT 1 FROM themes t WHERE t.queue_html_page = v.url)
+		  AND EXISTS (SELECT 1 FROM page_versions n WHERE n.name = v.name AND n.published_at > v.published_at)`
+	err := r.db.SelectContext(ctx, &versions, q, before)
+	return versions, err
+}
+
+func (r *Repository) DeletePageVersion(ctx context.Context, id string) error {
+	q := `DELETE FROM page_versions WHERE id = $1`
+	_, err := r.db.ExecContext(ctx, q, id)
+	return err
+}
@@ -0,0 +1,118 @@
+package autoscale
+
+import (
+	"antrein/bc-dashboard/model/config"
+	"antrein/bc-dashboard/model/entity"
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type Repository struct {
+	cfg *config.Config
+	db  *sqlx.DB
+}
+
+func New(cfg *config.Config, db *sqlx.DB) *Repository {
+	return &Repository{
+		cfg: cfg,
+		db:  db,
+	}
+}
+
+func (r *Repository) GetPolicyByProjectID(ctx context.Context, projectID string) (*entity.AutoscalePolicyWithConfig, error) {
+	policy := entity.AutoscalePolicyWithConfig{}
+	q := `SELECT a.*, c.threshold, c.effective_threshold
+		  FROM autoscale_policies a
+		  JOIN configurations c ON c.project_id = a.project_id
+		  WHERE a.project_id = $1`
+	err := r.db.GetContext(ctx, &policy, q, projectID)
+	if err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+func (r *Repository) GetEnabledPolicies(ctx context.Context) ([]entity.AutoscalePolicyWithConfig, error) {
+	policies := []entity.AutoscalePolicyWithConfig{}
+	q := `SELECT a.*, c.threshold, c.effective_threshold
+		  FROM autoscale_policies a
+		  JOIN configurations c ON c.project_id = a.project_id
+		  WHERE a.enabled = true AND c.is_configure = true`
+	err := r.db.SelectContext(ctx, &policies, q)
+	return policies, err
+}
+
+// UpsertPolicy stores the policy and sets the starting effective threshold,
+// a disabled policy clears it so the configured threshold applies again.
+func (r *Repository) UpsertPolicy(ctx context.Context, req entity.AutoscalePolicy, effectiveThreshold sql.NullInt32) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	q := `INSERT INTO autoscale_policies (project_id, enabled, min_threshold, max_threshold, step_down, step_up, created_at)
+		  VALUES ($1, $2, $3, $4, $5, $6, now())
+		  ON CONFLICT (project_id) DO UPDATE
+		  SET enabled = EXCLUDED.enabled,
+		  min_threshold = EXCLUDED.min_threshold,
+		  max_threshold = EXCLUDED.max_threshold,
+		  step_down = EXCLUDED.step_down,
+		  step_up = EXCLUDED.step_up,
+		  updated_at = now()`
+	_, err = tx.ExecContext(ctx, q, req.ProjectID, req.Enabled, req.MinThreshold, req.MaxThreshold, req.StepDown, req.StepUp)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	q = `UPDATE configurations SET effective_threshold = $1 WHERE project_id = $2`
+	_, err = tx.ExecContext(ctx, q, effectiveThreshold, req.ProjectID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// ApplyAdjustment moves the effective threshold and records the adjustment in
+// the same transaction.
+func (r *Repository) ApplyAdjustment(ctx context.Context, req entity.ThresholdAdjustment) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	q := `UPDATE configurations SET effective_threshold = $1 WHERE project_id = $2`
+	_, err = tx.ExecContext(ctx, q, req.ToThreshold, req.ProjectID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	q = `UPDATE autoscale_policies SET last_adjusted_at = $1 WHERE project_id = $2`
+	_, err = tx.ExecContext(ctx, q, req.CreatedAt, req.ProjectID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	q = `INSERT INTO threshold_adjustments (project_id, from_threshold, to_threshold, healthiness, users_in_room, users_in_queue, reason, created_at)
+		  VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+	_, err = tx.ExecContext(ctx, q, req.ProjectID, req.FromThreshold, req.ToThreshold, req.Healthiness, req.UsersInRoom, req.UsersInQueue, req.Reason, req.CreatedAt)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *Repository) GetAdjustments(ctx context.Context, projectID string, limit int) ([]entity.ThresholdAdjustment, error) {
+	adjustments := []entity.ThresholdAdjustment{}
+	q := `SELECT * FROM threshold_adjustments WHERE project_id = $1 ORDER BY created_at DESC LIMIT $2`
+	err := r.db.SelectContext(ctx, &adjustments, q, projectID, limit)
+	return adjustments, err
+}
@@ -0,0 +1,109 @@
+package bypass
+
+import (
+	"antrein/bc-dashboard/model/config"
+	"antrein/bc-dashboard/model/entity"
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type Repository struct {
+	cfg *config.Config
+	db  *sqlx.DB
+}
+
+func New(cfg *config.Config, db *sqlx.DB) *Repository {
+	return &Repository{
+		cfg: cfg,
+		db:  db,
+	}
+}
+
+func (r *Repository) CreateNewRule(ctx context.Context, req entity.BypassRule) (*entity.BypassRule, error) {
+	rule := req
+	q := `INSERT INTO bypass_rules (project_id, type, name, code_hash, cidr, header_name, header_value, max_uses, expires_at, is_active, created_at)
+		  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) returning id`
+	var id string
+	err := r.db.GetContext(ctx, &id, q, req.ProjectID, req.Type, req.Name, req.CodeHash, req.CIDR, req.HeaderName, req.HeaderValue, req.MaxUses, req.ExpiresAt, req.IsActive, req.CreatedAt)
+	rule.ID = id
+	return &rule, err
+}
+
+func (r *Repository) GetTenantRuleByID(ctx context.Context, id, tenantID string) (*entity.BypassRule, error) {
+	rule := entity.BypassRule{}
+	q := `SELECT bypass_rules.* FROM bypass_rules INNER JOIN projects ON projects.id = bypass_rules.project_id WHERE bypass_rules.id = $1 AND projects.tenant_id = $2 LIMIT 1`
+	err := r.db.GetContext(ctx, &rule, q, id, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return &rule, err
+}
+
+func (r *Repository) GetProjectRules(ctx context.Context, projectID string) ([]entity.BypassRule, error) {
+	rules := []entity.BypassRule{}
+	q := `SELECT * FROM bypass_rules WHERE project_id = $1 ORDER BY created_at`
+	err := r.db.SelectContext(ctx, &rules, q, projectID)
+	return rules, err
+}
+
+func (r *Repository) GetActiveProjectRules(ctx context.Context, projectID string) ([]entity.BypassRule, error) {
+	rules := []entity.BypassRule{}
+	q := `SELECT * FROM bypass_rules WHERE project_id = $1 AND is_active = TRUE AND (expires_at IS NULL OR expires_at > now()) ORDER BY created_at`
+	err := r.db.SelectContext(ctx, &rules, q, projectID)
+	return rules, err
+}
+
+func (r *Repository) UpdateRule(ctx context.Context, req entity.BypassRule) error {
+	q := `UPDATE bypass_rules
+		  SET name = $1,
+		  max_uses = $2,
+		  expires_at = $3,
+		  is_active = $4,
+		  updated_at = now()
+		  WHERE id = $5`
+	_, err := r.db.ExecContext(ctx, q, req.Name, req.MaxUses, req.ExpiresAt, req.IsActive, req.ID)
+	return err
+}
+
+func (r *Repository) DeleteRule(ctx context.Context, id string) error {
+	q := `DELETE FROM bypass_rules WHERE id = $1`
+	_, err := r.db.ExecContext(ctx, q, id)
+	return err
+}
+
+// RedeemCode increments the usage counter of an active invite code as long as
+// it is not expired and still below its cap. It returns sql.ErrNoRows when the
+// code cannot be used.
+func (r *Repository) RedeemCode(ctx context.Context, projectID, codeHash string) (*entity.BypassRule, error) {
+	rule := entity.BypassRule{}
+	q := `UPDATE bypass_rules
+		  SET used_count = used_count + 1,
+		  last_used_at = now()
+		  WHERE project_id = $1 AND code_hash = $2 AND type = 'invite_code' AND is_active = TRUE
+		  AND (expires_at IS NULL OR expires_at > now())
+		  AND (max_uses = 0 OR used_count < max_uses)
+		  RETURNING *`
+	err := r.db.GetContext(ctx, &rule, q, projectID, codeHash)
+	if err != nil {
+		return nil, err
+	}
+	return &rule, err
+}
+
+func (r *Repository) RecordUsage(ctx context.Context, id string) error {
+	q := `UPDATE bypass_rules SET used_count = used_count + 1, last_used_at = now() WHERE id = $1`
+	resp, err := r.db.ExecContext(ctx, q, id)
+	if err != nil {
+		return err
+	}
+	affected, err := resp.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
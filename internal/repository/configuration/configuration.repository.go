@@ -7,18 +7,30 @@ import (
 	"context"
 	"database/sql"
 	"errors"
-	"net/http"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 )
 
+// The queue page style lives in the active theme, configurations are always
+// read together with it.
+const (
+	configColumns = `configurations.*,
+		  COALESCE(themes.queue_page_style, 'base') AS queue_page_style,
+		  themes.queue_html_page,
+		  themes.queue_page_base_color,
+		  themes.queue_page_title,
+		  themes.queue_page_logo`
+	configTables = `configurations LEFT JOIN themes ON themes.id = configurations.active_theme_id`
+)
+
 type Repository struct {
 	cfg       *config.Config
 	db        *sqlx.DB
-	infraRepo *infra.Repository
+	infraRepo infra.InfraManager
 }
 
-func New(cfg *config.Config, db *sqlx.DB, infraRepo *infra.Repository) *Repository {
+func New(cfg *config.Config, db *sqlx.DB, infraRepo infra.InfraManager) *Repository {
 	return &Repository{
 		cfg:       cfg,
 		db:        db,
@@ -28,7 +40,7 @@ func New(cfg *config.Config, db *sqlx.DB, infraRepo *infra.Repository) *Reposito
 
 func (r *Repository) GetConfigByProjectID(ctx context.Context, projectID string) (*entity.Configuration, error) {
 	config := entity.Configuration{}
-	q := `SELECT * FROM configurations WHERE project_id = $1 LIMIT 1`
+	q := `SELECT ` + configColumns + ` FROM ` + configTables + ` WHERE configurations.project_id = $1 LIMIT 1`
 	err := r.db.GetContext(ctx, &config, q, projectID)
 	if err != nil {
 		return nil, err
@@ -38,7 +50,7 @@ func (r *Repository) GetConfigByProjectID(ctx context.Context, projectID string)
 
 func (r *Repository) GetConfigByHost(ctx context.Context, host string) (*entity.Configuration, error) {
 	config := entity.Configuration{}
-	q := `SELECT * FROM configurations WHERE host = $1 LIMIT 1`
+	q := `SELECT ` + configColumns + ` FROM ` + configTables + ` WHERE configurations.host = $1 LIMIT 1`
 	err := r.db.GetContext(ctx, &config, q, host)
 	if err != nil {
 		return nil, err
@@ -46,7 +58,32 @@ func (r *Repository) GetConfigByHost(ctx context.Context, host string) (*entity.
 	return &config, err
 }
 
-func (r *Repository) UpdateProjectConfig(ctx context.Context, req entity.Configuration) error {
+func (r *Repository) GetProjectByID(ctx context.Context, projectID string) (*entity.Project, error) {
+	project := entity.Project{}
+	q := `SELECT * FROM projects WHERE id = $1 LIMIT 1`
+	err := r.db.GetContext(ctx, &project, q, projectID)
+	if err != nil {
+		return nil, err
+	}
+	return &project, err
+}
+
+// IsSharedRouteTaken tells whether another project on the shared deployment
+// already registered host and base url. Projects without a mode of their own
+// run in defaultMode.
+func (r *Repository) IsSharedRouteTaken(ctx context.Context, projectID, host, baseURL, defaultMode string) (bool, error) {
+	var taken bool
+	q := `SELECT EXISTS (SELECT 1 FROM configurations
+		  INNER JOIN projects ON projects.id = configurations.project_id
+		  WHERE configurations.host = $1 AND configurations.base_url = $2 AND configurations.project_id != $3
+		  AND configurations.is_configure AND COALESCE(projects.infra_mode::text, $4) = 'multi_tenant')`
+	err := r.db.GetContext(ctx, &taken, q, host, baseURL, projectID, defaultMode)
+	return taken, err
+}
+
+// UpdateProjectConfig saves the configuration and syncs it to the infra. A
+// new threshold drops the autoscaled one, so scaling starts again from it.
+func (r *Repository) UpdateProjectConfig(ctx context.Context, req entity.Configuration, infraBody infra.InfraBody) error {
 	tx, err := r.db.BeginTxx(ctx, &sql.TxOptions{
 		Isolation: 1,
 		ReadOnly:  false,
@@ -60,10 +97,14 @@ func (r *Repository) UpdateProjectConfig(ctx context.Context, req entity.Configu
 		  queue_start = $6,
 		  queue_end = $7,
 		  is_configure = $8,
+		  queue_mode = $9,
+		  pre_queue_open_at = $10,
+		  lottery_seed = $11,
+		  effective_threshold = CASE WHEN threshold = $1 THEN effective_threshold END,
 		  updated_at = now()
-		  WHERE project_id = $9`
+		  WHERE project_id = $12`
 
-	resp, err := tx.ExecContext(ctx, q, req.Threshold, req.SessionTime, req.Host, req.BaseURL, req.MaxUsersInQueue, req.QueueStart, req.QueueEnd, true, req.ProjectID)
+	resp, err := tx.ExecContext(ctx, q, req.Threshold, req.SessionTime, req.Host, req.BaseURL, req.MaxUsersInQueue, req.QueueStart, req.QueueEnd, true, req.QueueMode, req.PreQueueOpenAt, req.LotterySeed, req.ProjectID)
 
 	if err != nil {
 		tx.Rollback()
@@ -82,13 +123,7 @@ func (r *Repository) UpdateProjectConfig(ctx context.Context, req entity.Configu
 		return errors.New("Project tidak terdaftar")
 	}
 
-	client := &http.Client{}
-
-	err = r.infraRepo.CreateInfraProject(client, infra.InfraBody{
-		ProjectID:     req.ProjectID,
-		ProjectDomain: req.Host.String,
-		URLPath:       req.BaseURL.String,
-	})
+	err = r.infraRepo.CreateInfraProject(ctx, infraBody)
 
 	if err != nil {
 		tx.Rollback()
@@ -98,15 +133,235 @@ func (r *Repository) UpdateProjectConfig(ctx context.Context, req entity.Configu
 	return tx.Commit()
 }
 
+// UpdateProjectStyle writes the style into the active theme when the theme
+// belongs to the project. Otherwise the project default theme is created or
+// overwritten and activated, so shared tenant themes are never changed here.
 func (r *Repository) UpdateProjectStyle(ctx context.Context, req entity.Configuration) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	var themeID string
+	q := `SELECT themes.id FROM configurations
+		  INNER JOIN themes ON themes.id = configurations.active_theme_id AND themes.project_id = configurations.project_id
+		  WHERE configurations.project_id = $1`
+	err = tx.GetContext(ctx, &themeID, q, req.ProjectID)
+	if err != nil && err != sql.ErrNoRows {
+		tx.Rollback()
+		return err
+	}
+
+	if err == nil {
+		q = `UPDATE themes 
+			 SET queue_page_style = $1,
+			 queue_html_page = $2,
+			 queue_page_base_color = $3,
+			 queue_page_title = $4,
+			 queue_page_logo = $5,
+			 updated_at = now()
+			 WHERE id = $6`
+		_, err = tx.ExecContext(ctx, q, req.QueuePageStyle, req.QueueHTMLPage, req.QueuePageBaseColor, req.QueuePageTitle, req.QueuePageLogo, themeID)
+	} else {
+		q = `INSERT INTO themes (tenant_id, project_id, name, queue_page_style, queue_html_page, queue_page_base_color, queue_page_title, queue_page_logo)
+			 SELECT tenant_id, id, 'default', $2, $3, $4, $5, $6 FROM projects WHERE id = $1
+			 ON CONFLICT (project_id, name) WHERE project_id IS NOT NULL DO UPDATE
+			 SET queue_page_style = EXCLUDED.queue_page_style,
+			 queue_html_page = EXCLUDED.queue_html_page,
+			 queue_page_base_color = EXCLUDED.queue_page_base_color,
+			 queue_page_title = EXCLUDED.queue_page_title,
+			 queue_page_logo = EXCLUDED.queue_page_logo,
+			 updated_at = now()
+			 RETURNING id`
+		err = tx.GetContext(ctx, &themeID, q, req.ProjectID, req.QueuePageStyle, req.QueueHTMLPage, req.QueuePageBaseColor, req.QueuePageTitle, req.QueuePageLogo)
+	}
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	q = `UPDATE configurations SET active_theme_id = $1, updated_at = now() WHERE project_id = $2`
+	_, err = tx.ExecContext(ctx, q, themeID, req.ProjectID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *Repository) GetTenantConfigByProjectID(ctx context.Context, projectID, tenantID string) (*entity.Configuration, error) {
+	config := entity.Configuration{}
+	q := `SELECT ` + configColumns + ` FROM ` + configTables + ` INNER JOIN projects ON projects.id = configurations.project_id WHERE configurations.project_id = $1 AND projects.tenant_id = $2 LIMIT 1`
+	err := r.db.GetContext(ctx, &config, q, projectID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return &config, err
+}
+
+func (r *Repository) UpdateProjectState(ctx context.Context, req entity.Configuration) error {
 	q := `UPDATE configurations 
-		  SET queue_page_style = $1,
-		  queue_html_page = $2,
-		  queue_page_base_color = $3,
-		  queue_page_title = $4,
-		  queue_page_logo = $5,
+		  SET operational_state = $1,
+		  state_reason = $2,
+		  auto_resume_at = $3,
+		  state_changed_at = now(),
 		  updated_at = now()
-		  WHERE project_id = $6`
-	_, err := r.db.ExecContext(ctx, q, req.QueuePageStyle, req.QueueHTMLPage, req.QueuePageBaseColor, req.QueuePageTitle, req.QueuePageLogo, req.ProjectID)
+		  WHERE project_id = $4`
+	_, err := r.db.ExecContext(ctx, q, req.OperationalState, req.StateReason, req.AutoResumeAt, req.ProjectID)
 	return err
 }
+
+func (r *Repository) GetDueAutoResumeConfigs(ctx context.Context, now time.Time) ([]entity.Configuration, error) {
+	configs := []entity.Configuration{}
+	q := `SELECT ` + configColumns + ` FROM ` + configTables + ` WHERE configurations.auto_resume_at IS NOT NULL AND configurations.auto_resume_at <= $1 AND configurations.operational_state != 'active'`
+	err := r.db.SelectContext(ctx, &configs, q, now)
+	return configs, err
+}
+
+// AutoResumeProject switches the project back to active if it is still due at
+// now. It returns false when a state change made since it was listed means it
+// no longer is.
+func (r *Repository) AutoResumeProject(ctx context.Context, projectID string, now time.Time) (bool, error) {
+	q := `UPDATE configurations 
+		  SET operational_state = 'active',
+		  state_reason = NULL,
+		  auto_resume_at = NULL,
+		  state_changed_at = now(),
+		  updated_at = now()
+		  WHERE project_id = $1 AND auto_resume_at IS NOT NULL AND auto_resume_at <= $2 AND operational_state != 'active'`
+	resp, err := r.db.ExecContext(ctx, q, projectID, now)
+	if err != nil {
+		return false, err
+	}
+	affected, err := resp.RowsAffected()
+	return affected > 0, err
+}
+
+// GetOpenedQueueWindows returns the windows that are open at now and were not
+// announced yet.
+func (r *Repository) GetOpenedQueueWindows(ctx context.Context, now time.Time) ([]entity.QueueWindow, error) {
+	windows := []entity.QueueWindow{}
+	q := `SELECT configurations.project_id, projects.tenant_id, configurations.queue_start, configurations.queue_end
+		  FROM configurations INNER JOIN projects ON projects.id = configurations.project_id
+		  WHERE configurations.is_configure AND configurations.queue_start <= $1 AND configurations.queue_end > $1
+		  AND configurations.notified_queue_start IS DISTINCT FROM configurations.queue_start`
+	err := r.db.SelectContext(ctx, &windows, q, now)
+	return windows, err
+}
+
+// GetClosedQueueWindows returns the windows that closed between since and now
+// and were not announced yet.
+func (r *Repository) GetClosedQueueWindows(ctx context.Context, now, since time.Time) ([]entity.QueueWindow, error) {
+	windows := []entity.QueueWindow{}
+	q := `SELECT configurations.project_id, projects.tenant_id, configurations.queue_start, configurations.queue_end
+		  FROM configurations INNER JOIN projects ON projects.id = configurations.project_id
+		  WHERE configurations.is_configure AND configurations.queue_end <= $1 AND configurations.queue_end > $2
+		  AND configurations.notified_queue_end IS DISTINCT FROM configurations.queue_end`
+	err := r.db.SelectContext(ctx, &windows, q, now, since)
+	return windows, err
+}
+
+// MarkQueueWindowOpened records that the window starting at queueStart was
+// announced. It returns false when it already was, so every window is
+// announced once even with several workers.
+func (r *Repository) MarkQueueWindowOpened(ctx context.Context, projectID string, queueStart time.Time) (bool, error) {
+	q := `UPDATE configurations SET notified_queue_start = $2
+		  WHERE project_id = $1 AND queue_start = $2 AND notified_queue_start IS DISTINCT FROM $2`
+	resp, err := r.db.ExecContext(ctx, q, projectID, queueStart)
+	if err != nil {
+		return false, err
+	}
+	affected, err := resp.RowsAffected()
+	return affected > 0, err
+}
+
+func (r *Repository) MarkQueueWindowClosed(ctx context.Context, projectID string, queueEnd time.Time) (bool, error) {
+	q := `UPDATE configurations SET notified_queue_end = $2
+		  WHERE project_id = $1 AND queue_end = $2 AND notified_queue_end IS DISTINCT FROM $2`
+	resp, err := r.db.ExecContext(ctx, q, projectID, queueEnd)
+	if err != nil {
+		return false, err
+	}
+	affected, err := resp.RowsAffected()
+	return affected > 0, err
+}
+
+func (r *Repository) GetPriorityTiers(ctx context.Context, projectID string) ([]entity.PriorityTier, error) {
+	tiers := []entity.PriorityTier{}
+	q := `SELECT * FROM priority_tiers WHERE project_id = $1 ORDER BY priority, name`
+	err := r.db.SelectContext(ctx, &tiers, q, projectID)
+	return tiers, err
+}
+
+func (r *Repository) ReplacePriorityTiers(ctx context.Context, projectID string, tiers []entity.PriorityTier) error {
+	tx, err := r.db.BeginTxx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelDefault,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return err
+	}
+
+	q1 := `DELETE FROM priority_tiers WHERE project_id = $1`
+	if _, err = tx.ExecContext(ctx, q1, projectID); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	q2 := `INSERT INTO priority_tiers (project_id, name, priority, weight, reserved_share, max_users_in_queue, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7)`
+	for _, tier := range tiers {
+		if _, err = tx.ExecContext(ctx, q2, projectID, tier.Name, tier.Priority, tier.Weight, tier.ReservedShare, tier.MaxUsersInQueue, tier.CreatedAt); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *Repository) GetHTMLPolicyModeByProjectID(ctx context.Context, projectID string) (string, error) {
+	var mode string
+	q := `SELECT t.html_policy_mode FROM tenants t JOIN projects p ON p.tenant_id = t.id WHERE p.id = $1`
+	err := r.db.GetContext(ctx, &mode, q, projectID)
+	return mode, err
+}
+
+func (r *Repository) GetHTMLPolicyModeByTenantID(ctx context.Context, tenantID string) (string, error) {
+	var mode string
+	q := `SELECT html_policy_mode FROM tenants WHERE id = $1`
+	err := r.db.GetContext(ctx, &mode, q, tenantID)
+	return mode, err
+}
+
+func (r *Repository) GetProjectLocales(ctx context.Context, projectID string) ([]entity.ProjectLocale, error) {
+	locales := []entity.ProjectLocale{}
+	q := `SELECT * FROM project_locales WHERE project_id = $1 ORDER BY locale`
+	err := r.db.SelectContext(ctx, &locales, q, projectID)
+	return locales, err
+}
+
+func (r *Repository) UpsertProjectLocale(ctx context.Context, req entity.ProjectLocale) error {
+	q := `INSERT INTO project_locales (project_id, locale, texts, created_at) VALUES ($1, $2, $3, $4)
+		  ON CONFLICT (project_id, locale) DO UPDATE
+		  SET texts = EXCLUDED.texts,
+		  updated_at = now()`
+	_, err := r.db.ExecContext(ctx, q, req.ProjectID, req.Locale, req.Texts, req.CreatedAt)
+	return err
+}
+
+func (r *Repository) DeleteProjectLocale(ctx context.Context, projectID, locale string) error {
+	q := `DELETE FROM project_locales WHERE project_id = $1 AND locale = $2`
+	resp, err := r.db.ExecContext(ctx, q, projectID, locale)
+	if err != nil {
+		return err
+	}
+	affected, err := resp.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package event
+
+import (
+	"antrein/bc-dashboard/internal/utils/generator"
+	"antrein/bc-dashboard/model/dto"
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// AllEvents subscribes a handler to every event type.
+const AllEvents = "*"
+
+type Handler func(ctx context.Context, event dto.ProjectEvent)
+
+// Bus hands the events raised by the usecases to the notification channels
+// that subscribed to them. Every handler runs in a goroutine of its own, so a
+// slow channel never holds up the code that raised the event.
+type Bus struct {
+	mu       sync.RWMutex
+	handlers map[string][]Handler
+}
+
+func New() *Bus {
+	return &Bus{
+		handlers: map[string][]Handler{},
+	}
+}
+
+func (b *Bus) Subscribe(eventType string, handler Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers[eventType] = append(b.handlers[eventType], handler)
+}
+
+// Publish fills in the id, version and time of the event when they are not
+// set and calls the subscribed handlers. It does not wait for them.
+func (b *Bus) Publish(ctx context.Context, event dto.ProjectEvent) {
+	if event.ID == "" {
+		id, err := generator.GenerateEventID()
+		if err != nil {
+			log.Println("Error gagal membuat id event", err)
+			return
+		}
+		event.ID = id
+	}
+	if event.Version == "" {
+		event.Version = dto.EventVersion
+	}
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now()
+	}
+
+	b.mu.RLock()
+	handlers := append(append([]Handler{}, b.handlers[event.Type]...), b.handlers[AllEvents]...)
+	b.mu.RUnlock()
+
+	ctx = context.WithoutCancel(ctx)
+	for _, handler := range handlers {
+		go handler(ctx, event)
+	}
+}
@@ -0,0 +1,101 @@
+package health
+
+import (
+	"antrein/bc-dashboard/model/config"
+	"antrein/bc-dashboard/model/entity"
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type Repository struct {
+	cfg *config.Config
+	db  *sqlx.DB
+}
+
+func New(cfg *config.Config, db *sqlx.DB) *Repository {
+	return &Repository{
+		cfg: cfg,
+		db:  db,
+	}
+}
+
+func (r *Repository) GetHealthTargets(ctx context.Context) ([]entity.HealthTarget, error) {
+	targets := []entity.HealthTarget{}
+	q := `SELECT projects.id AS project_id, projects.tenant_id,
+		  (SELECT healthy FROM health_checks WHERE health_checks.project_id = projects.id ORDER BY checked_at DESC LIMIT 1) AS last_healthy
+		  FROM projects
+		  INNER JOIN configurations ON projects.id = configurations.project_id
+		  WHERE configurations.is_configure = true`
+	err := r.db.SelectContext(ctx, &targets, q)
+	return targets, err
+}
+
+// RecordHealthCheck stores the check. When the health changed an incident is
+// opened or the open one is closed, its id is returned.
+func (r *Repository) RecordHealthCheck(ctx context.Context, req entity.HealthCheck, changed bool) (string, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return "", err
+	}
+
+	q1 := `INSERT INTO health_checks (project_id, healthy, checked_at) VALUES ($1, $2, $3)`
+	_, err = tx.ExecContext(ctx, q1, req.ProjectID, req.Healthy, req.CheckedAt)
+	if err != nil {
+		tx.Rollback()
+		return "", err
+	}
+
+	var incidentID string
+	if changed {
+		if req.Healthy {
+			q2 := `UPDATE health_incidents SET ended_at = $1 WHERE project_id = $2 AND ended_at IS NULL RETURNING id`
+			err = tx.GetContext(ctx, &incidentID, q2, req.CheckedAt, req.ProjectID)
+		} else {
+			q2 := `INSERT INTO health_incidents (project_id, started_at) VALUES ($1, $2)
+				   ON CONFLICT (project_id) WHERE ended_at IS NULL DO UPDATE SET started_at = health_incidents.started_at
+				   RETURNING id`
+			err = tx.GetContext(ctx, &incidentID, q2, req.ProjectID, req.CheckedAt)
+		}
+		if err != nil && err != sql.ErrNoRows {
+			tx.Rollback()
+			return "", err
+		}
+	}
+
+	return incidentID, tx.Commit()
+}
+
+func (r *Repository) GetHealthChecks(ctx context.Context, projectID string, limit int) ([]entity.HealthCheck, error) {
+	checks := []entity.HealthCheck{}
+	q := `SELECT * FROM health_checks WHERE project_id = $1 ORDER BY checked_at DESC LIMIT $2`
+	err := r.db.SelectContext(ctx, &checks, q, projectID, limit)
+	return checks, err
+}
+
+func (r *Repository) GetHealthSummary(ctx context.Context, projectID string, since time.Time) (*entity.HealthSummary, error) {
+	summary := entity.HealthSummary{}
+	q := `SELECT COUNT(*) AS total, COUNT(*) FILTER (WHERE healthy) AS healthy, MIN(checked_at) AS first_checked_at
+		  FROM health_checks WHERE project_id = $1 AND checked_at >= $2`
+	err := r.db.GetContext(ctx, &summary, q, projectID, since)
+	if err != nil {
+		return nil, err
+	}
+	return &summary, nil
+}
+
+// GetIncidents returns the incidents that were open at some point since.
+func (r *Repository) GetIncidents(ctx context.Context, projectID string, since time.Time) ([]entity.HealthIncident, error) {
+	incidents := []entity.HealthIncident{}
+	q := `SELECT * FROM health_incidents WHERE project_id = $1 AND (ended_at IS NULL OR ended_at >= $2) ORDER BY started_at DESC`
+	err := r.db.SelectContext(ctx, &incidents, q, projectID, since)
+	return incidents, err
+}
+
+func (r *Repository) DeleteHealthChecksBefore(ctx context.Context, before time.Time) error {
+	q := `DELETE FROM health_checks WHERE checked_at < $1`
+	_, err := r.db.ExecContext(ctx, q, before)
+	return err
+}
@@ -0,0 +1,56 @@
+package history
+
+import (
+	"antrein/bc-dashboard/model/config"
+	"antrein/bc-dashboard/model/entity"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type Repository struct {
+	cfg *config.Config
+	db  *sqlx.DB
+}
+
+func New(cfg *config.Config, db *sqlx.DB) *Repository {
+	return &Repository{
+		cfg: cfg,
+		db:  db,
+	}
+}
+
+func (r *Repository) CreateProjectHistory(ctx context.Context, req entity.ProjectHistory) error {
+	q := `INSERT INTO project_histories (project_id, actor, action, detail, created_at) VALUES ($1, $2, $3, $4, $5)`
+	_, err := r.db.ExecContext(ctx, q, req.ProjectID, req.Actor, req.Action, req.Detail, req.CreatedAt)
+	return err
+}
+
+// RecordProjectHistory stores detail as the JSON body of a new history entry.
+// An empty actor marks changes made by the service itself.
+func (r *Repository) RecordProjectHistory(ctx context.Context, projectID, actor, action string, detail interface{}) error {
+	payload, err := json.Marshal(detail)
+	if err != nil {
+		return err
+	}
+	return r.CreateProjectHistory(ctx, entity.ProjectHistory{
+		ProjectID: projectID,
+		Actor: sql.NullString{
+			Valid:  actor != "",
+			String: actor,
+		},
+		Action:    action,
+		Detail:    payload,
+		CreatedAt: time.Now(),
+	})
+}
+
+func (r *Repository) GetProjectHistories(ctx context.Context, projectID string, limit int) ([]entity.ProjectHistory, error) {
+	histories := []entity.ProjectHistory{}
+	q := `SELECT * FROM project_histories WHERE project_id = $1 ORDER BY created_at DESC LIMIT $2`
+	err := r.db.SelectContext(ctx, &histories, q, projectID, limit)
+	return histories, err
+}
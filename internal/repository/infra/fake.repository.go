@@ -0,0 +1,135 @@
+package infra
+
+import (
+	"antrein/bc-dashboard/model/dto"
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	StorageKindHTML   = "html"
+	StorageKindAssets = "assets"
+)
+
+// Fake is an in-memory infra manager. Created projects are healthy until
+// SetHealth says otherwise, uploaded files are kept and served by
+// NewFakeServer under BaseURL.
+type Fake struct {
+	BaseURL string
+
+	mu       sync.Mutex
+	projects map[string]InfraBody
+	health   map[string]bool
+	files    map[string][]byte
+}
+
+func NewFake(baseURL string) *Fake {
+	return &Fake{
+		BaseURL:  strings.TrimSuffix(baseURL, "/"),
+		projects: map[string]InfraBody{},
+		health:   map[string]bool{},
+		files:    map[string][]byte{},
+	}
+}
+
+func (f *Fake) GetInfraProjects(ctx context.Context) ([]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	ids := make([]string, 0, len(f.projects))
+	for id := range f.projects {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// CreateInfraProject rejects bodies a real manager could not deploy: single
+// tenant projects need resources and shared ones must not carry any.
+func (f *Fake) CreateInfraProject(ctx context.Context, infraBody InfraBody) error {
+	switch infraBody.Mode {
+	case "", ModeMultiTenant:
+		if infraBody.Resources != nil {
+			return errors.New("multi tenant projects share the queue deployment and take no resources")
+		}
+	case ModeSingleTenant:
+		if infraBody.Resources == nil || infraBody.Resources.Replicas <= 0 {
+			return errors.New("single tenant projects need resources")
+		}
+	default:
+		return errors.New("unknown infra mode " + infraBody.Mode)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.projects[infraBody.ProjectID] = infraBody
+	if _, ok := f.health[infraBody.ProjectID]; !ok {
+		f.health[infraBody.ProjectID] = true
+	}
+	return nil
+}
+
+func (f *Fake) DeleteInfraProject(ctx context.Context, projectID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.projects, projectID)
+	delete(f.health, projectID)
+	return nil
+}
+
+func (f *Fake) CheckHealthProject(ctx context.Context, projectID string) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.projects[projectID]; !ok {
+		return false, ErrProjectNotFound
+	}
+	return f.health[projectID], nil
+}
+
+func (f *Fake) ClearInfraProject(ctx context.Context) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.projects = map[string]InfraBody{}
+	f.health = map[string]bool{}
+	return nil
+}
+
+func (f *Fake) UploadLogoFile(ctx context.Context, file dto.File) (string, error) {
+	return f.store(StorageKindAssets, file.Filename, file.Content), nil
+}
+
+func (f *Fake) UploadHTMLFile(ctx context.Context, file dto.File) (string, error) {
+	return f.store(StorageKindHTML, file.Filename+".html", file.Content), nil
+}
+
+// SetHealth changes what health checks report for the project.
+func (f *Fake) SetHealth(projectID string, healthiness bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.health[projectID] = healthiness
+}
+
+// Project returns the body the project was created with.
+func (f *Fake) Project(projectID string) (InfraBody, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	body, ok := f.projects[projectID]
+	return body, ok
+}
+
+// File returns an uploaded file by kind and stored name.
+func (f *Fake) File(kind, name string) ([]byte, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	content, ok := f.files[kind+"/"+name]
+	return content, ok
+}
+
+func (f *Fake) store(kind, name string, content []byte) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.files[kind+"/"+name] = append([]byte{}, content...)
+	return f.BaseURL + "/storage/" + kind + "/" + name
+}
@@ -0,0 +1,174 @@
+package infra
+
+import (
+	"antrein/bc-dashboard/model/config"
+	"antrein/bc-dashboard/model/dto"
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// NewFakeServer serves the infra manager HTTP API from fake, so Repository
+// can be pointed at it in place of a real manager. Requests are verified the
+// way auth tells Repository to authenticate them.
+func NewFakeServer(fake *Fake, auth config.InfraAuthConfig) http.Handler {
+	router := mux.NewRouter()
+	router.Use(fakeAuthMiddleware(auth))
+
+	router.HandleFunc("/kube/project", func(w http.ResponseWriter, r *http.Request) {
+		ids, _ := fake.GetInfraProjects(r.Context())
+		writeFakeJSON(w, http.StatusOK, ids)
+	}).Methods("GET")
+
+	router.HandleFunc("/kube/project", func(w http.ResponseWriter, r *http.Request) {
+		body := InfraBody{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.ProjectID == "" {
+			writeFakeError(w, http.StatusBadRequest, "invalid project body")
+			return
+		}
+		if err := fake.CreateInfraProject(r.Context(), body); err != nil {
+			writeFakeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeFakeJSON(w, http.StatusOK, body)
+	}).Methods("POST")
+
+	router.HandleFunc("/kube/project/{id}", func(w http.ResponseWriter, r *http.Request) {
+		fake.DeleteInfraProject(r.Context(), mux.Vars(r)["id"])
+		writeFakeJSON(w, http.StatusOK, nil)
+	}).Methods("DELETE")
+
+	router.HandleFunc("/kube/restart/project", func(w http.ResponseWriter, r *http.Request) {
+		fake.ClearInfraProject(r.Context())
+		writeFakeJSON(w, http.StatusOK, nil)
+	}).Methods("DELETE")
+
+	router.HandleFunc("/kube/health/{id}", func(w http.ResponseWriter, r *http.Request) {
+		healthiness, err := fake.CheckHealthProject(r.Context(), mux.Vars(r)["id"])
+		if errors.Is(err, ErrProjectNotFound) {
+			writeFakeError(w, http.StatusOK, err.Error())
+			return
+		}
+		writeFakeJSON(w, http.StatusOK, map[string]bool{"healthiness": healthiness})
+	}).Methods("GET")
+
+	router.HandleFunc("/storage/assets", func(w http.ResponseWriter, r *http.Request) {
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			writeFakeError(w, http.StatusBadRequest, "missing file")
+			return
+		}
+		defer file.Close()
+		content, err := io.ReadAll(file)
+		if err != nil {
+			writeFakeError(w, http.StatusBadRequest, "unreadable file")
+			return
+		}
+		url, _ := fake.UploadLogoFile(r.Context(), dto.File{Filename: header.Filename, Content: content})
+		writeFakeJSON(w, http.StatusOK, map[string]string{"url": url})
+	}).Methods("POST")
+
+	router.HandleFunc("/storage/html", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			FileName   string `json:"file_name"`
+			HTMLBase64 string `json:"html_base64"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.FileName == "" {
+			writeFakeError(w, http.StatusBadRequest, "invalid html body")
+			return
+		}
+		content, err := base64.StdEncoding.DecodeString(body.HTMLBase64)
+		if err != nil {
+			writeFakeError(w, http.StatusBadRequest, "invalid html_base64")
+			return
+		}
+		url, _ := fake.UploadHTMLFile(r.Context(), dto.File{Filename: body.FileName, Content: content})
+		writeFakeJSON(w, http.StatusOK, map[string]string{"url": url})
+	}).Methods("POST")
+
+	router.HandleFunc("/storage/{kind}/{name}", func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		content, ok := fake.File(vars["kind"], vars["name"])
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		contentType := mime.TypeByExtension(path.Ext(vars["name"]))
+		if contentType == "" {
+			contentType = http.DetectContentType(content)
+		}
+		w.Header().Set("Content-Type", contentType)
+		w.Write(content)
+	}).Methods("GET")
+
+	return router
+}
+
+// fakeAuthMiddleware rejects requests without valid credentials with 401.
+// Signed requests are also rejected when their nonce was seen before.
+func fakeAuthMiddleware(auth config.InfraAuthConfig) mux.MiddlewareFunc {
+	maxSkew := time.Duration(auth.MaxSkewSeconds) * time.Second
+	if maxSkew <= 0 {
+		maxSkew = DefaultMaxSkew
+	}
+	replays := &replayCache{seen: map[string]time.Time{}}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var err error
+			switch auth.Mode {
+			case AuthModeBearer:
+				err = VerifyBearer(r, auth.Token)
+			case AuthModeHMAC:
+				var body []byte
+				body, err = io.ReadAll(r.Body)
+				if err != nil {
+					break
+				}
+				r.Body = io.NopCloser(bytes.NewReader(body))
+				var nonce string
+				now := time.Now()
+				nonce, err = VerifyRequest(r, body, auth.HMACSecret, now, maxSkew)
+				if err == nil {
+					err = replays.check(nonce, now, maxSkew)
+				}
+			case AuthModeMTLS:
+				if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 {
+					err = ErrMissingCredentials
+				}
+			}
+			if err != nil {
+				writeFakeError(w, http.StatusUnauthorized, err.Error())
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func writeFakeJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status":  "success",
+		"message": "ok",
+		"data":    data,
+	})
+}
+
+func writeFakeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status":  "failed",
+		"message": message,
+	})
+}
@@ -0,0 +1,207 @@
+package infra
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"math/rand"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultMaxRetries       = 2
+	defaultBreakerThreshold = 5
+	defaultBreakerCooldown  = 30 * time.Second
+	retryBaseDelay          = 200 * time.Millisecond
+	retryMaxDelay           = 2 * time.Second
+	maxResponseSize         = 1 << 20
+)
+
+// operation describes one kind of manager call. Only idempotent operations
+// are retried.
+type operation struct {
+	name       string
+	method     string
+	timeout    time.Duration
+	idempotent bool
+}
+
+var (
+	opListProjects  = operation{name: "list_projects", method: http.MethodGet, timeout: 10 * time.Second, idempotent: true}
+	opCreateProject = operation{name: "create_project", method: http.MethodPost, timeout: 30 * time.Second}
+	opDeleteProject = operation{name: "delete_project", method: http.MethodDelete, timeout: 30 * time.Second, idempotent: true}
+	opCheckHealth   = operation{name: "check_health", method: http.MethodGet, timeout: 5 * time.Second, idempotent: true}
+	opClearProjects = operation{name: "clear_projects", method: http.MethodDelete, timeout: 60 * time.Second, idempotent: true}
+	// Files are stored under their name, uploading one again replaces it.
+	opUploadLogo = operation{name: "upload_logo", method: http.MethodPost, timeout: 30 * time.Second, idempotent: true}
+	opUploadHTML = operation{name: "upload_html", method: http.MethodPost, timeout: 30 * time.Second, idempotent: true}
+)
+
+// envelope is the response body of every manager endpoint.
+type envelope struct {
+	Status  string          `json:"status"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data"`
+}
+
+// breaker opens after threshold consecutive failures and lets a single trial
+// call through once cooldown has passed.
+type breaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	failures  int
+	openUntil time.Time
+	trial     bool
+}
+
+func (b *breaker) allow(now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.failures < b.threshold {
+		return true
+	}
+	if now.Before(b.openUntil) || b.trial {
+		return false
+	}
+	b.trial = true
+	return true
+}
+
+func (b *breaker) record(success bool, now time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.trial = false
+	if success {
+		b.failures = 0
+		return
+	}
+	b.failures++
+	if b.failures >= b.threshold {
+		b.openUntil = now.Add(b.cooldown)
+	}
+}
+
+// request is one manager call. body is kept as bytes so every retry sends it
+// again from the start.
+type request struct {
+	op          operation
+	path        string
+	contentType string
+	body        []byte
+}
+
+// do sends the request and decodes the data field of the response into out.
+// Network errors, 429 and 5xx responses count against the breaker and are
+// retried with jittered exponential backoff for idempotent operations.
+func (r *Repository) do(ctx context.Context, req request, out interface{}) error {
+	attempts := 1
+	if req.op.idempotent {
+		attempts += r.maxRetries
+	}
+
+	var lastErr *ManagerError
+	for attempt := 0; attempt < attempts; attempt++ {
+		if attempt > 0 {
+			if err := sleepBackoff(ctx, attempt); err != nil {
+				return &ManagerError{Operation: req.op.name, Err: err}
+			}
+		}
+		if !r.breaker.allow(time.Now()) {
+			return &ManagerError{Operation: req.op.name, Err: ErrCircuitOpen}
+		}
+
+		env, statusCode, err := r.send(ctx, req)
+		retryable := err != nil || statusCode == http.StatusTooManyRequests || statusCode >= 500
+		r.breaker.record(!retryable, time.Now())
+
+		if err != nil {
+			if ctx.Err() != nil {
+				return &ManagerError{Operation: req.op.name, Err: ctx.Err()}
+			}
+			lastErr = &ManagerError{Operation: req.op.name, Err: errors.Join(ErrUnavailable, err)}
+			continue
+		}
+		if retryable {
+			lastErr = &ManagerError{Operation: req.op.name, StatusCode: statusCode, Message: env.Message, Err: ErrUnavailable}
+			continue
+		}
+		if statusCode != http.StatusOK || env.Status == "failed" {
+			return &ManagerError{Operation: req.op.name, StatusCode: statusCode, Message: env.Message, Err: ErrRejected}
+		}
+
+		if out != nil && len(env.Data) > 0 && string(env.Data) != "null" {
+			if err := json.Unmarshal(env.Data, out); err != nil {
+				return &ManagerError{Operation: req.op.name, StatusCode: statusCode, Err: err}
+			}
+		}
+		return nil
+	}
+	return lastErr
+}
+
+// send makes a single attempt under the timeout of the operation. The body
+// of a response that is not JSON is kept as the error message.
+func (r *Repository) send(ctx context.Context, req request) (*envelope, int, error) {
+	timeout := req.op.timeout
+	if override, ok := r.cfg.Infra.TimeoutSeconds[req.op.name]; ok && override > 0 {
+		timeout = time.Duration(override) * time.Second
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var body io.Reader
+	if req.body != nil {
+		body = bytes.NewReader(req.body)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, req.op.method, strings.TrimSuffix(r.cfg.Infra.ManagerURL, "/")+req.path, body)
+	if err != nil {
+		return nil, 0, err
+	}
+	if req.contentType != "" {
+		httpReq.Header.Set("Content-Type", req.contentType)
+	}
+	httpReq.Header.Set("Accept", "application/json")
+	if err := r.authenticate(httpReq, req.body); err != nil {
+		return nil, 0, err
+	}
+
+	resp, err := r.client.Do(httpReq)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return nil, resp.StatusCode, err
+	}
+
+	env := &envelope{}
+	if len(bytes.TrimSpace(raw)) > 0 && json.Unmarshal(raw, env) != nil {
+		env.Message = strings.TrimSpace(string(raw))
+	}
+	return env, resp.StatusCode, nil
+}
+
+func sleepBackoff(ctx context.Context, attempt int) error {
+	delay := retryBaseDelay << (attempt - 1)
+	if delay > retryMaxDelay {
+		delay = retryMaxDelay
+	}
+	delay = delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
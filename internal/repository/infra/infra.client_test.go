@@ -0,0 +1,234 @@
+package infra
+
+import (
+	"antrein/bc-dashboard/model/config"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newTestRepository(t *testing.T, url string, infraCfg config.InfraConfig) *Repository {
+	t.Helper()
+	infraCfg.ManagerURL = url
+	repo, err := New(&config.Config{Infra: infraCfg})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	return repo
+}
+
+func TestClientAuth(t *testing.T) {
+	tests := []struct {
+		name   string
+		server config.InfraAuthConfig
+		client config.InfraAuthConfig
+		wantOK bool
+	}{
+		{
+			name:   "bearer",
+			server: config.InfraAuthConfig{Mode: AuthModeBearer, Token: "secret-token"},
+			client: config.InfraAuthConfig{Mode: AuthModeBearer, Token: "secret-token"},
+			wantOK: true,
+		},
+		{
+			name:   "bearer wrong token",
+			server: config.InfraAuthConfig{Mode: AuthModeBearer, Token: "secret-token"},
+			client: config.InfraAuthConfig{Mode: AuthModeBearer, Token: "other-token"},
+		},
+		{
+			name:   "bearer missing token",
+			server: config.InfraAuthConfig{Mode: AuthModeBearer, Token: "secret-token"},
+			client: config.InfraAuthConfig{Mode: AuthModeNone},
+		},
+		{
+			name:   "hmac",
+			server: config.InfraAuthConfig{Mode: AuthModeHMAC, HMACSecret: "hmac-secret"},
+			client: config.InfraAuthConfig{Mode: AuthModeHMAC, HMACSecret: "hmac-secret"},
+			wantOK: true,
+		},
+		{
+			name:   "hmac wrong secret",
+			server: config.InfraAuthConfig{Mode: AuthModeHMAC, HMACSecret: "hmac-secret"},
+			client: config.InfraAuthConfig{Mode: AuthModeHMAC, HMACSecret: "other-secret"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := NewFake("http://infra.test")
+			server := httptest.NewServer(NewFakeServer(fake, tt.server))
+			defer server.Close()
+			repo := newTestRepository(t, server.URL, config.InfraConfig{Auth: tt.client})
+
+			ctx := context.Background()
+			err := repo.CreateInfraProject(ctx, InfraBody{ProjectID: "konser", ProjectDomain: "konser.antrein.test", URLPath: "/"})
+			if !tt.wantOK {
+				var managerErr *ManagerError
+				if !errors.As(err, &managerErr) || !errors.Is(err, ErrRejected) || managerErr.StatusCode != http.StatusUnauthorized {
+					t.Fatalf("CreateInfraProject() error = %v, want rejected with status 401", err)
+				}
+				if _, ok := fake.Project("konser"); ok {
+					t.Fatal("unauthenticated request created the project")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CreateInfraProject() error = %v", err)
+			}
+
+			healthiness, err := repo.CheckHealthProject(ctx, "konser")
+			if err != nil || !healthiness {
+				t.Fatalf("CheckHealthProject() = %v, %v, want true", healthiness, err)
+			}
+		})
+	}
+}
+
+// flakyHandler answers the first failures requests with 503 before passing
+// them on to next.
+func flakyHandler(next http.Handler, failures int32, calls *int32) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(calls, 1) <= failures {
+			writeFakeError(w, http.StatusServiceUnavailable, "try again")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func TestClientRetry(t *testing.T) {
+	fake := NewFake("http://infra.test")
+	fake.CreateInfraProject(context.Background(), InfraBody{ProjectID: "konser"})
+
+	var calls int32
+	server := httptest.NewServer(flakyHandler(NewFakeServer(fake, config.InfraAuthConfig{}), 2, &calls))
+	defer server.Close()
+	repo := newTestRepository(t, server.URL, config.InfraConfig{MaxRetries: 2})
+
+	projects, err := repo.GetInfraProjects(context.Background())
+	if err != nil {
+		t.Fatalf("GetInfraProjects() error = %v", err)
+	}
+	if len(projects) != 1 || projects[0] != "konser" {
+		t.Errorf("GetInfraProjects() = %v, want [konser]", projects)
+	}
+	if calls != 3 {
+		t.Errorf("manager was called %d times, want 3", calls)
+	}
+}
+
+func TestClientDoesNotRetryCreate(t *testing.T) {
+	fake := NewFake("http://infra.test")
+	var calls int32
+	server := httptest.NewServer(flakyHandler(NewFakeServer(fake, config.InfraAuthConfig{}), 1, &calls))
+	defer server.Close()
+	repo := newTestRepository(t, server.URL, config.InfraConfig{MaxRetries: 2})
+
+	err := repo.CreateInfraProject(context.Background(), InfraBody{ProjectID: "konser"})
+	if !errors.Is(err, ErrUnavailable) {
+		t.Fatalf("CreateInfraProject() error = %v, want %v", err, ErrUnavailable)
+	}
+	if calls != 1 {
+		t.Errorf("manager was called %d times, want 1", calls)
+	}
+}
+
+func TestClientBreaker(t *testing.T) {
+	fake := NewFake("http://infra.test")
+	var calls int32
+	server := httptest.NewServer(flakyHandler(NewFakeServer(fake, config.InfraAuthConfig{}), 1<<30, &calls))
+	defer server.Close()
+	repo := newTestRepository(t, server.URL, config.InfraConfig{MaxRetries: 1, BreakerThreshold: 2, BreakerCooldownSeconds: 60})
+
+	ctx := context.Background()
+	if _, err := repo.GetInfraProjects(ctx); !errors.Is(err, ErrUnavailable) {
+		t.Fatalf("GetInfraProjects() error = %v, want %v", err, ErrUnavailable)
+	}
+	if calls != 2 {
+		t.Fatalf("manager was called %d times, want 2", calls)
+	}
+
+	if _, err := repo.GetInfraProjects(ctx); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("GetInfraProjects() error = %v, want %v", err, ErrCircuitOpen)
+	}
+	if calls != 2 {
+		t.Errorf("manager was called %d times while the circuit was open, want 2", calls)
+	}
+}
+
+func TestClientSignsEachRequestWithNewNonce(t *testing.T) {
+	auth := config.InfraAuthConfig{Mode: AuthModeHMAC, HMACSecret: "hmac-secret"}
+	fake := NewFake("http://infra.test")
+	server := httptest.NewServer(NewFakeServer(fake, auth))
+	defer server.Close()
+	repo := newTestRepository(t, server.URL, config.InfraConfig{Auth: auth})
+
+	// Identical requests within the same second must not be taken for replays.
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		if _, err := repo.GetInfraProjects(ctx); err != nil {
+			t.Fatalf("GetInfraProjects() call %d error = %v", i+1, err)
+		}
+	}
+}
+
+func TestFakeServerRejectsReplay(t *testing.T) {
+	auth := config.InfraAuthConfig{Mode: AuthModeHMAC, HMACSecret: "hmac-secret"}
+	server := httptest.NewServer(NewFakeServer(NewFake("http://infra.test"), auth))
+	defer server.Close()
+
+	req, err := http.NewRequest(http.MethodGet, server.URL+"/kube/project", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := SignRequest(req, nil, auth.HMACSecret, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, want := range []int{http.StatusOK, http.StatusUnauthorized} {
+		resp, err := http.DefaultClient.Do(req.Clone(context.Background()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != want {
+			t.Errorf("request %d status = %d, want %d", i+1, resp.StatusCode, want)
+		}
+	}
+}
+
+func TestCheckHealthProjectNotFound(t *testing.T) {
+	tests := []struct {
+		name         string
+		status       int
+		wantNotFound bool
+	}{
+		{name: "failed status", status: http.StatusOK, wantNotFound: true},
+		{name: "not found", status: http.StatusNotFound, wantNotFound: true},
+		{name: "unauthorized", status: http.StatusUnauthorized},
+		{name: "forbidden", status: http.StatusForbidden},
+		{name: "bad request", status: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				writeFakeError(w, tt.status, "rejected")
+			}))
+			defer server.Close()
+			repo := newTestRepository(t, server.URL, config.InfraConfig{})
+
+			_, err := repo.CheckHealthProject(context.Background(), "konser")
+			if got := errors.Is(err, ErrProjectNotFound); got != tt.wantNotFound {
+				t.Errorf("CheckHealthProject() error = %v, not found = %v, want %v", err, got, tt.wantNotFound)
+			}
+			if !tt.wantNotFound && !errors.Is(err, ErrRejected) {
+				t.Errorf("CheckHealthProject() error = %v, want %v", err, ErrRejected)
+			}
+		})
+	}
+}
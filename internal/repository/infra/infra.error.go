@@ -0,0 +1,44 @@
+package infra
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrCircuitOpen is returned without calling the manager while it is
+	// considered down.
+	ErrCircuitOpen = errors.New("infra manager circuit is open")
+	// ErrRejected is a request the manager answered with a failed status.
+	ErrRejected = errors.New("infra manager rejected the request")
+	// ErrUnavailable is a request that failed on the network or with a server
+	// error after all retries.
+	ErrUnavailable = errors.New("infra manager is unavailable")
+)
+
+// ManagerError is a failed infra manager call. StatusCode is zero when no
+// response was received, Message is the error message of the manager.
+type ManagerError struct {
+	Operation  string
+	StatusCode int
+	Message    string
+	Err        error
+}
+
+func (e *ManagerError) Error() string {
+	msg := fmt.Sprintf("infra manager %s failed", e.Operation)
+	if e.StatusCode != 0 {
+		msg += fmt.Sprintf(", status code: %d", e.StatusCode)
+	}
+	if e.Message != "" {
+		msg += ", " + e.Message
+	}
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+func (e *ManagerError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,33 @@
+package infra
+
+import (
+	"antrein/bc-dashboard/model/dto"
+	"context"
+	"errors"
+)
+
+// InfraManager is everything the dashboard asks of the infra manager. It is
+// implemented by Repository over HTTP and by Fake in memory.
+type InfraManager interface {
+	GetInfraProjects(ctx context.Context) ([]string, error)
+	CreateInfraProject(ctx context.Context, infraBody InfraBody) error
+	DeleteInfraProject(ctx context.Context, projectID string) error
+	CheckHealthProject(ctx context.Context, projectID string) (bool, error)
+	ClearInfraProject(ctx context.Context) error
+	UploadLogoFile(ctx context.Context, file dto.File) (string, error)
+	UploadHTMLFile(ctx context.Context, file dto.File) (string, error)
+}
+
+// InfraBody registers the project route. Resources is only set for single
+// tenant projects, which get a deployment of their own.
+type InfraBody struct {
+	ProjectID     string          `json:"project_id"`
+	ProjectDomain string          `json:"project_domain"`
+	URLPath       string          `json:"url_path"`
+	Mode          string          `json:"mode,omitempty"`
+	Resources     *InfraResources `json:"resources,omitempty"`
+}
+
+// ErrProjectNotFound is returned for health checks of projects the manager
+// does not run.
+var ErrProjectNotFound = errors.New("Project not found")
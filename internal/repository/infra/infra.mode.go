@@ -0,0 +1,82 @@
+package infra
+
+import (
+	"antrein/bc-dashboard/model/config"
+	"fmt"
+)
+
+// In multi tenant mode every project shares one queue deployment and only
+// registers its route there. In single tenant mode every project gets its
+// own deployment sized by Resources.
+const (
+	ModeMultiTenant  = "multi_tenant"
+	ModeSingleTenant = "single_tenant"
+)
+
+const (
+	ResourceSizeSmall   = "small"
+	ResourceSizeMedium  = "medium"
+	ResourceSizeLarge   = "large"
+	DefaultResourceSize = ResourceSizeSmall
+)
+
+type InfraResources struct {
+	CPU      string `json:"cpu"`
+	Memory   string `json:"memory"`
+	Replicas int    `json:"replicas"`
+}
+
+var resourcePresets = map[string]InfraResources{
+	ResourceSizeSmall:  {CPU: "250m", Memory: "256Mi", Replicas: 1},
+	ResourceSizeMedium: {CPU: "500m", Memory: "512Mi", Replicas: 2},
+	ResourceSizeLarge:  {CPU: "1", Memory: "1Gi", Replicas: 3},
+}
+
+func IsMode(mode string) bool {
+	return mode == ModeMultiTenant || mode == ModeSingleTenant
+}
+
+// DefaultMode is the configured mode, multi tenant when none is set.
+func DefaultMode(cfg *config.Config) string {
+	if cfg.Infra.Mode == "" {
+		return ModeMultiTenant
+	}
+	return cfg.Infra.Mode
+}
+
+// ProjectMode is the mode a project runs in, projectMode being its own
+// override which may be empty.
+func ProjectMode(cfg *config.Config, projectMode string) string {
+	if projectMode != "" {
+		return projectMode
+	}
+	return DefaultMode(cfg)
+}
+
+func ValidateMode(cfg *config.Config) error {
+	if !IsMode(DefaultMode(cfg)) {
+		return fmt.Errorf("infra mode %q is not multi_tenant or single_tenant", cfg.Infra.Mode)
+	}
+	for size, resources := range cfg.Infra.ResourceSizes {
+		if resources.CPU == "" || resources.Memory == "" || resources.Replicas <= 0 {
+			return fmt.Errorf("infra resource size %q needs cpu, memory and replicas", size)
+		}
+	}
+	return nil
+}
+
+// Resources looks the size up in the configured resource sizes first, so the
+// presets can be overridden and extended per deployment.
+func Resources(cfg *config.Config, size string) (*InfraResources, bool) {
+	if resources, ok := cfg.Infra.ResourceSizes[size]; ok {
+		return &InfraResources{
+			CPU:      resources.CPU,
+			Memory:   resources.Memory,
+			Replicas: resources.Replicas,
+		}, true
+	}
+	if resources, ok := resourcePresets[size]; ok {
+		return &resources, true
+	}
+	return nil, false
+}
@@ -4,84 +4,118 @@ import (
 	"antrein/bc-dashboard/model/config"
 	"antrein/bc-dashboard/model/dto"
 	"bytes"
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
 	"mime/multipart"
 	"net/http"
+	"net/url"
+	"time"
 )
 
+// Repository talks to the infra manager over HTTP. One client and circuit
+// breaker is shared by every call.
 type Repository struct {
-	cfg *config.Config
+	cfg        *config.Config
+	client     *http.Client
+	breaker    *breaker
+	maxRetries int
 }
 
-func New(cfg *config.Config) *Repository {
-	return &Repository{
-		cfg: cfg,
+func New(cfg *config.Config) (*Repository, error) {
+	maxRetries := cfg.Infra.MaxRetries
+	if maxRetries <= 0 {
+		maxRetries = defaultMaxRetries
+	}
+	threshold := cfg.Infra.BreakerThreshold
+	if threshold <= 0 {
+		threshold = defaultBreakerThreshold
+	}
+	cooldown := time.Duration(cfg.Infra.BreakerCooldownSeconds) * time.Second
+	if cooldown <= 0 {
+		cooldown = defaultBreakerCooldown
+	}
+
+	transport := &http.Transport{
+		Proxy:               http.ProxyFromEnvironment,
+		MaxIdleConns:        100,
+		MaxIdleConnsPerHost: 20,
+		IdleConnTimeout:     90 * time.Second,
+	}
+
+	auth := cfg.Infra.Auth
+	switch auth.Mode {
+	case "", AuthModeNone:
+	case AuthModeBearer:
+		if auth.Token == "" {
+			return nil, errors.New("infra auth mode bearer needs a token")
+		}
+	case AuthModeHMAC:
+		if auth.HMACSecret == "" {
+			return nil, errors.New("infra auth mode hmac needs an hmac_secret")
+		}
+	case AuthModeMTLS:
+		if auth.ClientCertFile == "" || auth.ClientKeyFile == "" {
+			return nil, errors.New("infra auth mode mtls needs a client certificate and key")
+		}
+		tlsConfig, err := LoadTLSConfig(auth.ClientCertFile, auth.ClientKeyFile, auth.CAFile, false)
+		if err != nil {
+			return nil, err
+		}
+		transport.TLSClientConfig = tlsConfig
+	default:
+		return nil, fmt.Errorf("unknown infra auth mode %q", auth.Mode)
 	}
-}
 
-type InfraBody struct {
-	ProjectID     string `json:"project_id"`
-	ProjectDomain string `json:"project_domain"`
-	URLPath       string `json:"url_path"`
+	return &Repository{
+		cfg:    cfg,
+		client: &http.Client{Transport: transport},
+		breaker: &breaker{
+			threshold: threshold,
+			cooldown:  cooldown,
+		},
+		maxRetries: maxRetries,
+	}, nil
 }
 
-func (r *Repository) GetInfraProjects(client *http.Client) ([]string, error) {
-	req, err := http.NewRequest("GET", r.cfg.Infra.ManagerURL+"/kube/project", nil)
-	if err != nil {
-		return nil, err
-	}
-
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	var result struct {
-		Data []string `json:"data"`
-	}
-
-	bodyBytes, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
+// authenticate adds the credentials of the configured auth mode. mTLS is
+// handled by the transport.
+func (r *Repository) authenticate(req *http.Request, body []byte) error {
+	auth := r.cfg.Infra.Auth
+	switch auth.Mode {
+	case AuthModeBearer:
+		req.Header.Set("Authorization", "Bearer "+auth.Token)
+	case AuthModeHMAC:
+		return SignRequest(req, body, auth.HMACSecret, time.Now())
 	}
+	return nil
+}
 
-	err = json.Unmarshal(bodyBytes, &result)
+func (r *Repository) GetInfraProjects(ctx context.Context) ([]string, error) {
+	var projects []string
+	err := r.do(ctx, request{op: opListProjects, path: "/kube/project"}, &projects)
 	if err != nil {
 		return nil, err
 	}
-
-	return result.Data, nil
+	return projects, nil
 }
 
-func (r *Repository) CreateInfraProject(client *http.Client, infraBody InfraBody) error {
+func (r *Repository) CreateInfraProject(ctx context.Context, infraBody InfraBody) error {
 	jsonData, err := json.Marshal(infraBody)
 	if err != nil {
 		return err
 	}
-	req, err := http.NewRequest("POST", r.cfg.Infra.ManagerURL+"/kube/project", bytes.NewBuffer(jsonData))
-	req.Header.Set("Content-Type", "application/json")
-	if err != nil {
-		return err
-	}
-
-	resp, err := client.Do(req)
-	if err != nil {
-		return err
-	}
-	resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("failed to create project, status code: %d", resp.StatusCode)
-	}
-	return nil
+	return r.do(ctx, request{
+		op:          opCreateProject,
+		path:        "/kube/project",
+		contentType: "application/json",
+		body:        jsonData,
+	}, nil)
 }
 
-func (r *Repository) UploadLogoFile(client *http.Client, file dto.File) (string, error) {
+func (r *Repository) UploadLogoFile(ctx context.Context, file dto.File) (string, error) {
 	var b bytes.Buffer
 	w := multipart.NewWriter(&b)
 
@@ -92,145 +126,82 @@ func (r *Repository) UploadLogoFile(client *http.Client, file dto.File) (string,
 	if _, err = fw.Write(file.Content); err != nil {
 		return "", err
 	}
-
-	w.Close()
-
-	req, err := http.NewRequest("POST", r.cfg.Infra.ManagerURL+"/storage/assets", &b)
-	if err != nil {
-		return "", err
-	}
-	req.Header.Set("Content-Type", w.FormDataContentType())
-
-	resp, err := client.Do(req)
-	if err != nil {
+	if err = w.Close(); err != nil {
 		return "", err
 	}
-	defer resp.Body.Close()
 
 	var result struct {
-		Status  string `json:"status"`
-		Message string `json:"message"`
-		Data    struct {
-			URL string `json:"url"`
-		} `json:"data"`
+		URL string `json:"url"`
 	}
-
-	bodyBytes, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return "", err
-	}
-
-	err = json.Unmarshal(bodyBytes, &result)
+	err = r.do(ctx, request{
+		op:          opUploadLogo,
+		path:        "/storage/assets",
+		contentType: w.FormDataContentType(),
+		body:        b.Bytes(),
+	}, &result)
 	if err != nil {
 		return "", err
 	}
-
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("failed to upload file, status code: %d", resp.StatusCode)
-	}
-
-	return result.Data.URL, nil
+	return result.URL, nil
 }
 
-func (r *Repository) UploadHTMLFile(client *http.Client, file dto.File) error {
-	encodedContent := base64.StdEncoding.EncodeToString(file.Content)
-
+// UploadHTMLFile returns the URL the manager reports for the page, which is
+// empty for managers that do not report one.
+func (r *Repository) UploadHTMLFile(ctx context.Context, file dto.File) (string, error) {
 	payload := map[string]string{
 		"file_name":   file.Filename,
-		"html_base64": encodedContent,
+		"html_base64": base64.StdEncoding.EncodeToString(file.Content),
 	}
 
 	jsonData, err := json.Marshal(payload)
 	if err != nil {
-		return err
+		return "", err
 	}
 
-	req, err := http.NewRequest("POST", r.cfg.Infra.ManagerURL+"/storage/html", bytes.NewBuffer(jsonData))
-	if err != nil {
-		return err
+	var result struct {
+		URL string `json:"url"`
 	}
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := client.Do(req)
+	err = r.do(ctx, request{
+		op:          opUploadHTML,
+		path:        "/storage/html",
+		contentType: "application/json",
+		body:        jsonData,
+	}, &result)
 	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("failed to upload file, status code: %d", resp.StatusCode)
+		return "", err
 	}
-
-	return nil
+	return result.URL, nil
 }
 
-func (r *Repository) CheckHealthProject(client *http.Client, projectId string) (bool, error) {
-	req, err := http.NewRequest("GET", r.cfg.Infra.ManagerURL+"/kube/health/"+projectId, nil)
-	if err != nil {
-		return false, err
-	}
-
-	resp, err := client.Do(req)
-	if err != nil {
-		return false, err
-	}
-	defer resp.Body.Close()
-
+// CheckHealthProject reports ErrProjectNotFound when the manager does not
+// know the project, that is a 404 or a 200 carrying a failed status. Other
+// rejections such as failed authentication stay ErrRejected.
+func (r *Repository) CheckHealthProject(ctx context.Context, projectID string) (bool, error) {
 	var result struct {
-		Status string `json:"status"`
-		Data   struct {
-			Healthiness bool `json:"healthiness"`
-		} `json:"data"`
-	}
-
-	bodyBytes, err := io.ReadAll(resp.Body)
-	if err != nil {
+		Healthiness bool `json:"healthiness"`
+	}
+	err := r.do(ctx, request{
+		op:   opCheckHealth,
+		path: "/kube/health/" + url.PathEscape(projectID),
+	}, &result)
+	if err != nil {
+		var managerErr *ManagerError
+		if errors.As(err, &managerErr) && errors.Is(err, ErrRejected) &&
+			(managerErr.StatusCode == http.StatusNotFound || managerErr.StatusCode == http.StatusOK) {
+			managerErr.Err = ErrProjectNotFound
+		}
 		return false, err
 	}
-
-	err = json.Unmarshal(bodyBytes, &result)
-	if err != nil {
-		return false, err
-	}
-
-	if result.Status == "failed" {
-		return false, errors.New("Project not found")
-	}
-	return result.Data.Healthiness, nil
+	return result.Healthiness, nil
 }
 
-func (r *Repository) DeleteInfraProject(client *http.Client, projectId string) error {
-	req, err := http.NewRequest("DELETE", r.cfg.Infra.ManagerURL+"/kube/project/"+projectId, nil)
-	if err != nil {
-		return err
-	}
-
-	resp, err := client.Do(req)
-	if err != nil {
-		return err
-	}
-	resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("failed to delete project, status code: %d", resp.StatusCode)
-	}
-	return nil
+func (r *Repository) DeleteInfraProject(ctx context.Context, projectID string) error {
+	return r.do(ctx, request{
+		op:   opDeleteProject,
+		path: "/kube/project/" + url.PathEscape(projectID),
+	}, nil)
 }
 
-func (r *Repository) ClearInfraProject(client *http.Client) error {
-	req, err := http.NewRequest("DELETE", r.cfg.Infra.ManagerURL+"/kube/restart/project", nil)
-	if err != nil {
-		return err
-	}
-
-	resp, err := client.Do(req)
-	if err != nil {
-		return err
-	}
-	resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("failed to clear project, status code: %d", resp.StatusCode)
-	}
-	return nil
+func (r *Repository) ClearInfraProject(ctx context.Context) error {
+	return r.do(ctx, request{op: opClearProjects, path: "/kube/restart/project"}, nil)
 }
@@ -7,18 +7,19 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
-	"net/http"
+	"strings"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
 type Repository struct {
 	cfg       *config.Config
 	db        *sqlx.DB
-	infraRepo *infra.Repository
+	infraRepo infra.InfraManager
 }
 
-func New(cfg *config.Config, db *sqlx.DB, infraRepo *infra.Repository) *Repository {
+func New(cfg *config.Config, db *sqlx.DB, infraRepo infra.InfraManager) *Repository {
 	return &Repository{
 		cfg:       cfg,
 		db:        db,
@@ -32,8 +33,8 @@ func (r *Repository) CreateNewProject(ctx context.Context, req entity.Project) (
 		ReadOnly:  false,
 	})
 	project := req
-	q1 := `INSERT INTO projects (id, name, tenant_id, created_at) VALUES ($1, $2, $3, $4)`
-	_, err = tx.ExecContext(ctx, q1, req.ID, req.Name, req.TenantID, req.CreatedAt)
+	q1 := `INSERT INTO projects (id, name, tenant_id, infra_mode, resource_size, created_at) VALUES ($1, $2, $3, $4, $5, $6)`
+	_, err = tx.ExecContext(ctx, q1, req.ID, req.Name, req.TenantID, req.InfraMode, req.ResourceSize, req.CreatedAt)
 	if err != nil {
 		tx.Rollback()
 		return nil, err
@@ -54,6 +55,47 @@ func (r *Repository) CreateNewProject(ctx context.Context, req entity.Project) (
 	return &project, err
 }
 
+func (r *Repository) CreateNewProjectWithConfig(ctx context.Context, req entity.Project, config entity.Configuration) (*entity.Project, error) {
+	tx, err := r.db.BeginTxx(ctx, &sql.TxOptions{
+		Isolation: 2,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return nil, err
+	}
+	project := req
+	q1 := `INSERT INTO projects (id, name, tenant_id, infra_mode, resource_size, created_at) VALUES ($1, $2, $3, $4, $5, $6)`
+	_, err = tx.ExecContext(ctx, q1, req.ID, req.Name, req.TenantID, req.InfraMode, req.ResourceSize, req.CreatedAt)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	var themeID string
+	q2 := `INSERT INTO themes (tenant_id, project_id, name, queue_page_style, queue_html_page, queue_page_base_color, queue_page_title, queue_page_logo)
+		   VALUES ($1, $2, 'default', $3, $4, $5, $6, $7) RETURNING id`
+	err = tx.GetContext(ctx, &themeID, q2, req.TenantID, req.ID, config.QueuePageStyle, config.QueueHTMLPage, config.QueuePageBaseColor, config.QueuePageTitle, config.QueuePageLogo)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	q3 := `INSERT INTO configurations (project_id, threshold, session_time, max_users_in_queue, queue_start, queue_end, active_theme_id)
+		   VALUES ($1, $2, $3, $4, $5, $6, $7)`
+	_, err = tx.ExecContext(ctx, q3, req.ID, config.Threshold, config.SessionTime, config.MaxUsersInQueue, config.QueueStart, config.QueueEnd, themeID)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	return &project, err
+}
+
 func (r *Repository) GetTenantByID(ctx context.Context, id string) (*entity.Project, error) {
 	project := entity.Project{}
 	q := `SELECT * FROM projects WHERE id = $1 LIMIT 1`
@@ -66,7 +108,16 @@ func (r *Repository) GetTenantByID(ctx context.Context, id string) (*entity.Proj
 
 func (r *Repository) GetTenantProjectByID(ctx context.Context, id, tenantID string) (*entity.ProjectWithConfig, error) {
 	project := entity.ProjectWithConfig{}
-	q := `SELECT * FROM projects INNER JOIN configurations ON projects.id = configurations.project_id WHERE projects.id = $1 AND projects.tenant_id = $2 LIMIT 1`
+	q := `SELECT projects.*, configurations.*,
+		  COALESCE(themes.queue_page_style, 'base') AS queue_page_style,
+		  themes.queue_html_page,
+		  themes.queue_page_base_color,
+		  themes.queue_page_title,
+		  themes.queue_page_logo
+		  FROM projects
+		  INNER JOIN configurations ON projects.id = configurations.project_id
+		  LEFT JOIN themes ON themes.id = configurations.active_theme_id
+		  WHERE projects.id = $1 AND projects.tenant_id = $2 LIMIT 1`
 	err := r.db.GetContext(ctx, &project, q, id, tenantID)
 	if err != nil {
 		return nil, err
@@ -74,6 +125,13 @@ func (r *Repository) GetTenantProjectByID(ctx context.Context, id, tenantID stri
 	return &project, err
 }
 
+func (r *Repository) IsPremiumTenant(ctx context.Context, tenantID string) (bool, error) {
+	var isPremium bool
+	q := `SELECT is_premium FROM tenants WHERE id = $1`
+	err := r.db.GetContext(ctx, &isPremium, q, tenantID)
+	return isPremium, err
+}
+
 func (r *Repository) GetTenantProjects(ctx context.Context, tenantID string) ([]entity.Project, error) {
 	projects := []entity.Project{}
 	q := `SELECT * FROM projects WHERE tenant_id = $1 ORDER BY id`
@@ -81,6 +139,107 @@ func (r *Repository) GetTenantProjects(ctx context.Context, tenantID string) ([]
 	return projects, err
 }
 
+var projectSortColumns = map[string]string{
+	"name":       "projects.name",
+	"created_at": "projects.created_at",
+	"updated_at": "COALESCE(projects.updated_at, projects.created_at)",
+}
+
+// projectListTables joins every project with its latest recorded health
+// check, projects that were never checked have no health.
+const projectListTables = `projects
+	INNER JOIN configurations ON projects.id = configurations.project_id
+	LEFT JOIN LATERAL (SELECT healthy FROM health_checks WHERE health_checks.project_id = projects.id ORDER BY checked_at DESC LIMIT 1) last_check ON true`
+
+func buildProjectFilter(filter entity.ProjectFilter) (string, []interface{}) {
+	where := []string{"projects.tenant_id = $1"}
+	args := []interface{}{filter.TenantID}
+	if filter.Search != "" {
+		args = append(args, "%"+filter.Search+"%")
+		where = append(where, fmt.Sprintf("(projects.name ILIKE $%d OR projects.id ILIKE $%d)", len(args), len(args)))
+	}
+	if filter.Configured.Valid {
+		args = append(args, filter.Configured.Bool)
+		where = append(where, fmt.Sprintf("configurations.is_configure = $%d", len(args)))
+	}
+	if filter.Healthy.Valid {
+		args = append(args, filter.Healthy.Bool)
+		where = append(where, fmt.Sprintf("last_check.healthy = $%d", len(args)))
+	}
+	return strings.Join(where, " AND "), args
+}
+
+func (r *Repository) GetFilteredTenantProjects(ctx context.Context, filter entity.ProjectFilter) ([]entity.ProjectSummary, int, error) {
+	projects := []entity.ProjectSummary{}
+	where, args := buildProjectFilter(filter)
+
+	total := 0
+	qCount := `SELECT COUNT(*) FROM ` + projectListTables + ` WHERE ` + where
+	err := r.db.GetContext(ctx, &total, qCount, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	sortColumn, ok := projectSortColumns[filter.SortBy]
+	if !ok {
+		sortColumn = "projects.id"
+	}
+	sortOrder := "ASC"
+	if filter.SortOrder == "desc" {
+		sortOrder = "DESC"
+	}
+
+	q := `SELECT projects.*, configurations.is_configure, last_check.healthy AS last_healthy FROM ` + projectListTables + ` WHERE ` + where +
+		fmt.Sprintf(" ORDER BY %s %s, projects.id", sortColumn, sortOrder)
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit, filter.Offset)
+		q += fmt.Sprintf(" LIMIT $%d OFFSET $%d", len(args)-1, len(args))
+	}
+	err = r.db.SelectContext(ctx, &projects, q, args...)
+	return projects, total, err
+}
+
+func (r *Repository) UpdateProject(ctx context.Context, req entity.Project) error {
+	q := `UPDATE projects
+		  SET name = $1,
+		  description = $2,
+		  tags = $3,
+		  environment = $4,
+		  contact_email = $5,
+		  updated_at = now()
+		  WHERE id = $6 AND tenant_id = $7`
+	resp, err := r.db.ExecContext(ctx, q, req.Name, req.Description, pq.Array(req.Tags.StringArray), req.Environment, req.ContactEmail, req.ID, req.TenantID)
+	if err != nil {
+		return err
+	}
+	affected, err := resp.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// DeleteProject removes the project of the tenant together with everything
+// that belongs to it. It returns sql.ErrNoRows when there is no such project.
+func (r *Repository) DeleteProject(ctx context.Context, id, tenantID string) error {
+	q := `DELETE FROM projects WHERE id = $1 AND tenant_id = $2`
+	resp, err := r.db.ExecContext(ctx, q, id, tenantID)
+	if err != nil {
+		return err
+	}
+	affected, err := resp.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
 func (r *Repository) GetProjects(ctx context.Context, page int, pageSize int) ([]entity.Project, error) {
 	projects := []entity.Project{}
 	q := `SELECT * FROM projects ORDER BY name LIMIT $1 OFFSET $2`
@@ -117,8 +276,7 @@ func (r *Repository) ClearAllProjects(ctx context.Context) error {
 		return err
 	}
 
-	client := &http.Client{}
-	if err = r.infraRepo.ClearInfraProject(client); err != nil {
+	if err = r.infraRepo.ClearInfraProject(ctx); err != nil {
 		fmt.Println(err)
 		return err
 	}
@@ -0,0 +1,96 @@
+package provisioning
+
+import (
+	"antrein/bc-dashboard/model/config"
+	"antrein/bc-dashboard/model/entity"
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type Repository struct {
+	cfg *config.Config
+	db  *sqlx.DB
+}
+
+func New(cfg *config.Config, db *sqlx.DB) *Repository {
+	return &Repository{
+		cfg: cfg,
+		db:  db,
+	}
+}
+
+func (r *Repository) GetProvisioning(ctx context.Context, projectID string) (*entity.ProjectProvisioning, error) {
+	provisioning := entity.ProjectProvisioning{}
+	q := `SELECT * FROM project_provisionings WHERE project_id = $1`
+	err := r.db.GetContext(ctx, &provisioning, q, projectID)
+	if err != nil {
+		return nil, err
+	}
+	return &provisioning, nil
+}
+
+func (r *Repository) GetProvisioningsByState(ctx context.Context, states []string) ([]entity.ProjectProvisioning, error) {
+	provisionings := []entity.ProjectProvisioning{}
+	q := `SELECT * FROM project_provisionings WHERE state::text = ANY($1) ORDER BY state_changed_at`
+	err := r.db.SelectContext(ctx, &provisionings, q, pq.Array(states))
+	return provisionings, err
+}
+
+// Transition moves the project from req.FromState to req.ToState and records
+// the transition. Projects without a row yet are pending. sql.ErrNoRows is
+// returned when the project is no longer in req.FromState.
+func (r *Repository) Transition(ctx context.Context, req entity.ProvisioningTransition, lastError sql.NullString) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	q1 := `INSERT INTO project_provisionings (project_id, state, state_changed_at, created_at)
+		   SELECT $1, 'pending', $2, $2 WHERE $3::text = 'pending'
+		   ON CONFLICT (project_id) DO NOTHING`
+	_, err = tx.ExecContext(ctx, q1, req.ProjectID, req.CreatedAt, req.FromState)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	q2 := `UPDATE project_provisionings
+		   SET state = $1,
+		   last_error = $2,
+		   state_changed_at = $3,
+		   updated_at = now()
+		   WHERE project_id = $4 AND state = $5`
+	resp, err := tx.ExecContext(ctx, q2, req.ToState, lastError, req.CreatedAt, req.ProjectID, req.FromState)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	affected, err := resp.RowsAffected()
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if affected == 0 {
+		tx.Rollback()
+		return sql.ErrNoRows
+	}
+
+	q3 := `INSERT INTO provisioning_transitions (project_id, from_state, to_state, error, created_at) VALUES ($1, $2, $3, $4, $5)`
+	_, err = tx.ExecContext(ctx, q3, req.ProjectID, req.FromState, req.ToState, req.Error, req.CreatedAt)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *Repository) GetTransitions(ctx context.Context, projectID string, limit int) ([]entity.ProvisioningTransition, error) {
+	transitions := []entity.ProvisioningTransition{}
+	q := `SELECT * FROM provisioning_transitions WHERE project_id = $1 ORDER BY created_at DESC LIMIT $2`
+	err := r.db.SelectContext(ctx, &transitions, q, projectID, limit)
+	return transitions, err
+}
@@ -0,0 +1,68 @@
+package storage
+
+import (
+	"antrein/bc-dashboard/internal/repository/infra"
+	"antrein/bc-dashboard/model/config"
+	"antrein/bc-dashboard/model/dto"
+	"context"
+	"net/http"
+	"time"
+)
+
+// infraStorage uploads through the infra manager. The manager does not report
+// page URLs, those are built from PublicURL. It cannot read files back, pages
+// are read from PublicURL as well, and it has no way to delete them.
+type infraStorage struct {
+	cfg       config.StorageConfig
+	infraRepo infra.InfraManager
+	client    *http.Client
+}
+
+func newInfraStorage(cfg config.StorageConfig, infraRepo infra.InfraManager) *infraStorage {
+	return &infraStorage{
+		cfg:       cfg,
+		infraRepo: infraRepo,
+		client:    &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func (s *infraStorage) UploadHTML(ctx context.Context, name string, content []byte) (string, error) {
+	url, err := s.infraRepo.UploadHTMLFile(ctx, dto.File{
+		Filename: name,
+		Content:  content,
+	})
+	if err != nil {
+		return "", err
+	}
+	if url == "" {
+		url = s.HTMLURL(name)
+	}
+	return url, nil
+}
+
+func (s *infraStorage) ReadHTML(ctx context.Context, name string) ([]byte, error) {
+	return fetchHTML(ctx, s.client, s.HTMLURL(name))
+}
+
+func (s *infraStorage) UploadAsset(ctx context.Context, name, contentType string, content []byte) (string, error) {
+	return s.infraRepo.UploadLogoFile(ctx, dto.File{
+		Filename: name,
+		Content:  content,
+	})
+}
+
+func (s *infraStorage) DeleteHTML(ctx context.Context, name string) error {
+	return ErrDeleteUnsupported
+}
+
+func (s *infraStorage) DeleteAsset(ctx context.Context, name string) error {
+	return ErrDeleteUnsupported
+}
+
+func (s *infraStorage) HTMLURL(name string) string {
+	return joinURL(s.cfg.PublicURL, htmlKey(name))
+}
+
+func (s *infraStorage) DefaultLogoURL() string {
+	return s.cfg.DefaultLogoURL
+}
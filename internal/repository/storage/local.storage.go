@@ -0,0 +1,78 @@
+package storage
+
+import (
+	"antrein/bc-dashboard/model/config"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+)
+
+// localStorage writes into a directory served by this service under
+// LocalRoute. It is meant for development.
+type localStorage struct {
+	cfg config.StorageConfig
+}
+
+func newLocalStorage(cfg config.StorageConfig) (*localStorage, error) {
+	if cfg.LocalDir == "" {
+		return nil, errors.New("local storage needs local_dir")
+	}
+	return &localStorage{
+		cfg: cfg,
+	}, nil
+}
+
+func (s *localStorage) UploadHTML(ctx context.Context, name string, content []byte) (string, error) {
+	return s.write(htmlKey(name), content)
+}
+
+func (s *localStorage) ReadHTML(ctx context.Context, name string) ([]byte, error) {
+	return os.ReadFile(filepath.Join(s.cfg.LocalDir, filepath.FromSlash(htmlKey(name))))
+}
+
+func (s *localStorage) UploadAsset(ctx context.Context, name, contentType string, content []byte) (string, error) {
+	return s.write(assetKey(name), content)
+}
+
+func (s *localStorage) DeleteHTML(ctx context.Context, name string) error {
+	return s.remove(htmlKey(name))
+}
+
+func (s *localStorage) DeleteAsset(ctx context.Context, name string) error {
+	return s.remove(assetKey(name))
+}
+
+func (s *localStorage) HTMLURL(name string) string {
+	return joinURL(s.cfg.PublicURL, htmlKey(name))
+}
+
+func (s *localStorage) DefaultLogoURL() string {
+	return s.cfg.DefaultLogoURL
+}
+
+// write replaces the file through a rename so a page is never served half
+// written.
+func (s *localStorage) write(key string, content []byte) (string, error) {
+	path := filepath.Join(s.cfg.LocalDir, filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, content, 0o644); err != nil {
+		return "", err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return "", err
+	}
+	return joinURL(s.cfg.PublicURL, key), nil
+}
+
+func (s *localStorage) remove(key string) error {
+	err := os.Remove(filepath.Join(s.cfg.LocalDir, filepath.FromSlash(key)))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,198 @@
+package storage
+
+import (
+	"antrein/bc-dashboard/model/config"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// s3Storage puts objects into any S3 compatible store, signed with AWS
+// signature version 4.
+type s3Storage struct {
+	cfg    config.StorageConfig
+	client *http.Client
+}
+
+func newS3Storage(cfg config.StorageConfig) (*s3Storage, error) {
+	if cfg.S3.Endpoint == "" || cfg.S3.Bucket == "" {
+		return nil, errors.New("s3 storage needs endpoint and bucket")
+	}
+	if cfg.S3.Region == "" {
+		cfg.S3.Region = "us-east-1"
+	}
+	return &s3Storage{
+		cfg:    cfg,
+		client: &http.Client{Timeout: 30 * time.Second},
+	}, nil
+}
+
+func (s *s3Storage) UploadHTML(ctx context.Context, name string, content []byte) (string, error) {
+	return s.put(ctx, htmlKey(name), "text/html; charset=utf-8", content)
+}
+
+func (s *s3Storage) ReadHTML(ctx context.Context, name string) ([]byte, error) {
+	key := htmlKey(name)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.objectURL(key), nil)
+	if err != nil {
+		return nil, err
+	}
+	s.sign(req, nil, time.Now().UTC())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get object %s, status code: %d", key, resp.StatusCode)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, maxHTMLSize))
+}
+
+func (s *s3Storage) UploadAsset(ctx context.Context, name, contentType string, content []byte) (string, error) {
+	return s.put(ctx, assetKey(name), contentType, content)
+}
+
+func (s *s3Storage) DeleteHTML(ctx context.Context, name string) error {
+	return s.delete(ctx, htmlKey(name))
+}
+
+func (s *s3Storage) DeleteAsset(ctx context.Context, name string) error {
+	return s.delete(ctx, assetKey(name))
+}
+
+func (s *s3Storage) HTMLURL(name string) string {
+	return s.publicURL(htmlKey(name))
+}
+
+func (s *s3Storage) DefaultLogoURL() string {
+	return s.cfg.DefaultLogoURL
+}
+
+func (s *s3Storage) objectURL(key string) string {
+	endpoint, err := url.Parse(s.cfg.S3.Endpoint)
+	if err != nil {
+		return joinURL(s.cfg.S3.Endpoint, s.cfg.S3.Bucket+"/"+key)
+	}
+	if s.cfg.S3.PathStyle {
+		endpoint.Path = "/" + s.cfg.S3.Bucket + "/" + key
+	} else {
+		endpoint.Host = s.cfg.S3.Bucket + "." + endpoint.Host
+		endpoint.Path = "/" + key
+	}
+	return endpoint.String()
+}
+
+// publicURL prefers PublicURL, usually a CDN in front of the bucket, over
+// the object URL of the store itself.
+func (s *s3Storage) publicURL(key string) string {
+	if s.cfg.PublicURL != "" {
+		return joinURL(s.cfg.PublicURL, key)
+	}
+	return s.objectURL(key)
+}
+
+func (s *s3Storage) put(ctx context.Context, key, contentType string, content []byte) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, s.objectURL(key), bytes.NewReader(content))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", contentType)
+	s.sign(req, content, time.Now().UTC())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return "", fmt.Errorf("failed to put object %s, status code: %d, %s", key, resp.StatusCode, body)
+	}
+	return s.publicURL(key), nil
+}
+
+func (s *s3Storage) delete(ctx context.Context, key string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, s.objectURL(key), nil)
+	if err != nil {
+		return err
+	}
+	s.sign(req, nil, time.Now().UTC())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to delete object %s, status code: %d", key, resp.StatusCode)
+	}
+	return nil
+}
+
+func (s *s3Storage) sign(req *http.Request, content []byte, now time.Time) {
+	payloadHash := sha256Hex(content)
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	signedHeaders := "host;x-amz-content-sha256;x-amz-date"
+	canonicalHeaders := "host:" + req.URL.Host + "\n" +
+		"x-amz-content-sha256:" + payloadHash + "\n" +
+		"x-amz-date:" + amzDate + "\n"
+	if contentType := req.Header.Get("Content-Type"); contentType != "" {
+		signedHeaders = "content-type;" + signedHeaders
+		canonicalHeaders = "content-type:" + contentType + "\n" + canonicalHeaders
+	}
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + s.cfg.S3.Region + "/s3/aws4_request"
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+s.cfg.S3.SecretKey), date)
+	key = hmacSHA256(key, s.cfg.S3.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.cfg.S3.AccessKey, scope, signedHeaders, signature))
+}
+
+func sha256Hex(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,93 @@
+package storage
+
+import (
+	"antrein/bc-dashboard/internal/repository/infra"
+	"antrein/bc-dashboard/model/config"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// maxHTMLSize bounds how much of a stored page ReadHTML reads.
+const maxHTMLSize = 5 << 20
+
+const (
+	BackendInfraManager = "infra_manager"
+	BackendLocal        = "local"
+	BackendS3           = "s3"
+)
+
+// ErrDeleteUnsupported is returned by backends that cannot delete files.
+var ErrDeleteUnsupported = errors.New("storage backend cannot delete files")
+
+// LocalRoute is where the REST server serves the local backend files.
+const LocalRoute = "/bc/dashboard/storage/"
+
+// Storage keeps the published queue pages and logos. Every backend returns
+// the public URL of what it stored, HTMLURL gives the URL a page has or will
+// have once it is uploaded. ReadHTML returns a stored page so it can be
+// copied.
+type Storage interface {
+	UploadHTML(ctx context.Context, name string, content []byte) (string, error)
+	ReadHTML(ctx context.Context, name string) ([]byte, error)
+	UploadAsset(ctx context.Context, name, contentType string, content []byte) (string, error)
+	DeleteHTML(ctx context.Context, name string) error
+	DeleteAsset(ctx context.Context, name string) error
+	HTMLURL(name string) string
+	DefaultLogoURL() string
+}
+
+// StageConfig returns the storage configuration of the running stage. Stages
+// without one keep using the infra manager.
+func StageConfig(cfg *config.Config) config.StorageConfig {
+	storageCfg, ok := cfg.Storage[cfg.Stage]
+	if !ok || storageCfg.Backend == "" {
+		storageCfg.Backend = BackendInfraManager
+	}
+	return storageCfg
+}
+
+func New(cfg *config.Config, infraRepo infra.InfraManager) (Storage, error) {
+	storageCfg := StageConfig(cfg)
+	switch storageCfg.Backend {
+	case BackendInfraManager:
+		return newInfraStorage(storageCfg, infraRepo), nil
+	case BackendLocal:
+		return newLocalStorage(storageCfg)
+	case BackendS3:
+		return newS3Storage(storageCfg)
+	}
+	return nil, fmt.Errorf("unknown storage backend %q for stage %q", storageCfg.Backend, cfg.Stage)
+}
+
+func htmlKey(name string) string {
+	return "html_templates/" + name + ".html"
+}
+
+func assetKey(name string) string {
+	return "assets/" + name
+}
+
+func joinURL(base, key string) string {
+	return strings.TrimSuffix(base, "/") + "/" + key
+}
+
+// fetchHTML downloads a page from its public URL.
+func fetchHTML(ctx context.Context, client *http.Client, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get page %s, status code: %d", url, resp.StatusCode)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, maxHTMLSize))
+}
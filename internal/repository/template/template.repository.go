@@ -0,0 +1,65 @@
+package template
+
+import (
+	"antrein/bc-dashboard/model/config"
+	"antrein/bc-dashboard/model/entity"
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type Repository struct {
+	cfg *config.Config
+	db  *sqlx.DB
+}
+
+func New(cfg *config.Config, db *sqlx.DB) *Repository {
+	return &Repository{
+		cfg: cfg,
+		db:  db,
+	}
+}
+
+func (r *Repository) CreateNewTemplate(ctx context.Context, req entity.ProjectTemplate) (*entity.ProjectTemplate, error) {
+	template := req
+	q := `INSERT INTO project_templates (tenant_id, name, threshold, session_time, max_users_in_queue, queue_page_style, queue_html_page, queue_page_base_color, queue_page_title, queue_page_logo, created_at)
+		  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) returning id`
+	var id string
+	err := r.db.GetContext(ctx, &id, q, req.TenantID, req.Name, req.Threshold, req.SessionTime, req.MaxUsersInQueue, req.QueuePageStyle, req.QueueHTMLPage, req.QueuePageBaseColor, req.QueuePageTitle, req.QueuePageLogo, req.CreatedAt)
+	template.ID = id
+	return &template, err
+}
+
+func (r *Repository) GetTenantTemplateByID(ctx context.Context, id, tenantID string) (*entity.ProjectTemplate, error) {
+	template := entity.ProjectTemplate{}
+	q := `SELECT * FROM project_templates WHERE id = $1 AND tenant_id = $2 LIMIT 1`
+	err := r.db.GetContext(ctx, &template, q, id, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return &template, err
+}
+
+func (r *Repository) GetTenantTemplates(ctx context.Context, tenantID string) ([]entity.ProjectTemplate, error) {
+	templates := []entity.ProjectTemplate{}
+	q := `SELECT * FROM project_templates WHERE tenant_id = $1 ORDER BY name`
+	err := r.db.SelectContext(ctx, &templates, q, tenantID)
+	return templates, err
+}
+
+func (r *Repository) DeleteTenantTemplate(ctx context.Context, id, tenantID string) error {
+	q := `DELETE FROM project_templates WHERE id = $1 AND tenant_id = $2`
+	resp, err := r.db.ExecContext(ctx, q, id, tenantID)
+	if err != nil {
+		return err
+	}
+	affected, err := resp.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
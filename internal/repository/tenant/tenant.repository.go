@@ -4,6 +4,7 @@ import (
 	"antrein/bc-dashboard/model/config"
 	"antrein/bc-dashboard/model/entity"
 	"context"
+	"database/sql"
 
 	"github.com/jmoiron/sqlx"
 )
@@ -56,3 +57,19 @@ func (r *Repository) GetTenants(ctx context.Context, page int, pageSize int) ([]
 	err := r.db.SelectContext(ctx, &tenants, q, pageSize, offset)
 	return tenants, err
 }
+
+func (r *Repository) UpdateHTMLPolicyMode(ctx context.Context, id string, mode string) error {
+	q := `UPDATE tenants SET html_policy_mode = $1, updated_at = now() WHERE id = $2`
+	resp, err := r.db.ExecContext(ctx, q, mode, id)
+	if err != nil {
+		return err
+	}
+	affected, err := resp.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
@@ -0,0 +1,165 @@
+package theme
+
+import (
+	"antrein/bc-dashboard/model/config"
+	"antrein/bc-dashboard/model/entity"
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type Repository struct {
+	cfg *config.Config
+	db  *sqlx.DB
+}
+
+func New(cfg *config.Config, db *sqlx.DB) *Repository {
+	return &Repository{
+		cfg: cfg,
+		db:  db,
+	}
+}
+
+func (r *Repository) CreateNewTheme(ctx context.Context, req entity.Theme) (*entity.Theme, error) {
+	theme := req
+	q := `INSERT INTO themes (tenant_id, project_id, name, queue_page_style, queue_html_page, queue_page_base_color, queue_page_title, queue_page_logo, created_at)
+		  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id`
+	err := r.db.GetContext(ctx, &theme.ID, q, req.TenantID, req.ProjectID, req.Name, req.QueuePageStyle, req.QueueHTMLPage, req.QueuePageBaseColor, req.QueuePageTitle, req.QueuePageLogo, req.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &theme, nil
+}
+
+func (r *Repository) UpdateThemePage(ctx context.Context, req entity.Theme) error {
+	q := `UPDATE themes SET queue_html_page = $1, queue_page_logo = $2, updated_at = now() WHERE id = $3`
+	_, err := r.db.ExecContext(ctx, q, req.QueueHTMLPage, req.QueuePageLogo, req.ID)
+	return err
+}
+
+func (r *Repository) GetTenantThemeByID(ctx context.Context, id, tenantID string) (*entity.Theme, error) {
+	theme := entity.Theme{}
+	q := `SELECT * FROM themes WHERE id = $1 AND tenant_id = $2 LIMIT 1`
+	err := r.db.GetContext(ctx, &theme, q, id, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return &theme, nil
+}
+
+// GetAvailableThemes returns the project themes followed by the tenant library.
+func (r *Repository) GetAvailableThemes(ctx context.Context, projectID, tenantID string) ([]entity.Theme, error) {
+	themes := []entity.Theme{}
+	q := `SELECT * FROM themes
+		  WHERE tenant_id = $2 AND (project_id = $1 OR project_id IS NULL)
+		  ORDER BY project_id IS NULL, name`
+	err := r.db.SelectContext(ctx, &themes, q, projectID, tenantID)
+	return themes, err
+}
+
+func (r *Repository) IsThemeActive(ctx context.Context, id string) (bool, error) {
+	var active bool
+	q := `SELECT EXISTS (SELECT 1 FROM configurations WHERE active_theme_id = $1)`
+	err := r.db.GetContext(ctx, &active, q, id)
+	return active, err
+}
+
+func (r *Repository) DeleteTheme(ctx context.Context, id string) error {
+	q := `DELETE FROM themes WHERE id = $1`
+	_, err := r.db.ExecContext(ctx, q, id)
+	return err
+}
+
+func (r *Repository) ActivateTheme(ctx context.Context, projectID, themeID string) error {
+	q := `UPDATE configurations SET active_theme_id = $1, updated_at = now() WHERE project_id = $2`
+	resp, err := r.db.ExecContext(ctx, q, themeID, projectID)
+	if err != nil {
+		return err
+	}
+	affected, err := resp.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *Repository) CreateNewSchedule(ctx context.Context, req entity.ThemeSchedule) (*entity.ThemeSchedule, error) {
+	schedule := req
+	q := `INSERT INTO theme_schedules (project_id, theme_id, switch_at, created_at) VALUES ($1, $2, $3, $4) RETURNING id`
+	err := r.db.GetContext(ctx, &schedule.ID, q, req.ProjectID, req.ThemeID, req.SwitchAt, req.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+func (r *Repository) GetProjectSchedules(ctx context.Context, projectID string) ([]entity.ThemeSchedule, error) {
+	schedules := []entity.ThemeSchedule{}
+	q := `SELECT * FROM theme_schedules WHERE project_id = $1 ORDER BY switch_at DESC`
+	err := r.db.SelectContext(ctx, &schedules, q, projectID)
+	return schedules, err
+}
+
+func (r *Repository) GetTenantScheduleByID(ctx context.Context, id, tenantID string) (*entity.ThemeSchedule, error) {
+	schedule := entity.ThemeSchedule{}
+	q := `SELECT theme_schedules.* FROM theme_schedules
+		  INNER JOIN projects ON projects.id = theme_schedules.project_id
+		  WHERE theme_schedules.id = $1 AND projects.tenant_id = $2 LIMIT 1`
+	err := r.db.GetContext(ctx, &schedule, q, id, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+func (r *Repository) DeleteSchedule(ctx context.Context, id string) error {
+	q := `DELETE FROM theme_schedules WHERE id = $1`
+	_, err := r.db.ExecContext(ctx, q, id)
+	return err
+}
+
+func (r *Repository) GetDueSchedules(ctx context.Context, now time.Time) ([]entity.ThemeSchedule, error) {
+	schedules := []entity.ThemeSchedule{}
+	q := `SELECT * FROM theme_schedules WHERE applied_at IS NULL AND switch_at <= $1 ORDER BY switch_at`
+	err := r.db.SelectContext(ctx, &schedules, q, now)
+	return schedules, err
+}
+
+// ApplySchedule activates the scheduled theme and marks the schedule as
+// applied, unless another run already applied it.
+func (r *Repository) ApplySchedule(ctx context.Context, schedule entity.ThemeSchedule, now time.Time) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	q := `UPDATE theme_schedules SET applied_at = $1 WHERE id = $2 AND applied_at IS NULL`
+	resp, err := tx.ExecContext(ctx, q, now, schedule.ID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	affected, err := resp.RowsAffected()
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if affected == 0 {
+		tx.Rollback()
+		return sql.ErrNoRows
+	}
+
+	q = `UPDATE configurations SET active_theme_id = $1, updated_at = now() WHERE project_id = $2`
+	_, err = tx.ExecContext(ctx, q, schedule.ThemeID, schedule.ProjectID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
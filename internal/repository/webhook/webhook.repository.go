@@ -0,0 +1,169 @@
+package webhook
+
+import (
+	"antrein/bc-dashboard/model/config"
+	"antrein/bc-dashboard/model/entity"
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type Repository struct {
+	cfg *config.Config
+	db  *sqlx.DB
+}
+
+func New(cfg *config.Config, db *sqlx.DB) *Repository {
+	return &Repository{
+		cfg: cfg,
+		db:  db,
+	}
+}
+
+func (r *Repository) CreateNewWebhook(ctx context.Context, req entity.Webhook) (*entity.Webhook, error) {
+	webhook := req
+	q := `INSERT INTO webhooks (tenant_id, url, secret, events, description, is_active, created_at)
+		  VALUES ($1, $2, $3, $4, $5, $6, $7) returning id`
+	var id string
+	err := r.db.GetContext(ctx, &id, q, req.TenantID, req.URL, req.Secret, pq.Array(req.Events), req.Description, req.IsActive, req.CreatedAt)
+	webhook.ID = id
+	return &webhook, err
+}
+
+func (r *Repository) GetTenantWebhookByID(ctx context.Context, id, tenantID string) (*entity.Webhook, error) {
+	webhook := entity.Webhook{}
+	q := `SELECT * FROM webhooks WHERE id = $1 AND tenant_id = $2 LIMIT 1`
+	err := r.db.GetContext(ctx, &webhook, q, id, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return &webhook, err
+}
+
+func (r *Repository) GetTenantWebhooks(ctx context.Context, tenantID string) ([]entity.Webhook, error) {
+	webhooks := []entity.Webhook{}
+	q := `SELECT * FROM webhooks WHERE tenant_id = $1 ORDER BY created_at`
+	err := r.db.SelectContext(ctx, &webhooks, q, tenantID)
+	return webhooks, err
+}
+
+// GetSubscribedWebhooks returns the active webhooks of the tenant that
+// subscribed to eventType.
+func (r *Repository) GetSubscribedWebhooks(ctx context.Context, tenantID, eventType string) ([]entity.Webhook, error) {
+	webhooks := []entity.Webhook{}
+	q := `SELECT * FROM webhooks WHERE tenant_id = $1 AND is_active = TRUE AND $2 = ANY(events)`
+	err := r.db.SelectContext(ctx, &webhooks, q, tenantID, eventType)
+	return webhooks, err
+}
+
+func (r *Repository) UpdateWebhook(ctx context.Context, req entity.Webhook) error {
+	q := `UPDATE webhooks
+		  SET url = $1,
+		  events = $2,
+		  description = $3,
+		  is_active = $4,
+		  updated_at = now()
+		  WHERE id = $5`
+	_, err := r.db.ExecContext(ctx, q, req.URL, pq.Array(req.Events), req.Description, req.IsActive, req.ID)
+	return err
+}
+
+func (r *Repository) DeleteWebhook(ctx context.Context, id string) error {
+	q := `DELETE FROM webhooks WHERE id = $1`
+	_, err := r.db.ExecContext(ctx, q, id)
+	return err
+}
+
+func (r *Repository) GetProjectTenantID(ctx context.Context, projectID string) (string, error) {
+	var tenantID string
+	q := `SELECT tenant_id FROM projects WHERE id = $1`
+	err := r.db.GetContext(ctx, &tenantID, q, projectID)
+	return tenantID, err
+}
+
+// CreateDeliveries queues one delivery of the event for every webhook, due
+// right away.
+func (r *Repository) CreateDeliveries(ctx context.Context, webhookIDs []string, eventID, eventType string, payload []byte, now time.Time) error {
+	q := `INSERT INTO webhook_deliveries (webhook_id, event_id, event_type, payload, next_attempt_at, created_at)
+		  SELECT unnest($1::uuid[]), $2, $3, $4, $5, $5`
+	_, err := r.db.ExecContext(ctx, q, pq.Array(webhookIDs), eventID, eventType, payload, now)
+	return err
+}
+
+func (r *Repository) CreateDelivery(ctx context.Context, req entity.WebhookDelivery) (*entity.WebhookDelivery, error) {
+	delivery := entity.WebhookDelivery{}
+	q := `INSERT INTO webhook_deliveries (webhook_id, event_id, event_type, payload, status, next_attempt_at, created_at)
+		  VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING *`
+	err := r.db.GetContext(ctx, &delivery, q, req.WebhookID, req.EventID, req.EventType, req.Payload, req.Status, req.NextAttemptAt, req.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &delivery, err
+}
+
+func (r *Repository) GetTenantDeliveryByID(ctx context.Context, id, tenantID string) (*entity.WebhookDelivery, error) {
+	delivery := entity.WebhookDelivery{}
+	q := `SELECT webhook_deliveries.* FROM webhook_deliveries
+		  INNER JOIN webhooks ON webhooks.id = webhook_deliveries.webhook_id
+		  WHERE webhook_deliveries.id = $1 AND webhooks.tenant_id = $2 LIMIT 1`
+	err := r.db.GetContext(ctx, &delivery, q, id, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return &delivery, err
+}
+
+func (r *Repository) GetDeliveries(ctx context.Context, webhookID string, limit int) ([]entity.WebhookDelivery, error) {
+	deliveries := []entity.WebhookDelivery{}
+	q := `SELECT * FROM webhook_deliveries WHERE webhook_id = $1 ORDER BY created_at DESC LIMIT $2`
+	err := r.db.SelectContext(ctx, &deliveries, q, webhookID, limit)
+	return deliveries, err
+}
+
+// ClaimDueDeliveries takes up to limit pending deliveries of active webhooks
+// that are due at now and pushes their next attempt to leaseUntil, so no
+// other worker sends them meanwhile. A delivery whose attempt is never
+// recorded is picked up again once the lease runs out.
+func (r *Repository) ClaimDueDeliveries(ctx context.Context, now, leaseUntil time.Time, limit int) ([]entity.WebhookTarget, error) {
+	targets := []entity.WebhookTarget{}
+	q := `UPDATE webhook_deliveries
+		  SET next_attempt_at = $2, updated_at = now()
+		  FROM webhooks
+		  WHERE webhooks.id = webhook_deliveries.webhook_id AND webhook_deliveries.id IN (
+		    SELECT webhook_deliveries.id FROM webhook_deliveries
+		    INNER JOIN webhooks ON webhooks.id = webhook_deliveries.webhook_id
+		    WHERE webhook_deliveries.status = 'pending' AND webhook_deliveries.next_attempt_at <= $1 AND webhooks.is_active = TRUE
+		    ORDER BY webhook_deliveries.next_attempt_at
+		    LIMIT $3
+		    FOR UPDATE OF webhook_deliveries SKIP LOCKED)
+		  RETURNING webhook_deliveries.*, webhooks.url, webhooks.secret`
+	err := r.db.SelectContext(ctx, &targets, q, now, leaseUntil, limit)
+	return targets, err
+}
+
+func (r *Repository) RecordAttempt(ctx context.Context, req entity.WebhookDelivery) error {
+	q := `UPDATE webhook_deliveries
+		  SET status = $1,
+		  attempts = $2,
+		  next_attempt_at = $3,
+		  last_status_code = $4,
+		  last_error = $5,
+		  delivered_at = $6,
+		  updated_at = now()
+		  WHERE id = $7`
+	resp, err := r.db.ExecContext(ctx, q, req.Status, req.Attempts, req.NextAttemptAt, req.LastStatusCode, req.LastError, req.DeliveredAt, req.ID)
+	if err != nil {
+		return err
+	}
+	affected, err := resp.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
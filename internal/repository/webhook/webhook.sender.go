@@ -0,0 +1,125 @@
+package webhook
+
+import (
+	"antrein/bc-dashboard/internal/utils/validator"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+const (
+	HeaderDeliveryID   = "X-Antrein-Delivery"
+	HeaderEvent        = "X-Antrein-Event"
+	HeaderEventVersion = "X-Antrein-Event-Version"
+	HeaderTimestamp    = "X-Antrein-Timestamp"
+	HeaderSignature    = "X-Antrein-Signature"
+
+	sendTimeout = 10 * time.Second
+)
+
+// Sign is the signature of a webhook request: the hex HMAC-SHA256 of the unix
+// timestamp, a dot and the body, keyed with the webhook secret. Receivers
+// recompute it and compare it to the signature header after the "sha256="
+// prefix.
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+type Message struct {
+	DeliveryID string
+	EventType  string
+	Version    string
+	URL        string
+	Secret     string
+	Payload    []byte
+}
+
+// ErrNotPublicAddress is returned for webhooks whose host resolves to an
+// address that is not public.
+var ErrNotPublicAddress = errors.New("webhook host is not a public address")
+
+type Sender struct {
+	client    *http.Client
+	allowHTTP bool
+}
+
+// NewSender returns a sender that does not follow redirects, a redirect
+// counts as a failed delivery. Only public addresses are dialed, the check
+// runs on the resolved address so a name can not be rebound to an internal
+// one after the URL was validated. Plain http is only sent with allowHTTP,
+// to public addresses as well.
+func NewSender(allowHTTP bool) *Sender {
+	dialer := &net.Dialer{
+		Timeout: sendTimeout,
+		Control: func(network, address string, c syscall.RawConn) error {
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil || !validator.IsPublicIP(addrPort.Addr()) {
+				return ErrNotPublicAddress
+			}
+			return nil
+		},
+	}
+	return &Sender{
+		client: &http.Client{
+			Timeout: sendTimeout,
+			Transport: &http.Transport{
+				// A proxy would be dialed in place of the webhook host.
+				Proxy:               nil,
+				DialContext:         dialer.DialContext,
+				TLSHandshakeTimeout: sendTimeout,
+				MaxIdleConns:        100,
+				IdleConnTimeout:     90 * time.Second,
+			},
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		allowHTTP: allowHTTP,
+	}
+}
+
+// Send posts the signed payload and returns the status code of the response.
+// Any status outside 2xx is an error.
+func (s *Sender) Send(ctx context.Context, msg Message, now time.Time) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, msg.URL, bytes.NewReader(msg.Payload))
+	if err != nil {
+		return 0, err
+	}
+	if req.URL.Scheme != "https" && !(s.allowHTTP && req.URL.Scheme == "http") {
+		return 0, fmt.Errorf("webhook url scheme %q is not allowed", req.URL.Scheme)
+	}
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "antrein-webhook/"+msg.Version)
+	req.Header.Set(HeaderDeliveryID, msg.DeliveryID)
+	req.Header.Set(HeaderEvent, msg.EventType)
+	req.Header.Set(HeaderEventVersion, msg.Version)
+	req.Header.Set(HeaderTimestamp, timestamp)
+	req.Header.Set(HeaderSignature, "sha256="+Sign(msg.Secret, timestamp, msg.Payload))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
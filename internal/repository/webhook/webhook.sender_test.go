@@ -0,0 +1,59 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestSign(t *testing.T) {
+	tests := []struct {
+		name      string
+		secret    string
+		timestamp string
+		body      string
+		want      string
+	}{
+		{
+			name:      "payload",
+			secret:    "whsec_test",
+			timestamp: "1700000000",
+			body:      `{"type":"project.created"}`,
+			want:      "ce545aa666ef80f3dd4375b97c25cd86d6b1e231a29f492c531e2565b153b531",
+		},
+		{
+			name:      "empty body",
+			secret:    "whsec_test",
+			timestamp: "1700000000",
+			want:      "5967f3c560522fa40cf2876ebc3c3a08551dd6959aaade3b413460591895bdcc",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Sign(tt.secret, tt.timestamp, []byte(tt.body)); got != tt.want {
+				t.Errorf("Sign() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSenderRefusesLoopback(t *testing.T) {
+	var called bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer server.Close()
+
+	// Even with plain http allowed, the receiver has to be public.
+	_, err := NewSender(true).Send(context.Background(), Message{URL: server.URL, Payload: []byte("{}")}, time.Now())
+	if !errors.Is(err, ErrNotPublicAddress) {
+		t.Errorf("Send() error = %v, want %v", err, ErrNotPublicAddress)
+	}
+	if called {
+		t.Error("Send() reached a loopback receiver")
+	}
+}
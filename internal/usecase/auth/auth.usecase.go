@@ -148,3 +148,60 @@ func (u *Usecase) LoginTenantAccount(ctx context.Context, req dto.LoginRequest)
 		Token: token,
 	}, nil
 }
+
+func (u *Usecase) GetHTMLPolicy(ctx context.Context, tenantID string) (*dto.HTMLPolicyResponse, *dto.ErrorResponse) {
+	var errRes dto.ErrorResponse
+
+	tenant, err := u.repo.GetTenantByID(ctx, tenantID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			errRes = dto.ErrorResponse{
+				Status: 404,
+				Error:  "Tenant tidak ditemukan",
+			}
+			return nil, &errRes
+		}
+		log.Println("Error gagal mendapatkan tenant", err)
+		errRes = dto.ErrorResponse{
+			Status: 500,
+			Error:  "Gagal mendapatkan kebijakan HTML",
+		}
+		return nil, &errRes
+	}
+
+	return &dto.HTMLPolicyResponse{
+		Mode:            tenant.HTMLPolicyMode,
+		ScriptAllowlist: u.cfg.HTMLPolicy.ScriptAllowlist,
+		MaxPageSize:     u.cfg.HTMLPolicy.MaxPageSize,
+	}, nil
+}
+
+func (u *Usecase) UpdateHTMLPolicy(ctx context.Context, req dto.UpdateHTMLPolicyRequest, tenantID string) *dto.ErrorResponse {
+	var errRes dto.ErrorResponse
+
+	if req.Mode != dto.HTMLPolicyStrict && req.Mode != dto.HTMLPolicyPermissive {
+		errRes = dto.ErrorResponse{
+			Status: 400,
+			Error:  "Mode kebijakan HTML harus strict atau permissive",
+		}
+		return &errRes
+	}
+
+	err := u.repo.UpdateHTMLPolicyMode(ctx, tenantID, req.Mode)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			errRes = dto.ErrorResponse{
+				Status: 404,
+				Error:  "Tenant tidak ditemukan",
+			}
+			return &errRes
+		}
+		log.Println("Error gagal mengupdate kebijakan HTML", err)
+		errRes = dto.ErrorResponse{
+			Status: 500,
+			Error:  "Gagal mengupdate kebijakan HTML",
+		}
+		return &errRes
+	}
+	return nil
+}
@@ -0,0 +1,257 @@
+package autoscale
+
+import (
+	"antrein/bc-dashboard/internal/repository/analytic"
+	"antrein/bc-dashboard/internal/repository/autoscale"
+	"antrein/bc-dashboard/internal/repository/configuration"
+	"antrein/bc-dashboard/internal/repository/event"
+	"antrein/bc-dashboard/internal/repository/history"
+	"antrein/bc-dashboard/internal/repository/infra"
+	"antrein/bc-dashboard/internal/utils/parser"
+	"antrein/bc-dashboard/internal/utils/validator"
+	"antrein/bc-dashboard/model/config"
+	"antrein/bc-dashboard/model/dto"
+	"antrein/bc-dashboard/model/entity"
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+)
+
+const (
+	adjustmentLimit = 50
+	probeTimeout    = 5 * time.Second
+)
+
+type Usecase struct {
+	cfg          *config.Config
+	repo         *autoscale.Repository
+	configRepo   *configuration.Repository
+	infraRepo    infra.InfraManager
+	analyticRepo *analytic.Repository
+	historyRepo  *history.Repository
+	eventBus     *event.Bus
+}
+
+func New(cfg *config.Config, repo *autoscale.Repository, configRepo *configuration.Repository, infraRepo infra.InfraManager, analyticRepo *analytic.Repository, historyRepo *history.Repository, eventBus *event.Bus) *Usecase {
+	return &Usecase{
+		cfg:          cfg,
+		repo:         repo,
+		configRepo:   configRepo,
+		infraRepo:    infraRepo,
+		analyticRepo: analyticRepo,
+		historyRepo:  historyRepo,
+		eventBus:     eventBus,
+	}
+}
+
+func handleError(status int, message string) *dto.ErrorResponse {
+	return &dto.ErrorResponse{
+		Status: status,
+		Error:  message,
+	}
+}
+
+func clamp(value, min, max int) int {
+	if value < min {
+		return min
+	}
+	if value > max {
+		return max
+	}
+	return value
+}
+
+func (u *Usecase) GetAutoscalePolicy(ctx context.Context, projectID, tenantID string) (*dto.AutoscaleDetailResponse, *dto.ErrorResponse) {
+	config, err := u.configRepo.GetTenantConfigByProjectID(ctx, projectID, tenantID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, handleError(http.StatusNotFound, "Project dengan id tersebut tidak ditemukan")
+		}
+		log.Println("Error gagal mendapatkan konfigurasi project", err)
+		return nil, handleError(http.StatusInternalServerError, "Gagal mendapatkan kebijakan autoscale")
+	}
+
+	resp := dto.AutoscaleDetailResponse{
+		Policy: &dto.AutoscalePolicy{
+			ProjectID:           projectID,
+			ConfiguredThreshold: config.Threshold,
+			EffectiveThreshold:  parser.ParseNullInt(config.EffectiveThreshold, config.Threshold),
+		},
+		Adjustments: []dto.ThresholdAdjustment{},
+	}
+
+	policy, err := u.repo.GetPolicyByProjectID(ctx, projectID)
+	if err != nil && err != sql.ErrNoRows {
+		log.Println("Error gagal mendapatkan kebijakan autoscale", err)
+		return nil, handleError(http.StatusInternalServerError, "Gagal mendapatkan kebijakan autoscale")
+	}
+	if policy != nil {
+		resp.Policy.Enabled = policy.Enabled
+		resp.Policy.MinThreshold = policy.MinThreshold
+		resp.Policy.MaxThreshold = policy.MaxThreshold
+		resp.Policy.StepDown = policy.StepDown
+		resp.Policy.StepUp = policy.StepUp
+		resp.Policy.LastAdjustedAt = parser.ParseNullTime(policy.LastAdjustedAt)
+	}
+
+	adjustments, err := u.repo.GetAdjustments(ctx, projectID, adjustmentLimit)
+	if err != nil {
+		log.Println("Error gagal mendapatkan riwayat autoscale", err)
+		return nil, handleError(http.StatusInternalServerError, "Gagal mendapatkan kebijakan autoscale")
+	}
+	for _, adjustment := range adjustments {
+		resp.Adjustments = append(resp.Adjustments, dto.ThresholdAdjustment{
+			FromThreshold: adjustment.FromThreshold,
+			ToThreshold:   adjustment.ToThreshold,
+			Healthiness:   adjustment.Healthiness,
+			UsersInRoom:   int(adjustment.UsersInRoom.Int32),
+			UsersInQueue:  int(adjustment.UsersInQueue.Int32),
+			Reason:        adjustment.Reason.String,
+			CreatedAt:     adjustment.CreatedAt,
+		})
+	}
+	return &resp, nil
+}
+
+func (u *Usecase) UpdateAutoscalePolicy(ctx context.Context, req dto.UpdateAutoscalePolicy, tenantID string) *dto.ErrorResponse {
+	config, err := u.configRepo.GetTenantConfigByProjectID(ctx, req.ProjectID, tenantID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return handleError(http.StatusNotFound, "Project dengan id tersebut tidak ditemukan")
+		}
+		log.Println("Error gagal mendapatkan konfigurasi project", err)
+		return handleError(http.StatusInternalServerError, "Gagal mengupdate kebijakan autoscale")
+	}
+
+	err = validator.ValidateAutoscalePolicy(req)
+	if err != nil {
+		return handleError(http.StatusBadRequest, err.Error())
+	}
+	if req.Enabled {
+		err = validator.ValidateAutoscaleThreshold(config.Threshold, req.MinThreshold, req.MaxThreshold)
+		if err != nil {
+			return handleError(http.StatusBadRequest, err.Error())
+		}
+	}
+
+	// An enabled policy keeps the current effective threshold when it still
+	// fits the new bounds and starts from the configured threshold otherwise.
+	effective := sql.NullInt32{}
+	if req.Enabled {
+		current := parser.ParseNullInt(config.EffectiveThreshold, config.Threshold)
+		effective = sql.NullInt32{
+			Valid: true,
+			Int32: int32(clamp(current, req.MinThreshold, req.MaxThreshold)),
+		}
+	}
+
+	err = u.repo.UpsertPolicy(ctx, entity.AutoscalePolicy{
+		ProjectID:    req.ProjectID,
+		Enabled:      req.Enabled,
+		MinThreshold: req.MinThreshold,
+		MaxThreshold: req.MaxThreshold,
+		StepDown:     req.StepDown,
+		StepUp:       req.StepUp,
+	}, effective)
+	if err != nil {
+		log.Println("Error gagal mengupdate kebijakan autoscale", err)
+		return handleError(http.StatusInternalServerError, "Gagal mengupdate kebijakan autoscale")
+	}
+
+	err = u.historyRepo.RecordProjectHistory(ctx, req.ProjectID, tenantID, "update_autoscale_policy", req)
+	if err != nil {
+		log.Println("Error gagal mencatat history project", err)
+	}
+	return nil
+}
+
+// AdjustThresholds is run by the worker. The effective threshold steps down
+// while the origin is unhealthy. Once it is healthy again the threshold steps
+// back up to the configured one, and beyond it up to the maximum only while
+// the room is full or users are waiting.
+func (u *Usecase) AdjustThresholds(ctx context.Context) {
+	policies, err := u.repo.GetEnabledPolicies(ctx)
+	if err != nil {
+		log.Println("Error gagal mendapatkan kebijakan autoscale", err)
+		return
+	}
+
+	for _, policy := range policies {
+		u.adjustThreshold(ctx, policy)
+	}
+}
+
+func (u *Usecase) adjustThreshold(ctx context.Context, policy entity.AutoscalePolicyWithConfig) {
+	probeCtx, cancel := context.WithTimeout(ctx, probeTimeout)
+	healthiness, err := u.infraRepo.CheckHealthProject(probeCtx, policy.ProjectID)
+	cancel()
+	if err != nil {
+		// Without a health signal the threshold is left alone.
+		log.Println("Error gagal mengecek kesehatan project", policy.ProjectID, err)
+		return
+	}
+
+	probeCtx, cancel = context.WithTimeout(ctx, probeTimeout)
+	analyticData, err := u.analyticRepo.GetRealtimeData(probeCtx, policy.ProjectID)
+	cancel()
+	if err != nil {
+		log.Println("Error gagal mendapatkan data analitik", policy.ProjectID, err)
+		analyticData = nil
+	}
+
+	current := parser.ParseNullInt(policy.EffectiveThreshold, policy.Threshold)
+	target := clamp(current, policy.MinThreshold, policy.MaxThreshold)
+	reason := "out_of_bounds"
+	// Recovery also stops at the maximum, policies saved before the bounds
+	// were checked against it may sit below the configured threshold.
+	recovered := min(policy.Threshold, policy.MaxThreshold)
+	switch {
+	case !healthiness:
+		target = clamp(current-policy.StepDown, policy.MinThreshold, policy.MaxThreshold)
+		reason = "origin_unhealthy"
+	case current < recovered:
+		target = clamp(current+policy.StepUp, policy.MinThreshold, recovered)
+		reason = "origin_recovered"
+	case analyticData != nil && (analyticData.TotalUsersInQueue > 0 || analyticData.TotalUsersInRoom >= current):
+		target = clamp(current+policy.StepUp, policy.MinThreshold, policy.MaxThreshold)
+		reason = "demand"
+	}
+	if target == current {
+		return
+	}
+
+	adjustment := entity.ThresholdAdjustment{
+		ProjectID:     policy.ProjectID,
+		FromThreshold: current,
+		ToThreshold:   target,
+		Healthiness:   healthiness,
+		Reason:        sql.NullString{Valid: true, String: reason},
+		CreatedAt:     time.Now(),
+	}
+	if analyticData != nil {
+		adjustment.UsersInRoom = sql.NullInt32{Valid: true, Int32: int32(analyticData.TotalUsersInRoom)}
+		adjustment.UsersInQueue = sql.NullInt32{Valid: true, Int32: int32(analyticData.TotalUsersInQueue)}
+	}
+
+	err = u.repo.ApplyAdjustment(ctx, adjustment)
+	if err != nil {
+		log.Println("Error gagal menyimpan penyesuaian threshold", policy.ProjectID, err)
+		return
+	}
+
+	// Only the step that takes the threshold below the configured one alerts,
+	// the steps after it would repeat the same alert every run.
+	if !healthiness && current >= policy.Threshold && target < policy.Threshold {
+		u.eventBus.Publish(ctx, dto.ProjectEvent{
+			Type:      dto.EventAlertFired,
+			ProjectID: policy.ProjectID,
+			Data: dto.Alert{
+				Alert:   dto.AlertOriginUnhealthy,
+				Message: fmt.Sprintf("Origin tidak sehat, threshold diturunkan dari %d ke %d", current, target),
+			},
+		})
+	}
+}
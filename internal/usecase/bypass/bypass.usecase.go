@@ -0,0 +1,269 @@
+package bypass
+
+import (
+	"antrein/bc-dashboard/internal/repository/bypass"
+	"antrein/bc-dashboard/internal/repository/project"
+	"antrein/bc-dashboard/internal/utils/generator"
+	"antrein/bc-dashboard/internal/utils/parser"
+	"antrein/bc-dashboard/model/config"
+	"antrein/bc-dashboard/model/dto"
+	"antrein/bc-dashboard/model/entity"
+	"context"
+	"database/sql"
+	"log"
+	"net/http"
+	"time"
+)
+
+const timeLayout = "2006-01-02T15:04:05"
+
+type Usecase struct {
+	cfg         *config.Config
+	repo        *bypass.Repository
+	projectRepo *project.Repository
+}
+
+func New(cfg *config.Config, repo *bypass.Repository, projectRepo *project.Repository) *Usecase {
+	return &Usecase{
+		cfg:         cfg,
+		repo:        repo,
+		projectRepo: projectRepo,
+	}
+}
+
+func handleError(status int, message string) *dto.ErrorResponse {
+	return &dto.ErrorResponse{
+		Status: status,
+		Error:  message,
+	}
+}
+
+func toRuleDTO(rule entity.BypassRule) dto.BypassRule {
+	return dto.BypassRule{
+		ID:          rule.ID,
+		ProjectID:   rule.ProjectID,
+		Type:        rule.Type,
+		Name:        rule.Name,
+		CIDR:        rule.CIDR.String,
+		HeaderName:  rule.HeaderName.String,
+		HeaderValue: rule.HeaderValue.String,
+		MaxUses:     rule.MaxUses,
+		UsedCount:   rule.UsedCount,
+		LastUsedAt:  parser.ParseNullTime(rule.LastUsedAt),
+		ExpiresAt:   parser.ParseNullTime(rule.ExpiresAt),
+		IsActive:    rule.IsActive,
+	}
+}
+
+func parseExpiry(value string) (sql.NullTime, *dto.ErrorResponse) {
+	if value == "" {
+		return sql.NullTime{}, nil
+	}
+	expiresAt, err := time.Parse(timeLayout, value)
+	if err != nil {
+		return sql.NullTime{}, handleError(http.StatusBadRequest, "Format waktu kadaluarsa salah")
+	}
+	return sql.NullTime{Valid: true, Time: expiresAt}, nil
+}
+
+func (u *Usecase) codeKey() string {
+	return u.cfg.Secrets.BypassSecret
+}
+
+func (u *Usecase) checkProject(ctx context.Context, projectID, tenantID string) *dto.ErrorResponse {
+	_, err := u.projectRepo.GetTenantProjectByID(ctx, projectID, tenantID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return handleError(http.StatusNotFound, "Project dengan id tersebut tidak ditemukan")
+		}
+		log.Println("Error gagal mendapatkan project", err)
+		return handleError(http.StatusInternalServerError, "Gagal mendapatkan project")
+	}
+	return nil
+}
+
+func (u *Usecase) CreateBypassRule(ctx context.Context, req dto.CreateBypassRuleRequest, tenantID string) (*dto.BypassRule, *dto.ErrorResponse) {
+	if errRes := u.checkProject(ctx, req.ProjectID, tenantID); errRes != nil {
+		return nil, errRes
+	}
+
+	expiresAt, errRes := parseExpiry(req.ExpiresAt)
+	if errRes != nil {
+		return nil, errRes
+	}
+
+	rule := entity.BypassRule{
+		ProjectID: req.ProjectID,
+		Type:      req.Type,
+		Name:      req.Name,
+		MaxUses:   req.MaxUses,
+		ExpiresAt: expiresAt,
+		IsActive:  true,
+		CreatedAt: time.Now(),
+	}
+
+	code := ""
+	switch req.Type {
+	case dto.BypassInviteCode:
+		var err error
+		code, err = generator.GenerateSignedCode(u.codeKey(), req.ProjectID)
+		if err != nil {
+			log.Println("Error gagal membuat kode undangan", err)
+			return nil, handleError(http.StatusInternalServerError, "Gagal membuat aturan bypass")
+		}
+		rule.CodeHash = sql.NullString{Valid: true, String: generator.HashString(code)}
+	case dto.BypassIP:
+		rule.CIDR = sql.NullString{Valid: true, String: req.CIDR}
+	case dto.BypassHeader:
+		rule.HeaderName = sql.NullString{Valid: true, String: req.HeaderName}
+		rule.HeaderValue = sql.NullString{Valid: true, String: req.HeaderValue}
+	}
+
+	created, err := u.repo.CreateNewRule(ctx, rule)
+	if err != nil {
+		log.Println("Error gagal membuat aturan bypass", err)
+		return nil, handleError(http.StatusInternalServerError, "Gagal membuat aturan bypass")
+	}
+
+	// The plain code is only shown once, only its hash is stored.
+	resp := toRuleDTO(*created)
+	resp.Code = code
+	return &resp, nil
+}
+
+func (u *Usecase) GetListBypassRule(ctx context.Context, projectID, tenantID string) (*dto.ListBypassRuleResponse, *dto.ErrorResponse) {
+	if errRes := u.checkProject(ctx, projectID, tenantID); errRes != nil {
+		return nil, errRes
+	}
+
+	rules, err := u.repo.GetProjectRules(ctx, projectID)
+	if err != nil {
+		return nil, handleError(http.StatusInternalServerError, err.Error())
+	}
+	listRules := make([]dto.BypassRule, len(rules))
+	for i, rule := range rules {
+		listRules[i] = toRuleDTO(rule)
+	}
+	return &dto.ListBypassRuleResponse{
+		ProjectID: projectID,
+		Rules:     listRules,
+	}, nil
+}
+
+func (u *Usecase) getTenantRule(ctx context.Context, ruleID, tenantID string) (*entity.BypassRule, *dto.ErrorResponse) {
+	rule, err := u.repo.GetTenantRuleByID(ctx, ruleID, tenantID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, handleError(http.StatusNotFound, "Aturan bypass tidak ditemukan")
+		}
+		log.Println("Error gagal mendapatkan aturan bypass", err)
+		return nil, handleError(http.StatusInternalServerError, "Gagal mendapatkan aturan bypass")
+	}
+	return rule, nil
+}
+
+func (u *Usecase) UpdateBypassRule(ctx context.Context, ruleID string, req dto.UpdateBypassRuleRequest, tenantID string) (*dto.BypassRule, *dto.ErrorResponse) {
+	rule, errRes := u.getTenantRule(ctx, ruleID, tenantID)
+	if errRes != nil {
+		return nil, errRes
+	}
+
+	if req.Name != nil {
+		rule.Name = *req.Name
+	}
+	if req.MaxUses != nil {
+		rule.MaxUses = *req.MaxUses
+	}
+	if req.ExpiresAt != nil {
+		rule.ExpiresAt, errRes = parseExpiry(*req.ExpiresAt)
+		if errRes != nil {
+			return nil, errRes
+		}
+	}
+	if req.IsActive != nil {
+		rule.IsActive = *req.IsActive
+	}
+
+	err := u.repo.UpdateRule(ctx, *rule)
+	if err != nil {
+		log.Println("Error gagal mengupdate aturan bypass", err)
+		return nil, handleError(http.StatusInternalServerError, "Gagal mengupdate aturan bypass")
+	}
+
+	resp := toRuleDTO(*rule)
+	return &resp, nil
+}
+
+func (u *Usecase) DeleteBypassRule(ctx context.Context, ruleID, tenantID string) *dto.ErrorResponse {
+	if _, errRes := u.getTenantRule(ctx, ruleID, tenantID); errRes != nil {
+		return errRes
+	}
+	err := u.repo.DeleteRule(ctx, ruleID)
+	if err != nil {
+		log.Println("Error gagal menghapus aturan bypass", err)
+		return handleError(http.StatusInternalServerError, "Gagal menghapus aturan bypass")
+	}
+	return nil
+}
+
+// GetActiveBypassRules returns the rules the queue service evaluates locally.
+// Invite codes are left out, they are checked through RedeemBypassCode.
+func (u *Usecase) GetActiveBypassRules(ctx context.Context, projectID string) (*dto.ListBypassRuleResponse, *dto.ErrorResponse) {
+	rules, err := u.repo.GetActiveProjectRules(ctx, projectID)
+	if err != nil {
+		log.Println("Error gagal mendapatkan aturan bypass", err)
+		return nil, handleError(http.StatusInternalServerError, "Gagal mendapatkan aturan bypass")
+	}
+	listRules := []dto.BypassRule{}
+	for _, rule := range rules {
+		if rule.Type == dto.BypassInviteCode {
+			continue
+		}
+		if rule.MaxUses > 0 && rule.UsedCount >= rule.MaxUses {
+			continue
+		}
+		listRules = append(listRules, toRuleDTO(rule))
+	}
+	return &dto.ListBypassRuleResponse{
+		ProjectID: projectID,
+		Rules:     listRules,
+	}, nil
+}
+
+func (u *Usecase) RedeemBypassCode(ctx context.Context, projectID, code string) (*dto.RedeemBypassCodeResponse, *dto.ErrorResponse) {
+	if !generator.VerifySignedCode(u.codeKey(), projectID, code) {
+		return &dto.RedeemBypassCodeResponse{
+			Allowed: false,
+			Reason:  "invalid_signature",
+		}, nil
+	}
+
+	rule, err := u.repo.RedeemCode(ctx, projectID, generator.HashString(code))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return &dto.RedeemBypassCodeResponse{
+				Allowed: false,
+				Reason:  "not_usable",
+			}, nil
+		}
+		log.Println("Error gagal menggunakan kode undangan", err)
+		return nil, handleError(http.StatusInternalServerError, "Gagal menggunakan kode undangan")
+	}
+
+	return &dto.RedeemBypassCodeResponse{
+		Allowed: true,
+		RuleID:  rule.ID,
+	}, nil
+}
+
+func (u *Usecase) RecordBypassUsage(ctx context.Context, ruleID string) *dto.ErrorResponse {
+	err := u.repo.RecordUsage(ctx, ruleID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return handleError(http.StatusNotFound, "Aturan bypass tidak ditemukan")
+		}
+		log.Println("Error gagal mencatat penggunaan bypass", err)
+		return handleError(http.StatusInternalServerError, "Gagal mencatat penggunaan bypass")
+	}
+	return nil
+}
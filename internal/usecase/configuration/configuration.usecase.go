@@ -1,51 +1,73 @@
 package configuration
 
 import (
+	"antrein/bc-dashboard/internal/repository/asset"
+	"antrein/bc-dashboard/internal/repository/autoscale"
 	"antrein/bc-dashboard/internal/repository/configuration"
+	"antrein/bc-dashboard/internal/repository/event"
+	"antrein/bc-dashboard/internal/repository/history"
 	"antrein/bc-dashboard/internal/repository/infra"
+	"antrein/bc-dashboard/internal/repository/storage"
+	"antrein/bc-dashboard/internal/repository/theme"
+	"antrein/bc-dashboard/internal/usecase/provisioning"
+	"antrein/bc-dashboard/internal/utils/checker"
+	"antrein/bc-dashboard/internal/utils/generator"
+	"antrein/bc-dashboard/internal/utils/parser"
+	"antrein/bc-dashboard/internal/utils/renderer"
+	"antrein/bc-dashboard/internal/utils/validator"
 	"antrein/bc-dashboard/model/config"
 	"antrein/bc-dashboard/model/dto"
 	"antrein/bc-dashboard/model/entity"
 	"context"
+	"crypto/sha256"
 	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"mime/multipart"
 	"net/http"
-	"os"
+	"net/url"
 	"strings"
 	"time"
+
+	"github.com/lib/pq"
 )
 
+// defaultRetentionHours is how long unreferenced page versions and logos are
+// kept when the stage does not configure it.
+const defaultRetentionHours = 7 * 24
+
+// closedWindowGrace is how long after it closed a queue window is still
+// announced, older windows were missed while no worker was running.
+const closedWindowGrace = time.Hour
+
 type Usecase struct {
-	cfg       *config.Config
-	repo      *configuration.Repository
-	infraRepo *infra.Repository
+	cfg                 *config.Config
+	repo                *configuration.Repository
+	storage             storage.Storage
+	historyRepo         *history.Repository
+	themeRepo           *theme.Repository
+	assetRepo           *asset.Repository
+	autoscaleRepo       *autoscale.Repository
+	provisioningUsecase *provisioning.Usecase
+	eventBus            *event.Bus
 }
 
-func New(cfg *config.Config, repo *configuration.Repository, infraRepo *infra.Repository) *Usecase {
+func New(cfg *config.Config, repo *configuration.Repository, storage storage.Storage, historyRepo *history.Repository, themeRepo *theme.Repository, assetRepo *asset.Repository, autoscaleRepo *autoscale.Repository, provisioningUsecase *provisioning.Usecase, eventBus *event.Bus) *Usecase {
 	return &Usecase{
-		cfg:       cfg,
-		repo:      repo,
-		infraRepo: infraRepo,
-	}
-}
-
-func loadDefaultHTML() (string, error) {
-	filePath := "./files/templates/queue.html"
-	htmlFile, err := os.Open(filePath)
-	if err != nil {
-		return "", err
+		cfg:                 cfg,
+		repo:                repo,
+		storage:             storage,
+		historyRepo:         historyRepo,
+		themeRepo:           themeRepo,
+		assetRepo:           assetRepo,
+		autoscaleRepo:       autoscaleRepo,
+		provisioningUsecase: provisioningUsecase,
+		eventBus:            eventBus,
 	}
-	defer htmlFile.Close()
-
-	content, err := io.ReadAll(htmlFile)
-	if err != nil {
-		return "", err
-	}
-
-	return string(content), nil
 }
 
 func readFileContent(file *multipart.FileHeader) ([]byte, error) {
@@ -57,10 +79,6 @@ func readFileContent(file *multipart.FileHeader) ([]byte, error) {
 	return io.ReadAll(f)
 }
 
-func changeValueInHTML(html, name, value string) string {
-	return strings.Replace(html, name, value, 1)
-}
-
 func handleError(status int, message string) *dto.ErrorResponse {
 	return &dto.ErrorResponse{
 		Status: status,
@@ -87,9 +105,11 @@ func (u *Usecase) GetProjectConfigByID(ctx context.Context, projectID string) (*
 		}
 		return nil, &errRes
 	}
+	locales, localizedPages := u.LocalizedPages(ctx, config.ProjectID, config.QueuePageStyle)
 	return &dto.ProjectConfig{
 		ProjectID:          config.ProjectID,
 		Threshold:          config.Threshold,
+		EffectiveThreshold: parser.ParseNullInt(config.EffectiveThreshold, config.Threshold),
 		SessionTime:        config.SessionTime,
 		Host:               config.Host.String,
 		BaseURL:            config.BaseURL.String,
@@ -101,6 +121,17 @@ func (u *Usecase) GetProjectConfigByID(ctx context.Context, projectID string) (*
 		QueuePageBaseColor: config.QueuePageBaseColor.String,
 		QueuePageTitle:     config.QueuePageTitle.String,
 		QueuePageLogo:      config.QueuePageLogo.String,
+		OperationalState:   config.OperationalState,
+		StateReason:        config.StateReason.String,
+		StateChangedAt:     parser.ParseNullTime(config.StateChangedAt),
+		AutoResumeAt:       parser.ParseNullTime(config.AutoResumeAt),
+		PriorityTiers:      u.loadPriorityTiers(ctx, config.ProjectID),
+		QueueMode:          config.QueueMode,
+		PreQueueOpenAt:     parser.ParseNullTime(config.PreQueueOpenAt),
+		LotterySeed:        config.LotterySeed.String,
+		ActiveThemeID:      config.ActiveThemeID.String,
+		Locales:            locales,
+		LocalizedPages:     localizedPages,
 	}, nil
 }
 
@@ -123,9 +154,11 @@ func (u *Usecase) GetProjectConfigByHost(ctx context.Context, host string) (*dto
 		}
 		return nil, &errRes
 	}
+	locales, localizedPages := u.LocalizedPages(ctx, config.ProjectID, config.QueuePageStyle)
 	return &dto.ProjectConfig{
 		ProjectID:          config.ProjectID,
 		Threshold:          config.Threshold,
+		EffectiveThreshold: parser.ParseNullInt(config.EffectiveThreshold, config.Threshold),
 		SessionTime:        config.SessionTime,
 		Host:               config.Host.String,
 		BaseURL:            config.BaseURL.String,
@@ -137,6 +170,17 @@ func (u *Usecase) GetProjectConfigByHost(ctx context.Context, host string) (*dto
 		QueuePageBaseColor: config.QueuePageBaseColor.String,
 		QueuePageTitle:     config.QueuePageTitle.String,
 		QueuePageLogo:      config.QueuePageLogo.String,
+		OperationalState:   config.OperationalState,
+		StateReason:        config.StateReason.String,
+		StateChangedAt:     parser.ParseNullTime(config.StateChangedAt),
+		AutoResumeAt:       parser.ParseNullTime(config.AutoResumeAt),
+		PriorityTiers:      u.loadPriorityTiers(ctx, config.ProjectID),
+		QueueMode:          config.QueueMode,
+		PreQueueOpenAt:     parser.ParseNullTime(config.PreQueueOpenAt),
+		LotterySeed:        config.LotterySeed.String,
+		ActiveThemeID:      config.ActiveThemeID.String,
+		Locales:            locales,
+		LocalizedPages:     localizedPages,
 	}, nil
 }
 
@@ -162,6 +206,97 @@ func (u *Usecase) UpdateProjectConfig(ctx context.Context, req dto.UpdateProject
 		return &errRes
 	}
 
+	queueMode := req.QueueMode
+	if queueMode == "" {
+		queueMode = dto.QueueModeFIFO
+	}
+	preQueueOpen := sql.NullTime{}
+	switch queueMode {
+	case dto.QueueModeFIFO:
+	case dto.QueueModePreQueueLottery:
+		openAt, err := time.Parse(layout, req.PreQueueOpen)
+		if err != nil {
+			errRes = dto.ErrorResponse{
+				Status: 400,
+				Error:  "Format waktu pre-queue dibuka salah",
+			}
+			return &errRes
+		}
+		if !openAt.Before(queueStart) {
+			errRes = dto.ErrorResponse{
+				Status: 400,
+				Error:  "Waktu pre-queue dibuka harus sebelum waktu queue mulai",
+			}
+			return &errRes
+		}
+		preQueueOpen = sql.NullTime{Valid: true, Time: openAt}
+	default:
+		errRes = dto.ErrorResponse{
+			Status: 400,
+			Error:  "Mode antrian tidak valid",
+		}
+		return &errRes
+	}
+
+	current, err := u.repo.GetConfigByProjectID(ctx, req.ProjectID)
+	if err != nil {
+		log.Println("Error gagal mengupdate konfigurasi project", err)
+		if err == sql.ErrNoRows {
+			errRes = dto.ErrorResponse{
+				Status: 404,
+				Error:  "Project dengan id tersebut tidak ditemukan",
+			}
+			return &errRes
+		}
+		errRes = dto.ErrorResponse{
+			Status: 500,
+			Error:  "Gagal mengupdate konfigurasi project",
+		}
+		return &errRes
+	}
+
+	policy, err := u.autoscaleRepo.GetPolicyByProjectID(ctx, req.ProjectID)
+	if err != nil && err != sql.ErrNoRows {
+		log.Println("Error gagal mendapatkan kebijakan autoscale", err)
+		errRes = dto.ErrorResponse{
+			Status: 500,
+			Error:  "Gagal mengupdate konfigurasi project",
+		}
+		return &errRes
+	}
+	if err == nil && policy.Enabled {
+		err = validator.ValidateAutoscaleThreshold(req.Threshold, policy.MinThreshold, policy.MaxThreshold)
+		if err != nil {
+			errRes = dto.ErrorResponse{
+				Status: 400,
+				Error:  err.Error(),
+			}
+			return &errRes
+		}
+	}
+
+	// The lottery seed is kept as long as the draw it belongs to is unchanged,
+	// so the recorded seed always matches the positions that were handed out.
+	lotterySeed := sql.NullString{}
+	if queueMode == dto.QueueModePreQueueLottery {
+		lotterySeed = current.LotterySeed
+		sameDraw := current.QueueMode == queueMode &&
+			current.PreQueueOpenAt.Time.Equal(preQueueOpen.Time) &&
+			current.QueueStart.Time.Equal(queueStart)
+		if !sameDraw || !lotterySeed.Valid {
+			seed, err := generator.GenerateSeed()
+			if err != nil {
+				log.Println("Error gagal membuat seed lottery", err)
+				errRes = dto.ErrorResponse{
+					Status: 500,
+					Error:  "Gagal mengupdate konfigurasi project",
+				}
+				return &errRes
+			}
+			lotterySeed = sql.NullString{Valid: true, String: seed}
+		}
+	}
+
 	config := entity.Configuration{
 		ProjectID:   req.ProjectID,
 		Threshold:   req.Threshold,
@@ -183,9 +318,40 @@ func (u *Usecase) UpdateProjectConfig(ctx context.Context, req dto.UpdateProject
 			Valid: true,
 			Time:  queueEnd,
 		},
+		QueueMode:      queueMode,
+		PreQueueOpenAt: preQueueOpen,
+		LotterySeed:    lotterySeed,
+	}
+
+	tiers, err := u.repo.GetPriorityTiers(ctx, req.ProjectID)
+	if err != nil {
+		log.Println("Error gagal mendapatkan tier prioritas", err)
+		errRes = dto.ErrorResponse{
+			Status: 500,
+			Error:  "Gagal mengupdate konfigurasi project",
+		}
+		return &errRes
+	}
+	err = validator.ValidatePriorityTiers(toTierDTOs(tiers), req.Threshold, req.MaxUsersInQueue)
+	if err != nil {
+		errRes = dto.ErrorResponse{
+			Status: 400,
+			Error:  err.Error(),
+		}
+		return &errRes
+	}
+
+	infraBody, errInfra := u.infraBody(ctx, config)
+	if errInfra != nil {
+		return errInfra
 	}
 
-	err = u.repo.UpdateProjectConfig(ctx, config)
+	errProvision := u.provisioningUsecase.StartProvisioning(ctx, req.ProjectID)
+	if errProvision != nil {
+		return errProvision
+	}
+	err = u.repo.UpdateProjectConfig(ctx, config, *infraBody)
+	u.provisioningUsecase.FinishProvisioning(ctx, req.ProjectID, err)
 	if err != nil {
 		log.Println("Error gagal mengupdate konfigurasi project", err)
 		if err == sql.ErrNoRows {
@@ -202,63 +368,86 @@ func (u *Usecase) UpdateProjectConfig(ctx context.Context, req dto.UpdateProject
 		return &errRes
 	}
 
+	u.eventBus.Publish(ctx, dto.ProjectEvent{
+		Type:      dto.EventConfigPublished,
+		ProjectID: req.ProjectID,
+		Data:      req,
+	})
 	return nil
 }
 
-func (u *Usecase) UpdateProjectStyle(ctx context.Context, req dto.UpdateProjectStyle, imageFile *multipart.FileHeader, htmlFile *multipart.FileHeader) *dto.ErrorResponse {
-	logoURL := "https://lh3.googleusercontent.com/proxy/ADW02XxlWJtFJ9MfhL0gRPFhUb9pDx08u6hlXUceO35UBGZncB9B9KdKoeiZW0K6rK1cJfYlRULTZaB-8zOJBFkEuhe8jC_9xivMaDIqA9TpJHQTV_5zmCsNkFzvH0uxICaV-v_F367S8xK5fe2bXINYVkz2CpNToA"
-	if req.QueuePageStyle == "base" {
-		if imageFile != nil {
-			imageContent, err := readFileContent(imageFile)
-			if err != nil {
-				return handleError(http.StatusBadRequest, "Gagal membaca file image")
-			}
-			logoURL, err = u.infraRepo.UploadLogoFile(&http.Client{}, dto.File{
-				Filename: imageFile.Filename,
-				Content:  imageContent,
-			})
-			if err != nil {
-				return handleError(http.StatusInternalServerError, "Gagal upload file image")
-			}
-		}
+// infraBody builds the infra manager request for the mode the project runs
+// in. Shared projects register a route on the common deployment, so the route
+// has to be free there. Dedicated projects carry the resources of the size
+// chosen when they were created.
+func (u *Usecase) infraBody(ctx context.Context, config entity.Configuration) (*infra.InfraBody, *dto.ErrorResponse) {
+	var errRes dto.ErrorResponse
 
-		htmlTemplate, err := loadDefaultHTML()
-		if err != nil {
-			log.Println(err)
-			return handleError(http.StatusInternalServerError, "Gagal membuka file template")
+	project, err := u.repo.GetProjectByID(ctx, config.ProjectID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			errRes = dto.ErrorResponse{
+				Status: 404,
+				Error:  "Project dengan id tersebut tidak ditemukan",
+			}
+			return nil, &errRes
 		}
-
-		if req.QueuePageBaseColor != "" {
-			htmlTemplate = changeValueInHTML(htmlTemplate, "var(--base-color, #f1f1f1)", req.QueuePageBaseColor)
+		log.Println("Error gagal mendapatkan project", err)
+		errRes = dto.ErrorResponse{
+			Status: 500,
+			Error:  "Gagal mengupdate konfigurasi project",
 		}
+		return nil, &errRes
+	}
 
-		htmlTemplate = changeValueInHTML(htmlTemplate, "{queue_logo}", logoURL)
-		htmlTemplate = changeValueInHTML(htmlTemplate, "{queue_title}", req.QueuePageTitle)
+	infraBody := infra.InfraBody{
+		ProjectID:     config.ProjectID,
+		ProjectDomain: config.Host.String,
+		URLPath:       config.BaseURL.String,
+		Mode:          infra.ProjectMode(u.cfg, project.InfraMode.String),
+	}
 
-		err = u.infraRepo.UploadHTMLFile(&http.Client{}, dto.File{
-			Filename: req.ProjectID,
-			Content:  []byte(htmlTemplate),
-		})
+	switch infraBody.Mode {
+	case infra.ModeMultiTenant:
+		taken, err := u.repo.IsSharedRouteTaken(ctx, config.ProjectID, config.Host.String, config.BaseURL.String, infra.DefaultMode(u.cfg))
 		if err != nil {
-			return handleError(http.StatusInternalServerError, "Gagal upload HTML file")
+			log.Println("Error gagal memeriksa route project", err)
+			errRes = dto.ErrorResponse{
+				Status: 500,
+				Error:  "Gagal mengupdate konfigurasi project",
+			}
+			return nil, &errRes
 		}
-	} else if req.QueuePageStyle == "custom" {
-		if htmlFile == nil {
-			return handleError(http.StatusBadRequest, "Mohon sertakan file HTML")
+		if taken {
+			errRes = dto.ErrorResponse{
+				Status: 400,
+				Error:  "Host dan base url sudah digunakan project lain",
+			}
+			return nil, &errRes
 		}
-		htmlContent, err := readFileContent(htmlFile)
-		if err != nil {
-			return handleError(http.StatusBadRequest, "Gagal membaca file HTML")
+	case infra.ModeSingleTenant:
+		size := project.ResourceSize.String
+		if size == "" {
+			size = infra.DefaultResourceSize
 		}
-		err = u.infraRepo.UploadHTMLFile(&http.Client{}, dto.File{
-			Filename: req.ProjectID,
-			Content:  htmlContent,
-		})
-		if err != nil {
-			return handleError(http.StatusInternalServerError, "Gagal upload HTML file")
+		resources, ok := infra.Resources(u.cfg, size)
+		if !ok {
+			errRes = dto.ErrorResponse{
+				Status: 400,
+				Error:  "Ukuran resource project tidak tersedia",
+			}
+			return nil, &errRes
 		}
-	} else {
-		return handleError(http.StatusBadRequest, "Tipe style tidak valid")
+		infraBody.Resources = resources
+	}
+
+	return &infraBody, nil
+}
+
+func (u *Usecase) UpdateProjectStyle(ctx context.Context, req dto.UpdateProjectStyle, imageFile *multipart.FileHeader, htmlFile *multipart.FileHeader) (*dto.UpdateProjectStyleResponse, *dto.ErrorResponse) {
+	page, errRes := u.publishStylePage(ctx, req, "", req.ProjectID, imageFile, htmlFile)
+	if errRes != nil {
+		return nil, errRes
 	}
 
 	config := entity.Configuration{
@@ -266,7 +455,7 @@ func (u *Usecase) UpdateProjectStyle(ctx context.Context, req dto.UpdateProjectS
 		QueuePageStyle: req.QueuePageStyle,
 		QueueHTMLPage: sql.NullString{
 			Valid:  true,
-			String: fmt.Sprintf("https://storage.googleapis.com/antrein-ta/html_templates/%s.html", req.ProjectID),
+			String: page.PageURL,
 		},
 		QueuePageBaseColor: sql.NullString{
 			Valid:  true,
@@ -278,7 +467,7 @@ func (u *Usecase) UpdateProjectStyle(ctx context.Context, req dto.UpdateProjectS
 		},
 		QueuePageLogo: sql.NullString{
 			Valid:  true,
-			String: logoURL,
+			String: page.LogoURL,
 		},
 	}
 
@@ -286,10 +475,1150 @@ func (u *Usecase) UpdateProjectStyle(ctx context.Context, req dto.UpdateProjectS
 	if err != nil {
 		log.Println("Error updating project style", err)
 		if err == sql.ErrNoRows {
-			return handleError(http.StatusNotFound, "Project tidak ditemukan")
+			return nil, handleError(http.StatusNotFound, "Project tidak ditemukan")
 		}
-		return handleError(http.StatusInternalServerError, "Gagal mengupdate project style")
+		return nil, handleError(http.StatusInternalServerError, "Gagal mengupdate project style")
+	}
+	u.refreshLocalizedPages(ctx, req.ProjectID)
+
+	return &dto.UpdateProjectStyleResponse{
+		ProjectID:      req.ProjectID,
+		QueuePageStyle: req.QueuePageStyle,
+		Violations:     page.Violations,
+	}, nil
+}
+
+// CopyProjectPage stores the custom page config points at again under the
+// project's own name and makes the project show the copy. Pages are versioned
+// by name, so a project showing another project's page would follow every
+// rollback of it.
+func (u *Usecase) CopyProjectPage(ctx context.Context, projectID string, config entity.Configuration) *dto.ErrorResponse {
+	version, err := u.assetRepo.GetPageVersionByURL(ctx, config.QueueHTMLPage.String)
+	if err == sql.ErrNoRows {
+		// Pages uploaded before versioning cannot be rolled back, sharing
+		// them is safe.
+		return nil
+	}
+	if err != nil {
+		log.Println("Error gagal mendapatkan versi halaman", err)
+		return handleError(http.StatusInternalServerError, "Gagal menyalin halaman antrian")
+	}
+	content, err := u.storage.ReadHTML(ctx, version.ObjectName)
+	if err != nil {
+		log.Println("Error gagal membaca file HTML", version.ObjectName, err)
+		return handleError(http.StatusInternalServerError, "Gagal menyalin halaman antrian")
+	}
+	pageURL, err := u.uploadPage(ctx, projectID, "", projectID, content, "")
+	if err != nil {
+		log.Println("Error gagal upload HTML file", err)
+		return handleError(http.StatusInternalServerError, "Gagal menyalin halaman antrian")
 	}
 
+	config.ProjectID = projectID
+	config.QueueHTMLPage = sql.NullString{Valid: true, String: pageURL}
+	err = u.repo.UpdateProjectStyle(ctx, config)
+	if err != nil {
+		log.Println("Error updating project style", err)
+		return handleError(http.StatusInternalServerError, "Gagal menyalin halaman antrian")
+	}
 	return nil
 }
+
+// publishedPage is a queue page stored by publishStylePage.
+type publishedPage struct {
+	PageURL    string
+	LogoURL    string
+	Violations []dto.HTMLViolation
+}
+
+// publishStylePage renders the base page or checks the custom page and
+// uploads it under filename.
+func (u *Usecase) publishStylePage(ctx context.Context, req dto.UpdateProjectStyle, tenantID, filename string, imageFile *multipart.FileHeader, htmlFile *multipart.FileHeader) (*publishedPage, *dto.ErrorResponse) {
+	page := &publishedPage{
+		LogoURL:    u.storage.DefaultLogoURL(),
+		Violations: []dto.HTMLViolation{},
+	}
+	if req.QueuePageLogo != "" {
+		page.LogoURL = req.QueuePageLogo
+	}
+
+	var htmlContent []byte
+	if req.QueuePageStyle == "base" {
+		if req.QueuePageBaseColor != "" && !validator.IsCSSColor(req.QueuePageBaseColor) {
+			return nil, handleError(http.StatusBadRequest, "Warna dasar tidak valid")
+		}
+		if imageFile != nil {
+			var errRes *dto.ErrorResponse
+			page.LogoURL, errRes = u.uploadLogo(ctx, imageFile)
+			if errRes != nil {
+				return nil, errRes
+			}
+		}
+
+		var err error
+		htmlContent, err = renderer.RenderQueuePage(renderer.QueuePageData{
+			Title:     req.QueuePageTitle,
+			LogoURL:   page.LogoURL,
+			BaseColor: req.QueuePageBaseColor,
+		})
+		if err != nil {
+			log.Println(err)
+			return nil, handleError(http.StatusInternalServerError, "Gagal membuat halaman antrian")
+		}
+	} else if req.QueuePageStyle == "custom" {
+		if htmlFile == nil {
+			return nil, handleError(http.StatusBadRequest, "Mohon sertakan file HTML")
+		}
+		var err error
+		htmlContent, err = readFileContent(htmlFile)
+		if err != nil {
+			return nil, handleError(http.StatusBadRequest, "Gagal membaca file HTML")
+		}
+		policy, errRes := u.htmlPolicy(ctx, req.ProjectID, tenantID)
+		if errRes != nil {
+			return nil, errRes
+		}
+		htmlContent, page.Violations = checker.CheckHTMLPolicy(htmlContent, *policy)
+		if checker.HasBlockingViolation(page.Violations) {
+			return nil, handleError(http.StatusUnprocessableEntity, formatViolations(page.Violations))
+		}
+	} else {
+		return nil, handleError(http.StatusBadRequest, "Tipe style tidak valid")
+	}
+
+	pageLogoURL := ""
+	if req.QueuePageStyle == "base" {
+		pageLogoURL = page.LogoURL
+	}
+	pageURL, err := u.uploadPage(ctx, req.ProjectID, tenantID, filename, htmlContent, pageLogoURL)
+	if err != nil {
+		log.Println("Error gagal upload HTML file", err)
+		return nil, handleError(http.StatusInternalServerError, "Gagal upload HTML file")
+	}
+	page.PageURL = pageURL
+	return page, nil
+}
+
+// uploadPage stores the page as a new version of name, under an object name
+// made from its content hash so caches never serve an older page. Uploading
+// content that already has a version makes that version current again.
+func (u *Usecase) uploadPage(ctx context.Context, projectID, tenantID, name string, content []byte, logoURL string) (string, error) {
+	sum := sha256.Sum256(content)
+	hash := hex.EncodeToString(sum[:])
+	now := time.Now()
+
+	existing, err := u.assetRepo.GetPageVersion(ctx, name, hash)
+	if err == nil {
+		return existing.URL, u.assetRepo.PublishPageVersion(ctx, existing.ID, now)
+	}
+	if err != sql.ErrNoRows {
+		return "", err
+	}
+
+	objectName := name + "-" + hash[:16]
+	pageURL, err := u.storage.UploadHTML(ctx, objectName, content)
+	if err != nil {
+		return "", err
+	}
+
+	err = u.assetRepo.CreateNewPageVersion(ctx, entity.PageVersion{
+		TenantID:    tenantID,
+		ProjectID:   sql.NullString{Valid: projectID != "", String: projectID},
+		Name:        name,
+		ObjectName:  objectName,
+		Hash:        hash,
+		URL:         pageURL,
+		LogoURL:     sql.NullString{Valid: logoURL != "", String: logoURL},
+		PublishedAt: now,
+	})
+	return pageURL, err
+}
+
+// processLogo reads the uploaded logo and checks it against the logo policy.
+// A rejected logo is reported with the constraint it failed.
+func (u *Usecase) processLogo(imageFile *multipart.FileHeader) (*checker.Logo, *dto.ErrorResponse) {
+	content, err := readFileContent(imageFile)
+	if err != nil {
+		return nil, handleError(http.StatusBadRequest, "Gagal membaca file image")
+	}
+	logo, err := checker.CheckLogo(content, checker.LogoPolicy{
+		MaxFileSize:  u.cfg.Logo.MaxFileSize,
+		MaxDimension: u.cfg.Logo.MaxDimension,
+	})
+	if err != nil {
+		var logoErr *checker.LogoError
+		if errors.As(err, &logoErr) {
+			return nil, handleError(http.StatusBadRequest, fmt.Sprintf("Logo ditolak (%s): %s", logoErr.Rule, logoErr.Detail))
+		}
+		log.Println(err)
+		return nil, handleError(http.StatusInternalServerError, "Gagal memproses file image")
+	}
+	return logo, nil
+}
+
+// uploadLogo publishes the processed logo under its content hash. A logo that
+// was uploaded before is not uploaded again, its stored URL is reused.
+func (u *Usecase) uploadLogo(ctx context.Context, imageFile *multipart.FileHeader) (string, *dto.ErrorResponse) {
+	logo, errRes := u.processLogo(imageFile)
+	if errRes != nil {
+		return "", errRes
+	}
+
+	existing, err := u.assetRepo.GetLogoByHash(ctx, logo.Hash)
+	if err == nil {
+		return existing.URL, nil
+	}
+	if err != sql.ErrNoRows {
+		log.Println(err)
+		return "", handleError(http.StatusInternalServerError, "Gagal upload file image")
+	}
+
+	logoURL, err := u.storage.UploadAsset(ctx, logo.Hash+logo.Extension, logo.ContentType, logo.Content)
+	if err != nil {
+		log.Println("Error gagal upload file image", err)
+		return "", handleError(http.StatusInternalServerError, "Gagal upload file image")
+	}
+
+	err = u.assetRepo.CreateNewLogo(ctx, entity.LogoAsset{
+		Hash:        logo.Hash,
+		Name:        logo.Hash + logo.Extension,
+		URL:         logoURL,
+		ContentType: logo.ContentType,
+		Size:        len(logo.Content),
+		CreatedAt:   time.Now(),
+	})
+	if err != nil {
+		log.Println(err)
+	}
+	return logoURL, nil
+}
+
+// htmlPolicy builds the custom page policy for the tenant owning the project,
+// or for tenantID when the page is not tied to a project. Strict tenants get
+// every violation reported, permissive tenants get the removable ones
+// stripped from the page.
+func (u *Usecase) htmlPolicy(ctx context.Context, projectID, tenantID string) (*checker.HTMLPolicy, *dto.ErrorResponse) {
+	policy := checker.HTMLPolicy{
+		ScriptAllowlist:  u.cfg.HTMLPolicy.ScriptAllowlist,
+		AllowedFormHosts: []string{},
+		MaxPageSize:      u.cfg.HTMLPolicy.MaxPageSize,
+	}
+
+	if projectID == "" {
+		mode, err := u.repo.GetHTMLPolicyModeByTenantID(ctx, tenantID)
+		if err != nil {
+			log.Println("Error gagal mendapatkan kebijakan HTML", err)
+			return nil, handleError(http.StatusInternalServerError, "Gagal mendapatkan kebijakan HTML")
+		}
+		policy.Strip = mode == dto.HTMLPolicyPermissive
+		return &policy, nil
+	}
+
+	mode, err := u.repo.GetHTMLPolicyModeByProjectID(ctx, projectID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, handleError(http.StatusNotFound, "Project tidak ditemukan")
+		}
+		log.Println("Error gagal mendapatkan kebijakan HTML", err)
+		return nil, handleError(http.StatusInternalServerError, "Gagal mendapatkan kebijakan HTML")
+	}
+	policy.Strip = mode == dto.HTMLPolicyPermissive
+
+	config, err := u.repo.GetConfigByProjectID(ctx, projectID)
+	if err != nil {
+		log.Println("Error gagal mendapatkan konfigurasi project", err)
+		return nil, handleError(http.StatusInternalServerError, "Gagal mendapatkan kebijakan HTML")
+	}
+	if config.Host.String != "" {
+		policy.Origin = "https://" + config.Host.String
+		policy.AllowedFormHosts = append(policy.AllowedFormHosts, config.Host.String)
+	}
+	if baseURL, err := url.Parse(config.BaseURL.String); err == nil && baseURL.Hostname() != "" {
+		policy.AllowedFormHosts = append(policy.AllowedFormHosts, baseURL.Hostname())
+	}
+	return &policy, nil
+}
+
+func formatViolations(violations []dto.HTMLViolation) string {
+	details := []string{}
+	for _, violation := range violations {
+		if !violation.Stripped {
+			details = append(details, violation.Detail)
+		}
+	}
+	return "Halaman HTML tidak sesuai kebijakan: " + strings.Join(details, "; ")
+}
+
+// PreviewProjectStyle renders the queue page the way UpdateProjectStyle would,
+// without uploading files or saving the style. Fields left empty fall back to
+// the current style of the project.
+func (u *Usecase) PreviewProjectStyle(ctx context.Context, req dto.UpdateProjectStyle, tenantID string, imageFile *multipart.FileHeader, htmlFile *multipart.FileHeader) (*dto.StylePreviewResponse, *dto.ErrorResponse) {
+	config, err := u.repo.GetTenantConfigByProjectID(ctx, req.ProjectID, tenantID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, handleError(http.StatusNotFound, "Project dengan id tersebut tidak ditemukan")
+		}
+		log.Println("Error gagal mendapatkan konfigurasi project", err)
+		return nil, handleError(http.StatusInternalServerError, "Gagal membuat preview tampilan")
+	}
+
+	var htmlPage []byte
+	violations := []dto.HTMLViolation{}
+	switch req.QueuePageStyle {
+	case "base":
+		data := renderer.QueuePageData{
+			Title:     req.QueuePageTitle,
+			LogoURL:   req.QueuePageLogo,
+			BaseColor: req.QueuePageBaseColor,
+		}
+		if data.Title == "" {
+			data.Title = config.QueuePageTitle.String
+		}
+		if data.BaseColor == "" {
+			data.BaseColor = config.QueuePageBaseColor.String
+		}
+		if data.LogoURL == "" {
+			data.LogoURL = config.QueuePageLogo.String
+		}
+		if data.LogoURL == "" {
+			data.LogoURL = u.storage.DefaultLogoURL()
+		}
+		if imageFile != nil {
+			logo, errRes := u.processLogo(imageFile)
+			if errRes != nil {
+				return nil, errRes
+			}
+			data.InlineLogo = logo.Content
+			data.InlineLogoType = logo.ContentType
+		}
+
+		htmlPage, err = renderer.RenderQueuePage(data)
+		if err != nil {
+			if errors.Is(err, renderer.ErrInvalidBaseColor) || errors.Is(err, renderer.ErrInvalidLogo) {
+				return nil, handleError(http.StatusBadRequest, err.Error())
+			}
+			log.Println(err)
+			return nil, handleError(http.StatusInternalServerError, "Gagal membuat halaman antrian")
+		}
+	case "custom":
+		if htmlFile == nil {
+			return nil, handleError(http.StatusBadRequest, "Mohon sertakan file HTML")
+		}
+		htmlPage, err = readFileContent(htmlFile)
+		if err != nil {
+			return nil, handleError(http.StatusBadRequest, "Gagal membaca file HTML")
+		}
+		policy, errRes := u.htmlPolicy(ctx, req.ProjectID, tenantID)
+		if errRes != nil {
+			return nil, errRes
+		}
+		htmlPage, violations = checker.CheckHTMLPolicy(htmlPage, *policy)
+	default:
+		return nil, handleError(http.StatusBadRequest, "Tipe style tidak valid")
+	}
+
+	return &dto.StylePreviewResponse{
+		ProjectID:      req.ProjectID,
+		QueuePageStyle: req.QueuePageStyle,
+		HTML:           string(htmlPage),
+		Violations:     violations,
+	}, nil
+}
+
+var operationalStates = []string{
+	dto.StateActive,
+	dto.StatePaused,
+	dto.StateDrainOnly,
+	dto.StateClosed,
+	dto.StateMaintenance,
+}
+
+func (u *Usecase) UpdateProjectState(ctx context.Context, req dto.UpdateProjectState, tenantID string) *dto.ErrorResponse {
+	if !checker.Contains(operationalStates, req.State) {
+		return handleError(http.StatusBadRequest, "Status operasional tidak valid")
+	}
+
+	autoResumeAt := sql.NullTime{}
+	if req.AutoResumeAt != "" {
+		if req.State == dto.StateActive {
+			return handleError(http.StatusBadRequest, "Waktu resume otomatis hanya untuk status selain active")
+		}
+		const layout = "2006-01-02T15:04:05"
+		resumeAt, err := time.Parse(layout, req.AutoResumeAt)
+		if err != nil {
+			return handleError(http.StatusBadRequest, "Format waktu resume otomatis salah")
+		}
+		if !resumeAt.After(time.Now()) {
+			return handleError(http.StatusBadRequest, "Waktu resume otomatis harus di masa depan")
+		}
+		autoResumeAt = sql.NullTime{Valid: true, Time: resumeAt}
+	}
+
+	current, err := u.repo.GetTenantConfigByProjectID(ctx, req.ProjectID, tenantID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return handleError(http.StatusNotFound, "Project dengan id tersebut tidak ditemukan")
+		}
+		log.Println("Error gagal mendapatkan konfigurasi project", err)
+		return handleError(http.StatusInternalServerError, "Gagal mengubah status project")
+	}
+
+	err = u.repo.UpdateProjectState(ctx, entity.Configuration{
+		ProjectID:        req.ProjectID,
+		OperationalState: req.State,
+		StateReason: sql.NullString{
+			Valid:  req.Reason != "",
+			String: req.Reason,
+		},
+		AutoResumeAt: autoResumeAt,
+	})
+	if err != nil {
+		log.Println("Error gagal mengubah status project", err)
+		return handleError(http.StatusInternalServerError, "Gagal mengubah status project")
+	}
+
+	u.recordStateChange(ctx, req.ProjectID, tenantID, current.OperationalState, req.State, req.Reason, autoResumeAt)
+	return nil
+}
+
+// ResumeDueProjects switches every project whose auto resume time has passed
+// back to active. It is run periodically by the worker.
+func (u *Usecase) ResumeDueProjects(ctx context.Context) {
+	now := time.Now()
+	configs, err := u.repo.GetDueAutoResumeConfigs(ctx, now)
+	if err != nil {
+		log.Println("Error gagal mendapatkan project untuk resume otomatis", err)
+		return
+	}
+	for _, config := range configs {
+		resumed, err := u.repo.AutoResumeProject(ctx, config.ProjectID, now)
+		if err != nil {
+			log.Println("Error gagal resume otomatis project", config.ProjectID, err)
+			continue
+		}
+		if !resumed {
+			continue
+		}
+		u.recordStateChange(ctx, config.ProjectID, "", config.OperationalState, dto.StateActive, "auto resume", sql.NullTime{})
+	}
+}
+
+// NotifyQueueWindows is run by the worker and raises queue.window_opened and
+// queue.window_closed once for every window of a configured project.
+func (u *Usecase) NotifyQueueWindows(ctx context.Context) {
+	now := time.Now()
+	opened, err := u.repo.GetOpenedQueueWindows(ctx, now)
+	if err != nil {
+		log.Println("Error gagal mendapatkan jendela antrian yang dibuka", err)
+	}
+	for _, window := range opened {
+		marked, err := u.repo.MarkQueueWindowOpened(ctx, window.ProjectID, window.QueueStart)
+		if err != nil {
+			log.Println("Error gagal menandai jendela antrian", window.ProjectID, err)
+			continue
+		}
+		if marked {
+			u.publishQueueWindow(ctx, dto.EventQueueWindowOpened, window)
+		}
+	}
+
+	closed, err := u.repo.GetClosedQueueWindows(ctx, now, now.Add(-closedWindowGrace))
+	if err != nil {
+		log.Println("Error gagal mendapatkan jendela antrian yang ditutup", err)
+	}
+	for _, window := range closed {
+		marked, err := u.repo.MarkQueueWindowClosed(ctx, window.ProjectID, window.QueueEnd)
+		if err != nil {
+			log.Println("Error gagal menandai jendela antrian", window.ProjectID, err)
+			continue
+		}
+		if marked {
+			u.publishQueueWindow(ctx, dto.EventQueueWindowClosed, window)
+		}
+	}
+}
+
+func (u *Usecase) publishQueueWindow(ctx context.Context, eventType string, window entity.QueueWindow) {
+	u.eventBus.Publish(ctx, dto.ProjectEvent{
+		Type:      eventType,
+		TenantID:  window.TenantID,
+		ProjectID: window.ProjectID,
+		Data: dto.QueueWindow{
+			QueueStart: window.QueueStart,
+			QueueEnd:   window.QueueEnd,
+		},
+	})
+}
+
+func (u *Usecase) recordStateChange(ctx context.Context, projectID, actor, from, to, reason string, autoResumeAt sql.NullTime) {
+	detail := map[string]interface{}{
+		"from":   from,
+		"to":     to,
+		"reason": reason,
+	}
+	if autoResumeAt.Valid {
+		detail["auto_resume_at"] = autoResumeAt.Time
+	}
+	err := u.historyRepo.RecordProjectHistory(ctx, projectID, actor, "change_state", detail)
+	if err != nil {
+		log.Println("Error gagal mencatat history project", err)
+	}
+}
+
+func toTierDTOs(tiers []entity.PriorityTier) []dto.PriorityTier {
+	result := make([]dto.PriorityTier, len(tiers))
+	for i, tier := range tiers {
+		result[i] = dto.PriorityTier{
+			Name:            tier.Name,
+			Priority:        tier.Priority,
+			Weight:          tier.Weight,
+			ReservedShare:   tier.ReservedShare,
+			MaxUsersInQueue: tier.MaxUsersInQueue,
+		}
+	}
+	return result
+}
+
+func (u *Usecase) loadPriorityTiers(ctx context.Context, projectID string) []dto.PriorityTier {
+	tiers, err := u.repo.GetPriorityTiers(ctx, projectID)
+	if err != nil {
+		log.Println("Error gagal mendapatkan tier prioritas", err)
+		return nil
+	}
+	return toTierDTOs(tiers)
+}
+
+func (u *Usecase) GetPriorityTiers(ctx context.Context, projectID, tenantID string) (*dto.ListPriorityTierResponse, *dto.ErrorResponse) {
+	config, err := u.repo.GetTenantConfigByProjectID(ctx, projectID, tenantID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, handleError(http.StatusNotFound, "Project dengan id tersebut tidak ditemukan")
+		}
+		log.Println("Error gagal mendapatkan konfigurasi project", err)
+		return nil, handleError(http.StatusInternalServerError, "Gagal mendapatkan tier prioritas")
+	}
+
+	tiers, err := u.repo.GetPriorityTiers(ctx, projectID)
+	if err != nil {
+		log.Println("Error gagal mendapatkan tier prioritas", err)
+		return nil, handleError(http.StatusInternalServerError, "Gagal mendapatkan tier prioritas")
+	}
+
+	return &dto.ListPriorityTierResponse{
+		ProjectID: projectID,
+		Threshold: config.Threshold,
+		Tiers:     toTierDTOs(tiers),
+	}, nil
+}
+
+func (u *Usecase) UpdatePriorityTiers(ctx context.Context, req dto.UpdatePriorityTiers, tenantID string) *dto.ErrorResponse {
+	config, err := u.repo.GetTenantConfigByProjectID(ctx, req.ProjectID, tenantID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return handleError(http.StatusNotFound, "Project dengan id tersebut tidak ditemukan")
+		}
+		log.Println("Error gagal mendapatkan konfigurasi project", err)
+		return handleError(http.StatusInternalServerError, "Gagal mengupdate tier prioritas")
+	}
+
+	err = validator.ValidatePriorityTiers(req.Tiers, config.Threshold, config.MaxUsersInQueue)
+	if err != nil {
+		return handleError(http.StatusBadRequest, err.Error())
+	}
+
+	now := time.Now()
+	tiers := make([]entity.PriorityTier, len(req.Tiers))
+	for i, tier := range req.Tiers {
+		tiers[i] = entity.PriorityTier{
+			Name:            tier.Name,
+			Priority:        tier.Priority,
+			Weight:          tier.Weight,
+			ReservedShare:   tier.ReservedShare,
+			MaxUsersInQueue: tier.MaxUsersInQueue,
+			CreatedAt:       now,
+		}
+	}
+
+	err = u.repo.ReplacePriorityTiers(ctx, req.ProjectID, tiers)
+	if err != nil {
+		log.Println("Error gagal mengupdate tier prioritas", err)
+		return handleError(http.StatusInternalServerError, "Gagal mengupdate tier prioritas")
+	}
+
+	err = u.historyRepo.RecordProjectHistory(ctx, req.ProjectID, tenantID, "update_priority_tiers", req.Tiers)
+	if err != nil {
+		log.Println("Error gagal mencatat history project", err)
+	}
+	return nil
+}
+
+func toThemeDTO(theme entity.Theme, activeThemeID string) dto.Theme {
+	return dto.Theme{
+		ID:                 theme.ID,
+		ProjectID:          theme.ProjectID.String,
+		Name:               theme.Name,
+		QueuePageStyle:     theme.QueuePageStyle,
+		QueueHTMLPage:      theme.QueueHTMLPage.String,
+		QueuePageBaseColor: theme.QueuePageBaseColor.String,
+		QueuePageTitle:     theme.QueuePageTitle.String,
+		QueuePageLogo:      theme.QueuePageLogo.String,
+		IsActive:           theme.ID == activeThemeID,
+		CreatedAt:          theme.CreatedAt,
+	}
+}
+
+func (u *Usecase) getTenantConfig(ctx context.Context, projectID, tenantID, failMessage string) (*entity.Configuration, *dto.ErrorResponse) {
+	config, err := u.repo.GetTenantConfigByProjectID(ctx, projectID, tenantID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, handleError(http.StatusNotFound, "Project dengan id tersebut tidak ditemukan")
+		}
+		log.Println("Error gagal mendapatkan konfigurasi project", err)
+		return nil, handleError(http.StatusInternalServerError, failMessage)
+	}
+	return config, nil
+}
+
+// getUsableTheme returns a theme of the tenant that can be activated on the
+// project, either one of its own themes or one from the tenant library.
+func (u *Usecase) getUsableTheme(ctx context.Context, themeID, projectID, tenantID string) (*entity.Theme, *dto.ErrorResponse) {
+	theme, err := u.themeRepo.GetTenantThemeByID(ctx, themeID, tenantID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, handleError(http.StatusNotFound, "Tema tidak ditemukan")
+		}
+		log.Println("Error gagal mendapatkan tema", err)
+		return nil, handleError(http.StatusInternalServerError, "Gagal mendapatkan tema")
+	}
+	if theme.ProjectID.Valid && theme.ProjectID.String != projectID {
+		return nil, handleError(http.StatusBadRequest, "Tema milik project lain")
+	}
+	return theme, nil
+}
+
+func (u *Usecase) CreateTheme(ctx context.Context, req dto.CreateThemeRequest, tenantID string, imageFile *multipart.FileHeader, htmlFile *multipart.FileHeader) (*dto.Theme, *dto.ErrorResponse) {
+	if req.ProjectID != "" {
+		if _, errRes := u.getTenantConfig(ctx, req.ProjectID, tenantID, "Gagal membuat tema"); errRes != nil {
+			return nil, errRes
+		}
+	}
+	if req.QueuePageStyle != "base" && req.QueuePageStyle != "custom" {
+		return nil, handleError(http.StatusBadRequest, "Tipe style tidak valid")
+	}
+
+	created, err := u.themeRepo.CreateNewTheme(ctx, entity.Theme{
+		TenantID:           tenantID,
+		ProjectID:          sql.NullString{Valid: req.ProjectID != "", String: req.ProjectID},
+		Name:               req.Name,
+		QueuePageStyle:     req.QueuePageStyle,
+		QueuePageBaseColor: sql.NullString{Valid: req.QueuePageBaseColor != "", String: req.QueuePageBaseColor},
+		QueuePageTitle:     sql.NullString{Valid: req.QueuePageTitle != "", String: req.QueuePageTitle},
+		CreatedAt:          time.Now(),
+	})
+	if err != nil {
+		if pgErr, ok := err.(*pq.Error); ok && pgErr.Code == "23505" {
+			return nil, handleError(http.StatusBadRequest, "Tema dengan nama tersebut sudah ada")
+		}
+		log.Println("Error gagal membuat tema", err)
+		return nil, handleError(http.StatusInternalServerError, "Gagal membuat tema")
+	}
+
+	// The page is uploaded under the theme id, so the row exists first and is
+	// removed again when the page can not be published.
+	filename := "theme-" + created.ID
+	page, errRes := u.publishStylePage(ctx, dto.UpdateProjectStyle{
+		ProjectID:          req.ProjectID,
+		QueuePageStyle:     req.QueuePageStyle,
+		QueuePageBaseColor: req.QueuePageBaseColor,
+		QueuePageTitle:     req.QueuePageTitle,
+		QueuePageLogo:      req.QueuePageLogo,
+	}, tenantID, filename, imageFile, htmlFile)
+	if errRes != nil {
+		if err := u.themeRepo.DeleteTheme(ctx, created.ID); err != nil {
+			log.Println("Error gagal menghapus tema", err)
+		}
+		return nil, errRes
+	}
+
+	created.QueueHTMLPage = sql.NullString{Valid: true, String: page.PageURL}
+	created.QueuePageLogo = sql.NullString{Valid: true, String: page.LogoURL}
+	err = u.themeRepo.UpdateThemePage(ctx, *created)
+	if err != nil {
+		log.Println("Error gagal mengupdate tema", err)
+		return nil, handleError(http.StatusInternalServerError, "Gagal membuat tema")
+	}
+
+	resp := toThemeDTO(*created, "")
+	resp.Violations = page.Violations
+	return &resp, nil
+}
+
+func (u *Usecase) GetListTheme(ctx context.Context, projectID, tenantID string) (*dto.ListThemeResponse, *dto.ErrorResponse) {
+	config, errRes := u.getTenantConfig(ctx, projectID, tenantID, "Gagal mendapatkan tema")
+	if errRes != nil {
+		return nil, errRes
+	}
+
+	themes, err := u.themeRepo.GetAvailableThemes(ctx, projectID, tenantID)
+	if err != nil {
+		log.Println("Error gagal mendapatkan tema", err)
+		return nil, handleError(http.StatusInternalServerError, "Gagal mendapatkan tema")
+	}
+	listThemes := make([]dto.Theme, len(themes))
+	for i, theme := range themes {
+		listThemes[i] = toThemeDTO(theme, config.ActiveThemeID.String)
+	}
+	return &dto.ListThemeResponse{
+		ProjectID:     projectID,
+		ActiveThemeID: config.ActiveThemeID.String,
+		Themes:        listThemes,
+	}, nil
+}
+
+func (u *Usecase) DeleteTheme(ctx context.Context, themeID, tenantID string) *dto.ErrorResponse {
+	_, err := u.themeRepo.GetTenantThemeByID(ctx, themeID, tenantID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return handleError(http.StatusNotFound, "Tema tidak ditemukan")
+		}
+		log.Println("Error gagal mendapatkan tema", err)
+		return handleError(http.StatusInternalServerError, "Gagal menghapus tema")
+	}
+
+	active, err := u.themeRepo.IsThemeActive(ctx, themeID)
+	if err != nil {
+		log.Println("Error gagal mengecek tema", err)
+		return handleError(http.StatusInternalServerError, "Gagal menghapus tema")
+	}
+	if active {
+		return handleError(http.StatusConflict, "Tema sedang aktif di project")
+	}
+
+	err = u.themeRepo.DeleteTheme(ctx, themeID)
+	if err != nil {
+		log.Println("Error gagal menghapus tema", err)
+		return handleError(http.StatusInternalServerError, "Gagal menghapus tema")
+	}
+	return nil
+}
+
+func (u *Usecase) ActivateTheme(ctx context.Context, req dto.ActivateThemeRequest, tenantID string) *dto.ErrorResponse {
+	config, errRes := u.getTenantConfig(ctx, req.ProjectID, tenantID, "Gagal mengaktifkan tema")
+	if errRes != nil {
+		return errRes
+	}
+	if _, errRes := u.getUsableTheme(ctx, req.ThemeID, req.ProjectID, tenantID); errRes != nil {
+		return errRes
+	}
+
+	err := u.themeRepo.ActivateTheme(ctx, req.ProjectID, req.ThemeID)
+	if err != nil {
+		log.Println("Error gagal mengaktifkan tema", err)
+		return handleError(http.StatusInternalServerError, "Gagal mengaktifkan tema")
+	}
+
+	u.recordThemeSwitch(ctx, req.ProjectID, tenantID, config.ActiveThemeID.String, req.ThemeID, "")
+	u.refreshLocalizedPages(ctx, req.ProjectID)
+	return nil
+}
+
+func (u *Usecase) ScheduleThemeSwitch(ctx context.Context, req dto.ScheduleThemeRequest, tenantID string) (*dto.ThemeSchedule, *dto.ErrorResponse) {
+	if _, errRes := u.getTenantConfig(ctx, req.ProjectID, tenantID, "Gagal menjadwalkan tema"); errRes != nil {
+		return nil, errRes
+	}
+	if _, errRes := u.getUsableTheme(ctx, req.ThemeID, req.ProjectID, tenantID); errRes != nil {
+		return nil, errRes
+	}
+
+	const layout = "2006-01-02T15:04:05"
+	switchAt, err := time.Parse(layout, req.SwitchAt)
+	if err != nil {
+		return nil, handleError(http.StatusBadRequest, "Format waktu pergantian tema salah")
+	}
+	if !switchAt.After(time.Now()) {
+		return nil, handleError(http.StatusBadRequest, "Waktu pergantian tema harus di masa depan")
+	}
+
+	schedule, err := u.themeRepo.CreateNewSchedule(ctx, entity.ThemeSchedule{
+		ProjectID: req.ProjectID,
+		ThemeID:   req.ThemeID,
+		SwitchAt:  switchAt,
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		log.Println("Error gagal menjadwalkan tema", err)
+		return nil, handleError(http.StatusInternalServerError, "Gagal menjadwalkan tema")
+	}
+
+	resp := toThemeScheduleDTO(*schedule)
+	return &resp, nil
+}
+
+func toThemeScheduleDTO(schedule entity.ThemeSchedule) dto.ThemeSchedule {
+	return dto.ThemeSchedule{
+		ID:        schedule.ID,
+		ProjectID: schedule.ProjectID,
+		ThemeID:   schedule.ThemeID,
+		SwitchAt:  schedule.SwitchAt,
+		AppliedAt: parser.ParseNullTime(schedule.AppliedAt),
+	}
+}
+
+func (u *Usecase) GetThemeSchedules(ctx context.Context, projectID, tenantID string) (*dto.ListThemeScheduleResponse, *dto.ErrorResponse) {
+	if _, errRes := u.getTenantConfig(ctx, projectID, tenantID, "Gagal mendapatkan jadwal tema"); errRes != nil {
+		return nil, errRes
+	}
+
+	schedules, err := u.themeRepo.GetProjectSchedules(ctx, projectID)
+	if err != nil {
+		log.Println("Error gagal mendapatkan jadwal tema", err)
+		return nil, handleError(http.StatusInternalServerError, "Gagal mendapatkan jadwal tema")
+	}
+	listSchedules := make([]dto.ThemeSchedule, len(schedules))
+	for i, schedule := range schedules {
+		listSchedules[i] = toThemeScheduleDTO(schedule)
+	}
+	return &dto.ListThemeScheduleResponse{
+		ProjectID: projectID,
+		Schedules: listSchedules,
+	}, nil
+}
+
+func (u *Usecase) DeleteThemeSchedule(ctx context.Context, scheduleID, tenantID string) *dto.ErrorResponse {
+	_, err := u.themeRepo.GetTenantScheduleByID(ctx, scheduleID, tenantID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return handleError(http.StatusNotFound, "Jadwal tema tidak ditemukan")
+		}
+		log.Println("Error gagal mendapatkan jadwal tema", err)
+		return handleError(http.StatusInternalServerError, "Gagal menghapus jadwal tema")
+	}
+
+	err = u.themeRepo.DeleteSchedule(ctx, scheduleID)
+	if err != nil {
+		log.Println("Error gagal menghapus jadwal tema", err)
+		return handleError(http.StatusInternalServerError, "Gagal menghapus jadwal tema")
+	}
+	return nil
+}
+
+// ApplyDueThemeSwitches is run by the worker and activates every scheduled
+// theme whose switch time has passed.
+func (u *Usecase) ApplyDueThemeSwitches(ctx context.Context) {
+	now := time.Now()
+	schedules, err := u.themeRepo.GetDueSchedules(ctx, now)
+	if err != nil {
+		log.Println("Error gagal mendapatkan jadwal tema", err)
+		return
+	}
+
+	for _, schedule := range schedules {
+		config, err := u.repo.GetConfigByProjectID(ctx, schedule.ProjectID)
+		if err != nil {
+			log.Println("Error gagal mendapatkan konfigurasi project", schedule.ProjectID, err)
+			continue
+		}
+		err = u.themeRepo.ApplySchedule(ctx, schedule, now)
+		if err != nil {
+			if err != sql.ErrNoRows {
+				log.Println("Error gagal mengganti tema", schedule.ProjectID, err)
+			}
+			continue
+		}
+		u.recordThemeSwitch(ctx, schedule.ProjectID, "", config.ActiveThemeID.String, schedule.ThemeID, schedule.ID)
+		u.refreshLocalizedPages(ctx, schedule.ProjectID)
+	}
+}
+
+func (u *Usecase) recordThemeSwitch(ctx context.Context, projectID, actor, from, to, scheduleID string) {
+	detail := map[string]string{
+		"from": from,
+		"to":   to,
+	}
+	if scheduleID != "" {
+		detail["schedule_id"] = scheduleID
+	}
+	err := u.historyRepo.RecordProjectHistory(ctx, projectID, actor, "activate_theme", detail)
+	if err != nil {
+		log.Println("Error gagal mencatat history project", err)
+	}
+}
+
+func localeFilename(projectID, locale string) string {
+	return projectID + "." + locale
+}
+
+// LocalizedPages lists the locales of the project and the page rendered for
+// each of them. Custom pages are uploaded as they are and have no variants.
+func (u *Usecase) LocalizedPages(ctx context.Context, projectID, queuePageStyle string) ([]string, map[string]string) {
+	if queuePageStyle != "base" {
+		return nil, nil
+	}
+	locales, err := u.repo.GetProjectLocales(ctx, projectID)
+	if err != nil {
+		log.Println("Error gagal mendapatkan bahasa project", err)
+		return nil, nil
+	}
+	if len(locales) == 0 {
+		return nil, nil
+	}
+	filenames := make([]string, len(locales))
+	for i, locale := range locales {
+		filenames[i] = localeFilename(projectID, locale.Locale)
+	}
+	urls, err := u.assetRepo.GetCurrentPageURLs(ctx, filenames)
+	if err != nil {
+		log.Println("Error gagal mendapatkan halaman bahasa project", err)
+		return nil, nil
+	}
+
+	names := []string{}
+	pages := map[string]string{}
+	for _, locale := range locales {
+		pageURL, ok := urls[localeFilename(projectID, locale.Locale)]
+		if !ok {
+			continue
+		}
+		names = append(names, locale.Locale)
+		pages[locale.Locale] = pageURL
+	}
+	if len(names) == 0 {
+		return nil, nil
+	}
+	return names, pages
+}
+
+// refreshLocalizedPages renders the active base style of the project once per
+// configured locale. Failures are logged, the default page stays in place.
+func (u *Usecase) refreshLocalizedPages(ctx context.Context, projectID string) {
+	config, err := u.repo.GetConfigByProjectID(ctx, projectID)
+	if err != nil {
+		log.Println("Error gagal mendapatkan konfigurasi project", err)
+		return
+	}
+	if config.QueuePageStyle != "base" {
+		return
+	}
+	locales, err := u.repo.GetProjectLocales(ctx, projectID)
+	if err != nil {
+		log.Println("Error gagal mendapatkan bahasa project", err)
+		return
+	}
+
+	for _, locale := range locales {
+		text := dto.QueuePageText{}
+		if err := json.Unmarshal(locale.Texts, &text); err != nil {
+			log.Println("Error gagal membaca teks bahasa", locale.Locale, err)
+			continue
+		}
+		htmlPage, err := renderer.RenderQueuePage(renderer.QueuePageData{
+			Title:     config.QueuePageTitle.String,
+			LogoURL:   config.QueuePageLogo.String,
+			BaseColor: config.QueuePageBaseColor.String,
+			Locale:    locale.Locale,
+			Text:      text,
+		})
+		if err != nil {
+			log.Println("Error gagal membuat halaman antrian", locale.Locale, err)
+			continue
+		}
+		_, err = u.uploadPage(ctx, projectID, "", localeFilename(projectID, locale.Locale), htmlPage, config.QueuePageLogo.String)
+		if err != nil {
+			log.Println("Error gagal upload HTML file", locale.Locale, err)
+		}
+	}
+}
+
+func toProjectLocaleDTO(locale entity.ProjectLocale) dto.ProjectLocale {
+	text := dto.QueuePageText{}
+	if err := json.Unmarshal(locale.Texts, &text); err != nil {
+		log.Println("Error gagal membaca teks bahasa", locale.Locale, err)
+	}
+	return dto.ProjectLocale{
+		Locale: locale.Locale,
+		Texts:  renderer.LocalizedText(locale.Locale, text),
+	}
+}
+
+func (u *Usecase) GetProjectLocales(ctx context.Context, projectID, tenantID string) (*dto.ListProjectLocaleResponse, *dto.ErrorResponse) {
+	if _, errRes := u.getTenantConfig(ctx, projectID, tenantID, "Gagal mendapatkan bahasa project"); errRes != nil {
+		return nil, errRes
+	}
+
+	locales, err := u.repo.GetProjectLocales(ctx, projectID)
+	if err != nil {
+		log.Println("Error gagal mendapatkan bahasa project", err)
+		return nil, handleError(http.StatusInternalServerError, "Gagal mendapatkan bahasa project")
+	}
+	listLocales := make([]dto.ProjectLocale, len(locales))
+	for i, locale := range locales {
+		listLocales[i] = toProjectLocaleDTO(locale)
+	}
+	return &dto.ListProjectLocaleResponse{
+		ProjectID: projectID,
+		Locales:   listLocales,
+	}, nil
+}
+
+func (u *Usecase) UpdateProjectLocale(ctx context.Context, req dto.UpdateProjectLocaleRequest, tenantID string) *dto.ErrorResponse {
+	if _, errRes := u.getTenantConfig(ctx, req.ProjectID, tenantID, "Gagal mengupdate bahasa project"); errRes != nil {
+		return errRes
+	}
+
+	texts, err := json.Marshal(req.Texts)
+	if err != nil {
+		return handleError(http.StatusBadRequest, "Teks bahasa tidak sesuai format")
+	}
+	err = u.repo.UpsertProjectLocale(ctx, entity.ProjectLocale{
+		ProjectID: req.ProjectID,
+		Locale:    req.Locale,
+		Texts:     texts,
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		log.Println("Error gagal mengupdate bahasa project", err)
+		return handleError(http.StatusInternalServerError, "Gagal mengupdate bahasa project")
+	}
+
+	err = u.historyRepo.RecordProjectHistory(ctx, req.ProjectID, tenantID, "update_locale", req)
+	if err != nil {
+		log.Println("Error gagal mencatat history project", err)
+	}
+	u.refreshLocalizedPages(ctx, req.ProjectID)
+	return nil
+}
+
+func (u *Usecase) DeleteProjectLocale(ctx context.Context, projectID, locale, tenantID string) *dto.ErrorResponse {
+	if _, errRes := u.getTenantConfig(ctx, projectID, tenantID, "Gagal menghapus bahasa project"); errRes != nil {
+		return errRes
+	}
+
+	err := u.repo.DeleteProjectLocale(ctx, projectID, locale)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return handleError(http.StatusNotFound, "Bahasa tidak ditemukan")
+		}
+		log.Println("Error gagal menghapus bahasa project", err)
+		return handleError(http.StatusInternalServerError, "Gagal menghapus bahasa project")
+	}
+
+	err = u.historyRepo.RecordProjectHistory(ctx, projectID, tenantID, "delete_locale", map[string]string{"locale": locale})
+	if err != nil {
+		log.Println("Error gagal mencatat history project", err)
+	}
+	return nil
+}
+
+func (u *Usecase) GetPageVersions(ctx context.Context, projectID, tenantID string) (*dto.ListPageVersionResponse, *dto.ErrorResponse) {
+	if _, errRes := u.getTenantConfig(ctx, projectID, tenantID, "Gagal mendapatkan versi halaman"); errRes != nil {
+		return nil, errRes
+	}
+
+	versions, err := u.assetRepo.GetProjectPageVersions(ctx, projectID)
+	if err != nil {
+		log.Println("Error gagal mendapatkan versi halaman", err)
+		return nil, handleError(http.StatusInternalServerError, "Gagal mendapatkan versi halaman")
+	}
+
+	// Versions are ordered newest first per page, so the first one seen of
+	// each page is the current one.
+	resp := []dto.PageVersion{}
+	seen := map[string]bool{}
+	for _, version := range versions {
+		resp = append(resp, dto.PageVersion{
+			ID:          version.ID,
+			Name:        version.Name,
+			URL:         version.URL,
+			Hash:        version.Hash,
+			IsCurrent:   !seen[version.Name],
+			PublishedAt: version.PublishedAt,
+			CreatedAt:   version.CreatedAt,
+		})
+		seen[version.Name] = true
+	}
+
+	return &dto.ListPageVersionResponse{
+		ProjectID: projectID,
+		Versions:  resp,
+	}, nil
+}
+
+func (u *Usecase) RollbackPage(ctx context.Context, req dto.RollbackPageRequest, tenantID string) *dto.ErrorResponse {
+	if _, errRes := u.getTenantConfig(ctx, req.ProjectID, tenantID, "Gagal mengembalikan versi halaman"); errRes != nil {
+		return errRes
+	}
+
+	version, err := u.assetRepo.GetTenantPageVersionByID(ctx, req.VersionID, tenantID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return handleError(http.StatusNotFound, "Versi halaman tidak ditemukan")
+		}
+		log.Println("Error gagal mendapatkan versi halaman", err)
+		return handleError(http.StatusInternalServerError, "Gagal mengembalikan versi halaman")
+	}
+	if version.ProjectID.String != req.ProjectID {
+		return handleError(http.StatusNotFound, "Versi halaman tidak ditemukan")
+	}
+
+	err = u.assetRepo.RollbackPage(ctx, *version, time.Now())
+	if err != nil {
+		log.Println("Error gagal mengembalikan versi halaman", err)
+		return handleError(http.StatusInternalServerError, "Gagal mengembalikan versi halaman")
+	}
+
+	err = u.historyRepo.RecordProjectHistory(ctx, req.ProjectID, tenantID, "rollback_page", map[string]string{
+		"version_id": version.ID,
+		"name":       version.Name,
+		"url":        version.URL,
+	})
+	if err != nil {
+		log.Println("Error gagal mencatat history project", err)
+	}
+	return nil
+}
+
+// CleanupAssetVersions is run by the worker and removes page versions and
+// logos that nothing points at once the retention period of the stage has
+// passed. Rows are only deleted after their file is, so nothing is cleaned up
+// on the infra manager backend, which cannot delete files.
+func (u *Usecase) CleanupAssetVersions(ctx context.Context) {
+	if storage.StageConfig(u.cfg).Backend == storage.BackendInfraManager {
+		return
+	}
+
+	retentionHours := storage.StageConfig(u.cfg).RetentionHours
+	if retentionHours <= 0 {
+		retentionHours = defaultRetentionHours
+	}
+	before := time.Now().Add(-time.Duration(retentionHours) * time.Hour)
+
+	versions, err := u.assetRepo.GetExpiredPageVersions(ctx, before)
+	if err != nil {
+		log.Println("Error gagal mendapatkan versi halaman", err)
+		return
+	}
+	for _, version := range versions {
+		if err := u.storage.DeleteHTML(ctx, version.ObjectName); err != nil {
+			log.Println("Error gagal menghapus file HTML", version.ObjectName, err)
+			continue
+		}
+		if err := u.assetRepo.DeletePageVersion(ctx, version.ID); err != nil {
+			log.Println("Error gagal menghapus versi halaman", version.ID, err)
+		}
+	}
+
+	logos, err := u.assetRepo.GetExpiredLogos(ctx, before)
+	if err != nil {
+		log.Println("Error gagal mendapatkan logo", err)
+		return
+	}
+	for _, logo := range logos {
+		if err := u.storage.DeleteAsset(ctx, logo.Name); err != nil {
+			log.Println("Error gagal menghapus file logo", logo.Name, err)
+			continue
+		}
+		if err := u.assetRepo.DeleteLogo(ctx, logo.Hash); err != nil {
+			log.Println("Error gagal menghapus logo", logo.Hash, err)
+		}
+	}
+}
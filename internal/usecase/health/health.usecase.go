@@ -0,0 +1,255 @@
+package health
+
+import (
+	"antrein/bc-dashboard/internal/repository/configuration"
+	"antrein/bc-dashboard/internal/repository/event"
+	"antrein/bc-dashboard/internal/repository/health"
+	"antrein/bc-dashboard/internal/repository/infra"
+	"antrein/bc-dashboard/model/config"
+	"antrein/bc-dashboard/model/dto"
+	"antrein/bc-dashboard/model/entity"
+	"context"
+	"database/sql"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	probeTimeout       = 5 * time.Second
+	maxConcurrentProbe = 10
+	// checkRetention is how long single checks are kept, incidents are kept
+	// for as long as the project exists.
+	checkRetention = 30 * 24 * time.Hour
+)
+
+type Usecase struct {
+	cfg        *config.Config
+	repo       *health.Repository
+	configRepo *configuration.Repository
+	infraRepo  infra.InfraManager
+	eventBus   *event.Bus
+}
+
+func New(cfg *config.Config, repo *health.Repository, configRepo *configuration.Repository, infraRepo infra.InfraManager, eventBus *event.Bus) *Usecase {
+	return &Usecase{
+		cfg:        cfg,
+		repo:       repo,
+		configRepo: configRepo,
+		infraRepo:  infraRepo,
+		eventBus:   eventBus,
+	}
+}
+
+func handleError(status int, message string) *dto.ErrorResponse {
+	return &dto.ErrorResponse{
+		Status: status,
+		Error:  message,
+	}
+}
+
+// ProbeProjects checks the health of every configured project, a few at a
+// time, and records the results.
+func (u *Usecase) ProbeProjects(ctx context.Context) {
+	targets, err := u.repo.GetHealthTargets(ctx)
+	if err != nil {
+		log.Println("Error gagal mendapatkan project untuk dicek", err)
+		return
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, maxConcurrentProbe)
+	for _, target := range targets {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(target entity.HealthTarget) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			u.probeProject(ctx, target)
+		}(target)
+	}
+	wg.Wait()
+}
+
+// probeProject records one check. When the infra manager can not answer the
+// health is unknown, so nothing is recorded and no incident is touched. Any
+// change of health, and a first check that is unhealthy, opens or closes an
+// incident and raises project.health_changed.
+func (u *Usecase) probeProject(ctx context.Context, target entity.HealthTarget) {
+	probeCtx, cancel := context.WithTimeout(ctx, probeTimeout)
+	healthiness, err := u.infraRepo.CheckHealthProject(probeCtx, target.ProjectID)
+	cancel()
+	if err != nil {
+		log.Println("Error gagal mengecek kesehatan project", target.ProjectID, err)
+		return
+	}
+
+	check := entity.HealthCheck{
+		ProjectID: target.ProjectID,
+		Healthy:   healthiness,
+		CheckedAt: time.Now(),
+	}
+
+	changed := target.LastHealthy.Valid && target.LastHealthy.Bool != check.Healthy ||
+		!target.LastHealthy.Valid && !check.Healthy
+	incidentID, err := u.repo.RecordHealthCheck(ctx, check, changed)
+	if err != nil {
+		log.Println("Error gagal mencatat kesehatan project", target.ProjectID, err)
+		return
+	}
+	if !changed {
+		return
+	}
+
+	u.eventBus.Publish(ctx, dto.ProjectEvent{
+		Type:      dto.EventHealthChanged,
+		TenantID:  target.TenantID,
+		ProjectID: target.ProjectID,
+		Data: dto.HealthChange{
+			Healthy:    check.Healthy,
+			IncidentID: incidentID,
+			ChangedAt:  check.CheckedAt,
+		},
+	})
+}
+
+func (u *Usecase) CleanupHealthChecks(ctx context.Context) {
+	err := u.repo.DeleteHealthChecksBefore(ctx, time.Now().Add(-checkRetention))
+	if err != nil {
+		log.Println("Error gagal menghapus riwayat kesehatan project", err)
+	}
+}
+
+func (u *Usecase) checkProject(ctx context.Context, projectID, tenantID string) *dto.ErrorResponse {
+	_, err := u.configRepo.GetTenantConfigByProjectID(ctx, projectID, tenantID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return handleError(http.StatusNotFound, "Project dengan id tersebut tidak ditemukan")
+		}
+		log.Println("Error gagal mendapatkan konfigurasi project", err)
+		return handleError(http.StatusInternalServerError, "Gagal mendapatkan kesehatan project")
+	}
+	return nil
+}
+
+func toIncidentDTO(incident entity.HealthIncident, now time.Time) dto.HealthIncident {
+	end := now
+	resp := dto.HealthIncident{
+		ID:        incident.ID,
+		StartedAt: incident.StartedAt,
+		Ongoing:   !incident.EndedAt.Valid,
+	}
+	if incident.EndedAt.Valid {
+		end = incident.EndedAt.Time
+		resp.EndedAt = &incident.EndedAt.Time
+	}
+	resp.DurationSeconds = int64(end.Sub(incident.StartedAt).Seconds())
+	return resp
+}
+
+// GetUptime reports the uptime of the last days. Downtime is the time spent
+// in incidents, so it does not depend on how often the project was probed.
+func (u *Usecase) GetUptime(ctx context.Context, projectID, tenantID string, days int) (*dto.HealthUptimeResponse, *dto.ErrorResponse) {
+	if errRes := u.checkProject(ctx, projectID, tenantID); errRes != nil {
+		return nil, errRes
+	}
+
+	to := time.Now()
+	from := to.AddDate(0, 0, -days)
+	summary, err := u.repo.GetHealthSummary(ctx, projectID, from)
+	if err != nil {
+		log.Println("Error gagal mendapatkan ringkasan kesehatan project", err)
+		return nil, handleError(http.StatusInternalServerError, "Gagal mendapatkan uptime project")
+	}
+	incidents, err := u.repo.GetIncidents(ctx, projectID, from)
+	if err != nil {
+		log.Println("Error gagal mendapatkan insiden project", err)
+		return nil, handleError(http.StatusInternalServerError, "Gagal mendapatkan uptime project")
+	}
+
+	resp := dto.HealthUptimeResponse{
+		ProjectID:     projectID,
+		From:          from,
+		To:            to,
+		TotalChecks:   summary.Total,
+		HealthyChecks: summary.Healthy,
+		Incidents:     len(incidents),
+	}
+	if !summary.FirstCheckedAt.Valid {
+		return &resp, nil
+	}
+
+	monitoredFrom := summary.FirstCheckedAt.Time
+	var downtime time.Duration
+	for _, incident := range incidents {
+		start, end := incident.StartedAt, to
+		if incident.EndedAt.Valid {
+			end = incident.EndedAt.Time
+		}
+		if start.Before(from) {
+			// An incident that was already open when the window started
+			// means the project was monitored for the whole window.
+			start = from
+			monitoredFrom = from
+		}
+		if end.After(start) {
+			downtime += end.Sub(start)
+		}
+	}
+
+	resp.MonitoredFrom = &monitoredFrom
+	resp.DowntimeSeconds = int64(downtime.Seconds())
+	if monitored := to.Sub(monitoredFrom); monitored > 0 {
+		uptime := 100 * (1 - downtime.Seconds()/monitored.Seconds())
+		uptime = max(0, min(100, uptime))
+		resp.UptimePercent = &uptime
+	}
+	return &resp, nil
+}
+
+func (u *Usecase) GetIncidents(ctx context.Context, projectID, tenantID string, days int) (*dto.ListHealthIncidentResponse, *dto.ErrorResponse) {
+	if errRes := u.checkProject(ctx, projectID, tenantID); errRes != nil {
+		return nil, errRes
+	}
+
+	now := time.Now()
+	incidents, err := u.repo.GetIncidents(ctx, projectID, now.AddDate(0, 0, -days))
+	if err != nil {
+		log.Println("Error gagal mendapatkan insiden project", err)
+		return nil, handleError(http.StatusInternalServerError, "Gagal mendapatkan insiden project")
+	}
+
+	resp := dto.ListHealthIncidentResponse{
+		ProjectID: projectID,
+		Incidents: make([]dto.HealthIncident, len(incidents)),
+	}
+	for i, incident := range incidents {
+		resp.Incidents[i] = toIncidentDTO(incident, now)
+	}
+	return &resp, nil
+}
+
+func (u *Usecase) GetTimeline(ctx context.Context, projectID, tenantID string, limit int) (*dto.HealthTimelineResponse, *dto.ErrorResponse) {
+	if errRes := u.checkProject(ctx, projectID, tenantID); errRes != nil {
+		return nil, errRes
+	}
+
+	checks, err := u.repo.GetHealthChecks(ctx, projectID, limit)
+	if err != nil {
+		log.Println("Error gagal mendapatkan riwayat kesehatan project", err)
+		return nil, handleError(http.StatusInternalServerError, "Gagal mendapatkan riwayat kesehatan project")
+	}
+
+	resp := dto.HealthTimelineResponse{
+		ProjectID: projectID,
+		Checks:    make([]dto.HealthCheck, len(checks)),
+	}
+	for i, check := range checks {
+		resp.Checks[i] = dto.HealthCheck{
+			Healthy:   check.Healthy,
+			CheckedAt: check.CheckedAt,
+		}
+	}
+	return &resp, nil
+}
@@ -0,0 +1,278 @@
+package manifest
+
+import (
+	"antrein/bc-dashboard/internal/repository/project"
+	"antrein/bc-dashboard/internal/usecase/configuration"
+	projectUsecase "antrein/bc-dashboard/internal/usecase/project"
+	"antrein/bc-dashboard/model/config"
+	"antrein/bc-dashboard/model/dto"
+	"antrein/bc-dashboard/model/entity"
+	"context"
+	"database/sql"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const timeLayout = "2006-01-02T15:04:05"
+
+type Usecase struct {
+	cfg            *config.Config
+	projectRepo    *project.Repository
+	projectUsecase *projectUsecase.Usecase
+	configUsecase  *configuration.Usecase
+}
+
+func New(cfg *config.Config, projectRepo *project.Repository, projectUsecase *projectUsecase.Usecase, configUsecase *configuration.Usecase) *Usecase {
+	return &Usecase{
+		cfg:            cfg,
+		projectRepo:    projectRepo,
+		projectUsecase: projectUsecase,
+		configUsecase:  configUsecase,
+	}
+}
+
+type manifestField struct {
+	group string
+	name  string
+	value string
+}
+
+func handleError(status int, message string) *dto.ErrorResponse {
+	return &dto.ErrorResponse{
+		Status: status,
+		Error:  message,
+	}
+}
+
+func formatTime(t sql.NullTime) string {
+	if !t.Valid || t.Time.IsZero() {
+		return ""
+	}
+	return t.Time.Format(timeLayout)
+}
+
+func toManifest(p *entity.ProjectWithConfig) dto.ProjectManifest {
+	return dto.ProjectManifest{
+		APIVersion: dto.ManifestAPIVersion,
+		Kind:       dto.ManifestKind,
+		Metadata: dto.ManifestMetadata{
+			ID:   p.ProjectID,
+			Name: p.Name,
+		},
+		Configuration: dto.ManifestConfiguration{
+			Threshold:       p.Threshold,
+			SessionTime:     p.SessionTime,
+			Host:            p.Host.String,
+			BaseURL:         p.BaseURL.String,
+			MaxUsersInQueue: p.MaxUsersInQueue,
+			QueueMode:       p.QueueMode,
+		},
+		Schedule: dto.ManifestSchedule{
+			QueueStart:   formatTime(p.QueueStart),
+			QueueEnd:     formatTime(p.QueueEnd),
+			PreQueueOpen: formatTime(p.PreQueueOpenAt),
+		},
+		Style: dto.ManifestStyle{
+			QueuePageStyle:     p.QueuePageStyle,
+			QueuePageBaseColor: p.QueuePageBaseColor.String,
+			QueuePageTitle:     p.QueuePageTitle.String,
+			QueuePageLogo:      p.QueuePageLogo.String,
+			QueueHTMLPage:      p.QueueHTMLPage.String,
+		},
+	}
+}
+
+// defaultManifest mirrors the column defaults of a freshly registered project,
+// so that a plan for a new project only lists the values the manifest sets.
+func defaultManifest(id string) dto.ProjectManifest {
+	return dto.ProjectManifest{
+		APIVersion: dto.ManifestAPIVersion,
+		Kind:       dto.ManifestKind,
+		Metadata: dto.ManifestMetadata{
+			ID: id,
+		},
+		Configuration: dto.ManifestConfiguration{
+			SessionTime: 5,
+			QueueMode:   dto.QueueModeFIFO,
+		},
+		Style: dto.ManifestStyle{
+			QueuePageStyle: "base",
+		},
+	}
+}
+
+func flatten(m dto.ProjectManifest) []manifestField {
+	return []manifestField{
+		{"metadata", "metadata.name", m.Metadata.Name},
+		{"configuration", "configuration.threshold", strconv.Itoa(m.Configuration.Threshold)},
+		{"configuration", "configuration.session_time", strconv.Itoa(m.Configuration.SessionTime)},
+		{"configuration", "configuration.host", m.Configuration.Host},
+		{"configuration", "configuration.base_url", m.Configuration.BaseURL},
+		{"configuration", "configuration.max_users_in_queue", strconv.Itoa(m.Configuration.MaxUsersInQueue)},
+		{"configuration", "schedule.queue_start", m.Schedule.QueueStart},
+		{"configuration", "configuration.queue_mode", m.Configuration.QueueMode},
+		{"configuration", "schedule.queue_end", m.Schedule.QueueEnd},
+		{"configuration", "schedule.pre_queue_open", m.Schedule.PreQueueOpen},
+		{"style", "style.queue_page_style", m.Style.QueuePageStyle},
+		{"style", "style.queue_page_base_color", m.Style.QueuePageBaseColor},
+		{"style", "style.queue_page_title", m.Style.QueuePageTitle},
+		{"style", "style.queue_page_logo", m.Style.QueuePageLogo},
+		{"style", "style.queue_html_page", m.Style.QueueHTMLPage},
+	}
+}
+
+// diff returns the changes needed to go from current to desired, grouped by the
+// section they belong to. Empty style values in the desired manifest are left
+// untouched because they are produced by the upload itself.
+func diff(current, desired dto.ProjectManifest) ([]dto.ManifestChange, map[string]bool) {
+	changes := []dto.ManifestChange{}
+	groups := map[string]bool{}
+	from := flatten(current)
+	to := flatten(desired)
+	for i := range to {
+		if to[i].group == "style" && to[i].value == "" {
+			continue
+		}
+		if from[i].value == to[i].value {
+			continue
+		}
+		changes = append(changes, dto.ManifestChange{
+			Field: to[i].name,
+			From:  from[i].value,
+			To:    to[i].value,
+		})
+		groups[to[i].group] = true
+	}
+	return changes, groups
+}
+
+func (u *Usecase) ExportProject(ctx context.Context, projectID, tenantID string) (*dto.ProjectManifest, *dto.ErrorResponse) {
+	project, err := u.projectRepo.GetTenantProjectByID(ctx, projectID, tenantID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, handleError(http.StatusNotFound, "Project dengan id tersebut tidak ditemukan")
+		}
+		log.Println("Error gagal export project", err)
+		return nil, handleError(http.StatusInternalServerError, "Gagal export project")
+	}
+	manifest := toManifest(project)
+	return &manifest, nil
+}
+
+func (u *Usecase) ImportProject(ctx context.Context, req dto.ProjectManifest, tenantID string, dryRun bool) (*dto.ImportProjectResponse, *dto.ErrorResponse) {
+	if req.APIVersion != dto.ManifestAPIVersion {
+		return nil, handleError(http.StatusBadRequest, "Versi manifest tidak didukung")
+	}
+	if req.Kind != dto.ManifestKind {
+		return nil, handleError(http.StatusBadRequest, "Jenis manifest tidak didukung")
+	}
+	if req.Style.QueuePageStyle == "" {
+		req.Style.QueuePageStyle = "base"
+	}
+	if req.Configuration.QueueMode == "" {
+		req.Configuration.QueueMode = dto.QueueModeFIFO
+	}
+
+	action := "update"
+	var current dto.ProjectManifest
+	existing, err := u.projectRepo.GetTenantProjectByID(ctx, req.Metadata.ID, tenantID)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			log.Println("Error gagal import project", err)
+			return nil, handleError(http.StatusInternalServerError, "Gagal import project")
+		}
+		action = "create"
+		current = defaultManifest(req.Metadata.ID)
+	} else {
+		current = toManifest(existing)
+	}
+
+	changes, groups := diff(current, req)
+	if action == "update" && len(changes) == 0 {
+		action = "noop"
+	}
+
+	if groups["configuration"] {
+		if _, err := time.Parse(timeLayout, req.Schedule.QueueStart); err != nil {
+			return nil, handleError(http.StatusBadRequest, "Format waktu queue mulai salah")
+		}
+		if _, err := time.Parse(timeLayout, req.Schedule.QueueEnd); err != nil {
+			return nil, handleError(http.StatusBadRequest, "Format waktu queue berakhir salah")
+		}
+	}
+	if groups["style"] {
+		switch req.Style.QueuePageStyle {
+		case "base":
+		case "custom":
+			if current.Style.QueuePageStyle != "custom" || req.Style.QueueHTMLPage != current.Style.QueueHTMLPage {
+				return nil, handleError(http.StatusBadRequest, "Style custom harus diupload melalui endpoint style")
+			}
+		default:
+			return nil, handleError(http.StatusBadRequest, "Tipe style tidak valid")
+		}
+	}
+
+	resp := &dto.ImportProjectResponse{
+		ProjectID: req.Metadata.ID,
+		Action:    action,
+		DryRun:    dryRun,
+		Changes:   changes,
+	}
+	if dryRun || action == "noop" {
+		return resp, nil
+	}
+
+	if action == "create" {
+		_, errRes := u.projectUsecase.RegisterNewProject(ctx, dto.CreateProjectRequest{
+			ID:   req.Metadata.ID,
+			Name: req.Metadata.Name,
+		}, tenantID)
+		if errRes != nil {
+			return nil, errRes
+		}
+	}
+
+	if action == "update" && groups["metadata"] {
+		_, errRes := u.projectUsecase.UpdateProject(ctx, req.Metadata.ID, dto.UpdateProjectRequest{
+			Name: &req.Metadata.Name,
+		}, tenantID)
+		if errRes != nil {
+			return nil, errRes
+		}
+	}
+
+	if groups["configuration"] {
+		errRes := u.configUsecase.UpdateProjectConfig(ctx, dto.UpdateProjectConfig{
+			ProjectID:       req.Metadata.ID,
+			Threshold:       req.Configuration.Threshold,
+			SessionTime:     req.Configuration.SessionTime,
+			Host:            req.Configuration.Host,
+			BaseURL:         req.Configuration.BaseURL,
+			MaxUsersInQueue: req.Configuration.MaxUsersInQueue,
+			QueueStart:      req.Schedule.QueueStart,
+			QueueEnd:        req.Schedule.QueueEnd,
+			QueueMode:       req.Configuration.QueueMode,
+			PreQueueOpen:    req.Schedule.PreQueueOpen,
+		})
+		if errRes != nil {
+			return nil, errRes
+		}
+	}
+
+	if groups["style"] && req.Style.QueuePageStyle == "base" {
+		_, errRes := u.configUsecase.UpdateProjectStyle(ctx, dto.UpdateProjectStyle{
+			ProjectID:          req.Metadata.ID,
+			QueuePageStyle:     req.Style.QueuePageStyle,
+			QueuePageBaseColor: req.Style.QueuePageBaseColor,
+			QueuePageTitle:     req.Style.QueuePageTitle,
+			QueuePageLogo:      req.Style.QueuePageLogo,
+		}, nil, nil)
+		if errRes != nil {
+			return nil, errRes
+		}
+	}
+
+	return resp, nil
+}
@@ -1,30 +1,50 @@
 package project
 
 import (
+	"antrein/bc-dashboard/internal/repository/event"
+	"antrein/bc-dashboard/internal/repository/history"
 	"antrein/bc-dashboard/internal/repository/infra"
 	"antrein/bc-dashboard/internal/repository/project"
+	"antrein/bc-dashboard/internal/repository/template"
+	"antrein/bc-dashboard/internal/usecase/configuration"
+	"antrein/bc-dashboard/internal/usecase/provisioning"
+	"antrein/bc-dashboard/internal/utils/parser"
 	"antrein/bc-dashboard/model/config"
 	"antrein/bc-dashboard/model/dto"
 	"antrein/bc-dashboard/model/entity"
 	"context"
+	"database/sql"
+	"errors"
 	"log"
 	"net/http"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/lib/pq"
 )
 
 type Usecase struct {
-	cfg       *config.Config
-	repo      *project.Repository
-	infraRepo *infra.Repository
+	cfg                 *config.Config
+	repo                *project.Repository
+	infraRepo           infra.InfraManager
+	templateRepo        *template.Repository
+	historyRepo         *history.Repository
+	configUsecase       *configuration.Usecase
+	provisioningUsecase *provisioning.Usecase
+	eventBus            *event.Bus
 }
 
-func New(cfg *config.Config, repo *project.Repository, infraRepo *infra.Repository) *Usecase {
+func New(cfg *config.Config, repo *project.Repository, infraRepo infra.InfraManager, templateRepo *template.Repository, historyRepo *history.Repository, configUsecase *configuration.Usecase, provisioningUsecase *provisioning.Usecase, eventBus *event.Bus) *Usecase {
 	return &Usecase{
-		cfg:       cfg,
-		repo:      repo,
-		infraRepo: infraRepo,
+		cfg:                 cfg,
+		repo:                repo,
+		infraRepo:           infraRepo,
+		templateRepo:        templateRepo,
+		historyRepo:         historyRepo,
+		configUsecase:       configUsecase,
+		provisioningUsecase: provisioningUsecase,
+		eventBus:            eventBus,
 	}
 }
 
@@ -37,6 +57,13 @@ func (u *Usecase) RegisterNewProject(ctx context.Context, req dto.CreateProjectR
 		TenantID:  tenantID,
 		CreatedAt: time.Now(),
 	}
+	if errInfra := u.resolveProjectInfra(ctx, &project, req); errInfra != nil {
+		return nil, errInfra
+	}
+
+	if req.TemplateID != "" {
+		return u.registerProjectFromTemplate(ctx, project, req.TemplateID)
+	}
 
 	created, err := u.repo.CreateNewProject(ctx, project)
 	if err != nil {
@@ -55,18 +82,165 @@ func (u *Usecase) RegisterNewProject(ctx context.Context, req dto.CreateProjectR
 		return nil, &errRes
 	}
 
-	return &dto.CreateProjectResponse{
+	return u.projectCreated(ctx, *created), nil
+}
+
+// projectCreated raises project.created and returns the created project.
+func (u *Usecase) projectCreated(ctx context.Context, created entity.Project) *dto.CreateProjectResponse {
+	resp := dto.CreateProjectResponse{
 		Project: dto.Project{
 			ID:       created.ID,
 			Name:     created.Name,
 			TenantID: created.TenantID,
 		},
-	}, nil
+	}
+	u.eventBus.Publish(ctx, dto.ProjectEvent{
+		Type:      dto.EventProjectCreated,
+		TenantID:  created.TenantID,
+		ProjectID: created.ID,
+		Data:      resp.Project,
+	})
+	return &resp
 }
 
-func (u *Usecase) GetListProject(ctx context.Context, tenantID string) (*dto.ListProjectResponse, *dto.ErrorResponse) {
+// resolveProjectInfra sets the infra mode override and resource size of a
+// new project. The size is only kept for projects that run single tenant,
+// the override only for premium tenants.
+func (u *Usecase) resolveProjectInfra(ctx context.Context, project *entity.Project, req dto.CreateProjectRequest) *dto.ErrorResponse {
 	var errRes dto.ErrorResponse
-	projects, err := u.repo.GetTenantProjects(ctx, tenantID)
+
+	if req.InfraMode != "" && req.InfraMode != infra.DefaultMode(u.cfg) {
+		isPremium, err := u.repo.IsPremiumTenant(ctx, project.TenantID)
+		if err != nil {
+			log.Println("Error gagal mendapatkan tenant", err)
+			errRes = dto.ErrorResponse{
+				Status: 500,
+				Error:  "Gagal membuat project",
+			}
+			return &errRes
+		}
+		if !isPremium {
+			errRes = dto.ErrorResponse{
+				Status: 403,
+				Error:  "Mode infra project hanya dapat diubah oleh tenant premium",
+			}
+			return &errRes
+		}
+		project.InfraMode = sql.NullString{Valid: true, String: req.InfraMode}
+	}
+
+	switch infra.ProjectMode(u.cfg, project.InfraMode.String) {
+	case infra.ModeMultiTenant:
+		if req.ResourceSize != "" {
+			errRes = dto.ErrorResponse{
+				Status: 400,
+				Error:  "Ukuran resource hanya berlaku untuk project single_tenant",
+			}
+			return &errRes
+		}
+	case infra.ModeSingleTenant:
+		size := req.ResourceSize
+		if size == "" {
+			size = infra.DefaultResourceSize
+		}
+		if _, ok := infra.Resources(u.cfg, size); !ok {
+			errRes = dto.ErrorResponse{
+				Status: 400,
+				Error:  "Ukuran resource project tidak tersedia",
+			}
+			return &errRes
+		}
+		project.ResourceSize = sql.NullString{Valid: true, String: size}
+	}
+	return nil
+}
+
+func toProjectDTO(project entity.ProjectSummary) dto.Project {
+	isConfigure := project.IsConfigure
+	resp := dto.Project{
+		ID:           project.ID,
+		Name:         project.Name,
+		TenantID:     project.TenantID,
+		Description:  project.Description.String,
+		Tags:         project.Tags.StringArray,
+		Environment:  project.Environment.String,
+		ContactEmail: project.ContactEmail.String,
+		IsConfigure:  &isConfigure,
+	}
+	if project.LastHealthy.Valid {
+		resp.Healthiness = &project.LastHealthy.Bool
+	}
+	return resp
+}
+
+const (
+	maxConcurrentHealthCheck = 10
+	healthCheckTimeout       = 5 * time.Second
+)
+
+type projectHealth struct {
+	healthiness bool
+	err         error
+}
+
+// checkHealthProjects asks the infra manager for the health of every project,
+// a few at a time and each with its own timeout.
+func (u *Usecase) checkHealthProjects(ctx context.Context, projectIDs []string) map[string]projectHealth {
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, maxConcurrentHealthCheck)
+	result := make(map[string]projectHealth, len(projectIDs))
+	for _, id := range projectIDs {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(id string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+			defer cancel()
+			healthiness, err := u.infraRepo.CheckHealthProject(ctx, id)
+			mu.Lock()
+			result[id] = projectHealth{healthiness: healthiness, err: err}
+			mu.Unlock()
+		}(id)
+	}
+	wg.Wait()
+	return result
+}
+
+// healthErrorCategory tells the tenant why a health check failed without
+// exposing the error of the infra manager.
+func healthErrorCategory(err error) string {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return dto.HealthErrorTimeout
+	case errors.Is(err, infra.ErrRejected), errors.Is(err, infra.ErrProjectNotFound):
+		return dto.HealthErrorRejected
+	}
+	return dto.HealthErrorUnavailable
+}
+
+func (u *Usecase) GetListProject(ctx context.Context, req dto.ListProjectRequest, tenantID string) (*dto.PaginationResponse, *dto.ErrorResponse) {
+	var errRes dto.ErrorResponse
+
+	filter := entity.ProjectFilter{
+		TenantID:  tenantID,
+		Search:    req.Search,
+		SortBy:    req.SortBy,
+		SortOrder: req.SortOrder,
+		Limit:     req.PageSize,
+		Offset:    (req.Page - 1) * req.PageSize,
+	}
+	if req.Configured != nil {
+		filter.Configured = sql.NullBool{Valid: true, Bool: *req.Configured}
+	}
+	// Health is the latest check recorded by the health prober, projects it
+	// has not checked yet match neither filter.
+	if req.Health != "" {
+		filter.Healthy = sql.NullBool{Valid: true, Bool: req.Health == "healthy"}
+	}
+
+	projects, total, err := u.repo.GetFilteredTenantProjects(ctx, filter)
 	if err != nil {
 		errRes = dto.ErrorResponse{
 			Status: 500,
@@ -74,17 +248,19 @@ func (u *Usecase) GetListProject(ctx context.Context, tenantID string) (*dto.Lis
 		}
 		return nil, &errRes
 	}
+
 	listProjects := make([]dto.Project, len(projects))
 	for i, project := range projects {
-		listProjects[i] = dto.Project{
-			ID:       project.ID,
-			Name:     project.Name,
-			TenantID: tenantID,
-		}
+		listProjects[i] = toProjectDTO(project)
 	}
-	return &dto.ListProjectResponse{
-		TenantID: tenantID,
-		Projects: listProjects,
+
+	totalPage := (total + req.PageSize - 1) / req.PageSize
+	return &dto.PaginationResponse{
+		PageSize:    req.PageSize,
+		Page:        req.Page,
+		TotalRecord: total,
+		TotalPage:   totalPage,
+		Data:        listProjects,
 	}, nil
 }
 
@@ -98,13 +274,26 @@ func (u *Usecase) GetProjectDetail(ctx context.Context, projectID, tenantID stri
 		}
 		return nil, &errRes
 	}
+	locales, localizedPages := u.configUsecase.LocalizedPages(ctx, projectID, project.QueuePageStyle)
+	provisioningStatus, errProvisioning := u.provisioningUsecase.GetStatus(ctx, projectID)
+	if errProvisioning != nil {
+		return nil, errProvisioning
+	}
 	return &dto.ProjectDetailResponse{
-		ID:       projectID,
-		Name:     project.Name,
-		TenantID: project.TenantID,
+		ID:           projectID,
+		Name:         project.Name,
+		TenantID:     project.TenantID,
+		Description:  project.Description.String,
+		Tags:         project.Tags.StringArray,
+		Environment:  project.Environment.String,
+		ContactEmail: project.ContactEmail.String,
+		InfraMode:    infra.ProjectMode(u.cfg, project.InfraMode.String),
+		ResourceSize: project.ResourceSize.String,
+		Provisioning: *provisioningStatus,
 		Configuration: dto.ProjectConfig{
 			ProjectID:          projectID,
 			Threshold:          project.Threshold,
+			EffectiveThreshold: parser.ParseNullInt(project.EffectiveThreshold, project.Threshold),
 			SessionTime:        project.SessionTime,
 			Host:               project.Host.String,
 			BaseURL:            project.BaseURL.String,
@@ -117,17 +306,89 @@ func (u *Usecase) GetProjectDetail(ctx context.Context, projectID, tenantID stri
 			QueuePageTitle:     project.QueuePageTitle.String,
 			QueuePageLogo:      project.QueuePageLogo.String,
 			IsConfigure:        project.IsConfigure,
+			OperationalState:   project.OperationalState,
+			StateReason:        project.StateReason.String,
+			StateChangedAt:     parser.ParseNullTime(project.StateChangedAt),
+			AutoResumeAt:       parser.ParseNullTime(project.AutoResumeAt),
+			QueueMode:          project.QueueMode,
+			PreQueueOpenAt:     parser.ParseNullTime(project.PreQueueOpenAt),
+			LotterySeed:        project.LotterySeed.String,
+			ActiveThemeID:      project.ActiveThemeID.String,
+			Locales:            locales,
+			LocalizedPages:     localizedPages,
 		},
 	}, nil
 }
 
-func (u *Usecase) CheckHealthProject(ctx context.Context, projectID string) (*dto.CheckHealthProjectResponse, *dto.ErrorResponse) {
+// CheckHealthProjects checks every project of the tenant at once. Projects
+// that are not configured have no deployment and are not checked.
+func (u *Usecase) CheckHealthProjects(ctx context.Context, tenantID string) (*dto.CheckHealthProjectsResponse, *dto.ErrorResponse) {
 	var errRes dto.ErrorResponse
-	client := &http.Client{}
-	healthiness, err := u.infraRepo.CheckHealthProject(client, projectID)
+
+	projects, _, err := u.repo.GetFilteredTenantProjects(ctx, entity.ProjectFilter{TenantID: tenantID})
 	if err != nil {
+		log.Println("Error gagal mendapatkan project", err)
 		errRes = dto.ErrorResponse{
 			Status: 500,
+			Error:  "Gagal mengecek kesehatan project",
+		}
+		return nil, &errRes
+	}
+
+	ids := []string{}
+	for _, project := range projects {
+		if project.IsConfigure {
+			ids = append(ids, project.ID)
+		}
+	}
+	health := u.checkHealthProjects(ctx, ids)
+
+	resp := dto.CheckHealthProjectsResponse{
+		TenantID:  tenantID,
+		Total:     len(projects),
+		CheckedAt: time.Now(),
+		Projects:  make([]dto.ProjectHealthStatus, len(projects)),
+	}
+	for i, project := range projects {
+		item := dto.ProjectHealthStatus{ID: project.ID}
+		result, checked := health[project.ID]
+		switch {
+		case !checked:
+			item.Status = dto.HealthStatusUnconfigured
+			resp.Unconfigured++
+		case result.err != nil:
+			log.Println("Error gagal mengecek kesehatan project", project.ID, result.err)
+			item.Status = dto.HealthStatusError
+			item.Error = healthErrorCategory(result.err)
+			resp.Errors++
+		case result.healthiness:
+			item.Status = dto.HealthStatusHealthy
+			resp.Healthy++
+		default:
+			item.Status = dto.HealthStatusUnhealthy
+			resp.Unhealthy++
+		}
+		if checked && result.err == nil {
+			healthiness := result.healthiness
+			item.Healthiness = &healthiness
+		}
+		resp.Projects[i] = item
+	}
+	return &resp, nil
+}
+
+func (u *Usecase) CheckHealthProject(ctx context.Context, projectID string) (*dto.CheckHealthProjectResponse, *dto.ErrorResponse) {
+	var errRes dto.ErrorResponse
+	healthiness, err := u.infraRepo.CheckHealthProject(ctx, projectID)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, infra.ErrProjectNotFound) {
+			status = http.StatusNotFound
+		} else if errors.Is(err, infra.ErrCircuitOpen) || errors.Is(err, infra.ErrUnavailable) {
+			status = http.StatusServiceUnavailable
+		}
+		errRes = dto.ErrorResponse{
+			Status: status,
 			Error:  err.Error(),
 		}
 		return nil, &errRes
@@ -138,6 +399,108 @@ func (u *Usecase) CheckHealthProject(ctx context.Context, projectID string) (*dt
 	}, nil
 }
 
+// GetProvisioningStatus returns the provisioning of a project of the tenant.
+func (u *Usecase) GetProvisioningStatus(ctx context.Context, projectID, tenantID string) (*dto.ProvisioningStatus, *dto.ErrorResponse) {
+	var errRes dto.ErrorResponse
+	_, err := u.repo.GetTenantProjectByID(ctx, projectID, tenantID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			errRes = dto.ErrorResponse{
+				Status: 404,
+				Error:  "Project dengan id tersebut tidak ditemukan",
+			}
+			return nil, &errRes
+		}
+		errRes = dto.ErrorResponse{
+			Status: 500,
+			Error:  err.Error(),
+		}
+		return nil, &errRes
+	}
+	return u.provisioningUsecase.GetStatus(ctx, projectID)
+}
+
+func (u *Usecase) DeprovisionProject(ctx context.Context, projectID, tenantID string) (*dto.ProvisioningStatus, *dto.ErrorResponse) {
+	_, errRes := u.GetProvisioningStatus(ctx, projectID, tenantID)
+	if errRes != nil {
+		return nil, errRes
+	}
+	errRes = u.provisioningUsecase.Deprovision(ctx, projectID)
+	if errRes != nil {
+		return nil, errRes
+	}
+	u.recordHistory(ctx, projectID, tenantID, "deprovision_project", nil)
+	return u.provisioningUsecase.GetStatus(ctx, projectID)
+}
+
+// DeleteProject removes the deployment of the project, if it has one, and
+// then the project itself.
+func (u *Usecase) DeleteProject(ctx context.Context, projectID, tenantID string) *dto.ErrorResponse {
+	var errRes dto.ErrorResponse
+	project, err := u.repo.GetTenantProjectByID(ctx, projectID, tenantID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			errRes = dto.ErrorResponse{
+				Status: 404,
+				Error:  "Project dengan id tersebut tidak ditemukan",
+			}
+			return &errRes
+		}
+		log.Println("Error gagal mendapatkan project", err)
+		errRes = dto.ErrorResponse{
+			Status: 500,
+			Error:  "Gagal menghapus project",
+		}
+		return &errRes
+	}
+
+	if project.IsConfigure {
+		err = u.infraRepo.DeleteInfraProject(ctx, projectID)
+		var managerErr *infra.ManagerError
+		if err != nil && !(errors.As(err, &managerErr) && managerErr.StatusCode == http.StatusNotFound) {
+			log.Println("Error gagal menghapus deployment project", projectID, err)
+			status := http.StatusInternalServerError
+			if errors.Is(err, infra.ErrCircuitOpen) || errors.Is(err, infra.ErrUnavailable) {
+				status = http.StatusServiceUnavailable
+			}
+			errRes = dto.ErrorResponse{
+				Status: status,
+				Error:  "Gagal menghapus deployment project",
+			}
+			return &errRes
+		}
+	}
+
+	err = u.repo.DeleteProject(ctx, projectID, tenantID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			errRes = dto.ErrorResponse{
+				Status: 404,
+				Error:  "Project dengan id tersebut tidak ditemukan",
+			}
+			return &errRes
+		}
+		log.Println("Error gagal menghapus project", err)
+		errRes = dto.ErrorResponse{
+			Status: 500,
+			Error:  "Gagal menghapus project",
+		}
+		return &errRes
+	}
+
+	u.eventBus.Publish(ctx, dto.ProjectEvent{
+		Type:      dto.EventProjectDeleted,
+		TenantID:  tenantID,
+		ProjectID: projectID,
+		Data: dto.Project{
+			ID:       projectID,
+			Name:     project.Name,
+			TenantID: tenantID,
+		},
+	})
+	return nil
+}
+
 func (u *Usecase) ClearProject(ctx context.Context) *dto.ErrorResponse {
 	var errRes dto.ErrorResponse
 	err := u.repo.ClearAllProjects(ctx)
@@ -150,3 +513,453 @@ func (u *Usecase) ClearProject(ctx context.Context) *dto.ErrorResponse {
 	}
 	return nil
 }
+
+func hasBaseStyle(config entity.Configuration) bool {
+	if config.QueuePageStyle != "base" {
+		return false
+	}
+	return config.QueuePageTitle.String != "" || config.QueuePageBaseColor.String != "" || config.QueuePageLogo.String != ""
+}
+
+// publishProjectPage gives a new project a queue page of its own: the base
+// page is re-rendered under the new id and a custom page is copied.
+func (u *Usecase) publishProjectPage(ctx context.Context, projectID string, config entity.Configuration) *dto.ErrorResponse {
+	if hasBaseStyle(config) {
+		_, errStyle := u.configUsecase.UpdateProjectStyle(ctx, dto.UpdateProjectStyle{
+			ProjectID:          projectID,
+			QueuePageStyle:     config.QueuePageStyle,
+			QueuePageBaseColor: config.QueuePageBaseColor.String,
+			QueuePageTitle:     config.QueuePageTitle.String,
+			QueuePageLogo:      config.QueuePageLogo.String,
+		}, nil, nil)
+		return errStyle
+	}
+	if config.QueuePageStyle == "custom" && config.QueueHTMLPage.String != "" {
+		return u.configUsecase.CopyProjectPage(ctx, projectID, config)
+	}
+	return nil
+}
+
+// createProjectWithConfig inserts the project together with a prefilled
+// configuration row and publishes its queue page under the new id.
+func (u *Usecase) createProjectWithConfig(ctx context.Context, project entity.Project, config entity.Configuration) (*dto.CreateProjectResponse, *dto.ErrorResponse) {
+	var errRes dto.ErrorResponse
+
+	created, err := u.repo.CreateNewProjectWithConfig(ctx, project, config)
+	if err != nil {
+		log.Println("Error gagal membuat project", err)
+		if pgErr, ok := err.(*pq.Error); ok && pgErr.Code == "23505" {
+			errRes = dto.ErrorResponse{
+				Status: 400,
+				Error:  "Project dengan id tersebut sudah ada",
+			}
+			return nil, &errRes
+		}
+		errRes = dto.ErrorResponse{
+			Status: 500,
+			Error:  "Gagal membuat project",
+		}
+		return nil, &errRes
+	}
+
+	errStyle := u.publishProjectPage(ctx, created.ID, config)
+	if errStyle != nil {
+		// The project is already committed, it is removed again so a failed
+		// create leaves nothing behind.
+		if err := u.repo.DeleteProject(ctx, created.ID, created.TenantID); err != nil {
+			log.Println("Error gagal menghapus project", created.ID, err)
+		}
+		return nil, errStyle
+	}
+
+	return u.projectCreated(ctx, *created), nil
+}
+
+func (u *Usecase) registerProjectFromTemplate(ctx context.Context, project entity.Project, templateID string) (*dto.CreateProjectResponse, *dto.ErrorResponse) {
+	var errRes dto.ErrorResponse
+
+	template, err := u.templateRepo.GetTenantTemplateByID(ctx, templateID, project.TenantID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			errRes = dto.ErrorResponse{
+				Status: 404,
+				Error:  "Template dengan id tersebut tidak ditemukan",
+			}
+			return nil, &errRes
+		}
+		log.Println("Error gagal mendapatkan template", err)
+		errRes = dto.ErrorResponse{
+			Status: 500,
+			Error:  "Gagal membuat project",
+		}
+		return nil, &errRes
+	}
+
+	return u.createProjectWithConfig(ctx, project, entity.Configuration{
+		Threshold:          template.Threshold,
+		SessionTime:        template.SessionTime,
+		MaxUsersInQueue:    template.MaxUsersInQueue,
+		QueuePageStyle:     template.QueuePageStyle,
+		QueueHTMLPage:      template.QueueHTMLPage,
+		QueuePageBaseColor: template.QueuePageBaseColor,
+		QueuePageTitle:     template.QueuePageTitle,
+		QueuePageLogo:      template.QueuePageLogo,
+	})
+}
+
+func (u *Usecase) CloneProject(ctx context.Context, sourceID string, req dto.CloneProjectRequest, tenantID string) (*dto.CreateProjectResponse, *dto.ErrorResponse) {
+	var errRes dto.ErrorResponse
+
+	source, err := u.repo.GetTenantProjectByID(ctx, sourceID, tenantID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			errRes = dto.ErrorResponse{
+				Status: 404,
+				Error:  "Project dengan id tersebut tidak ditemukan",
+			}
+			return nil, &errRes
+		}
+		log.Println("Error gagal mendapatkan project", err)
+		errRes = dto.ErrorResponse{
+			Status: 500,
+			Error:  "Gagal menduplikasi project",
+		}
+		return nil, &errRes
+	}
+
+	// The queue window is copied from the source, without one the clone can
+	// not be configured for the given host.
+	if req.Host != "" && !(source.QueueStart.Valid && source.QueueEnd.Valid) {
+		errRes = dto.ErrorResponse{
+			Status: 400,
+			Error:  "Project sumber belum memiliki waktu queue, host tidak dapat diatur",
+		}
+		return nil, &errRes
+	}
+
+	// The clone runs on the same kind of deployment as its source.
+	project := entity.Project{
+		ID:           req.ID,
+		Name:         req.Name,
+		TenantID:     tenantID,
+		InfraMode:    source.InfraMode,
+		ResourceSize: source.ResourceSize,
+		CreatedAt:    time.Now(),
+	}
+
+	// Host and base url identify the origin behind the room, so they are not
+	// copied. The clone stays unconfigured until they are provided.
+	resp, errCreate := u.createProjectWithConfig(ctx, project, entity.Configuration{
+		Threshold:          source.Threshold,
+		SessionTime:        source.SessionTime,
+		MaxUsersInQueue:    source.MaxUsersInQueue,
+		QueueStart:         source.QueueStart,
+		QueueEnd:           source.QueueEnd,
+		QueuePageStyle:     source.QueuePageStyle,
+		QueueHTMLPage:      source.QueueHTMLPage,
+		QueuePageBaseColor: source.QueuePageBaseColor,
+		QueuePageTitle:     source.QueuePageTitle,
+		QueuePageLogo:      source.QueuePageLogo,
+	})
+	if errCreate != nil {
+		return nil, errCreate
+	}
+
+	if req.Host != "" {
+		const layout = "2006-01-02T15:04:05"
+		configReq := dto.UpdateProjectConfig{
+			ProjectID:       req.ID,
+			Threshold:       source.Threshold,
+			SessionTime:     source.SessionTime,
+			Host:            req.Host,
+			BaseURL:         req.BaseURL,
+			MaxUsersInQueue: source.MaxUsersInQueue,
+			QueueStart:      source.QueueStart.Time.Format(layout),
+			QueueEnd:        source.QueueEnd.Time.Format(layout),
+			QueueMode:       source.QueueMode,
+		}
+		if source.PreQueueOpenAt.Valid {
+			configReq.PreQueueOpen = source.PreQueueOpenAt.Time.Format(layout)
+		}
+		errConfig := u.configUsecase.UpdateProjectConfig(ctx, configReq)
+		if errConfig != nil {
+			// A clone that could not be configured is removed again, so the
+			// request can be retried with the same id.
+			if errDelete := u.DeleteProject(ctx, req.ID, tenantID); errDelete != nil {
+				log.Println("Error gagal menghapus project", req.ID, errDelete.Error)
+			}
+			return nil, errConfig
+		}
+	}
+
+	return resp, nil
+}
+
+func toTemplateDTO(template entity.ProjectTemplate) dto.ProjectTemplate {
+	return dto.ProjectTemplate{
+		ID:                 template.ID,
+		TenantID:           template.TenantID,
+		Name:               template.Name,
+		Threshold:          template.Threshold,
+		SessionTime:        template.SessionTime,
+		MaxUsersInQueue:    template.MaxUsersInQueue,
+		QueuePageStyle:     template.QueuePageStyle,
+		QueueHTMLPage:      template.QueueHTMLPage.String,
+		QueuePageBaseColor: template.QueuePageBaseColor.String,
+		QueuePageTitle:     template.QueuePageTitle.String,
+		QueuePageLogo:      template.QueuePageLogo.String,
+	}
+}
+
+func (u *Usecase) CreateProjectTemplate(ctx context.Context, req dto.CreateProjectTemplateRequest, tenantID string) (*dto.ProjectTemplate, *dto.ErrorResponse) {
+	var errRes dto.ErrorResponse
+
+	template := entity.ProjectTemplate{
+		TenantID:           tenantID,
+		Name:               req.Name,
+		Threshold:          req.Threshold,
+		SessionTime:        req.SessionTime,
+		MaxUsersInQueue:    req.MaxUsersInQueue,
+		QueuePageStyle:     req.QueuePageStyle,
+		QueuePageBaseColor: sql.NullString{Valid: req.QueuePageBaseColor != "", String: req.QueuePageBaseColor},
+		QueuePageTitle:     sql.NullString{Valid: req.QueuePageTitle != "", String: req.QueuePageTitle},
+		QueuePageLogo:      sql.NullString{Valid: req.QueuePageLogo != "", String: req.QueuePageLogo},
+		CreatedAt:          time.Now(),
+	}
+
+	if req.ProjectID != "" {
+		source, err := u.repo.GetTenantProjectByID(ctx, req.ProjectID, tenantID)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				errRes = dto.ErrorResponse{
+					Status: 404,
+					Error:  "Project dengan id tersebut tidak ditemukan",
+				}
+				return nil, &errRes
+			}
+			log.Println("Error gagal mendapatkan project", err)
+			errRes = dto.ErrorResponse{
+				Status: 500,
+				Error:  "Gagal membuat template",
+			}
+			return nil, &errRes
+		}
+		template.Threshold = source.Threshold
+		template.SessionTime = source.SessionTime
+		template.MaxUsersInQueue = source.MaxUsersInQueue
+		template.QueuePageStyle = source.QueuePageStyle
+		template.QueueHTMLPage = source.QueueHTMLPage
+		template.QueuePageBaseColor = source.QueuePageBaseColor
+		template.QueuePageTitle = source.QueuePageTitle
+		template.QueuePageLogo = source.QueuePageLogo
+	}
+
+	if template.QueuePageStyle == "" {
+		template.QueuePageStyle = "base"
+	}
+	if template.QueuePageStyle == "custom" && !template.QueueHTMLPage.Valid {
+		errRes = dto.ErrorResponse{
+			Status: 400,
+			Error:  "Template dengan style custom harus dibuat dari project",
+		}
+		return nil, &errRes
+	}
+
+	created, err := u.templateRepo.CreateNewTemplate(ctx, template)
+	if err != nil {
+		log.Println("Error gagal membuat template", err)
+		errRes = dto.ErrorResponse{
+			Status: 500,
+			Error:  "Gagal membuat template",
+		}
+		return nil, &errRes
+	}
+
+	resp := toTemplateDTO(*created)
+	return &resp, nil
+}
+
+func (u *Usecase) GetListProjectTemplate(ctx context.Context, tenantID string) (*dto.ListProjectTemplateResponse, *dto.ErrorResponse) {
+	var errRes dto.ErrorResponse
+	templates, err := u.templateRepo.GetTenantTemplates(ctx, tenantID)
+	if err != nil {
+		errRes = dto.ErrorResponse{
+			Status: 500,
+			Error:  err.Error(),
+		}
+		return nil, &errRes
+	}
+	listTemplates := make([]dto.ProjectTemplate, len(templates))
+	for i, template := range templates {
+		listTemplates[i] = toTemplateDTO(template)
+	}
+	return &dto.ListProjectTemplateResponse{
+		TenantID:  tenantID,
+		Templates: listTemplates,
+	}, nil
+}
+
+func (u *Usecase) DeleteProjectTemplate(ctx context.Context, templateID, tenantID string) *dto.ErrorResponse {
+	var errRes dto.ErrorResponse
+	err := u.templateRepo.DeleteTenantTemplate(ctx, templateID, tenantID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			errRes = dto.ErrorResponse{
+				Status: 404,
+				Error:  "Template dengan id tersebut tidak ditemukan",
+			}
+			return &errRes
+		}
+		errRes = dto.ErrorResponse{
+			Status: 500,
+			Error:  err.Error(),
+		}
+		return &errRes
+	}
+	return nil
+}
+
+func (u *Usecase) recordHistory(ctx context.Context, projectID, actor, action string, detail interface{}) {
+	err := u.historyRepo.RecordProjectHistory(ctx, projectID, actor, action, detail)
+	if err != nil {
+		log.Println("Error gagal mencatat history project", err)
+	}
+}
+
+func appendChange(changes []dto.FieldChange, field, from, to string) []dto.FieldChange {
+	if from == to {
+		return changes
+	}
+	return append(changes, dto.FieldChange{
+		Field: field,
+		From:  from,
+		To:    to,
+	})
+}
+
+func (u *Usecase) UpdateProject(ctx context.Context, projectID string, req dto.UpdateProjectRequest, tenantID string) (*dto.Project, *dto.ErrorResponse) {
+	var errRes dto.ErrorResponse
+
+	current, err := u.repo.GetTenantProjectByID(ctx, projectID, tenantID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			errRes = dto.ErrorResponse{
+				Status: 404,
+				Error:  "Project dengan id tersebut tidak ditemukan",
+			}
+			return nil, &errRes
+		}
+		log.Println("Error gagal mendapatkan project", err)
+		errRes = dto.ErrorResponse{
+			Status: 500,
+			Error:  "Gagal mengupdate project",
+		}
+		return nil, &errRes
+	}
+
+	project := entity.Project{
+		ID:           projectID,
+		TenantID:     current.TenantID,
+		Name:         current.Name,
+		Description:  current.Description,
+		Tags:         current.Tags,
+		Environment:  current.Environment,
+		ContactEmail: current.ContactEmail,
+	}
+	changes := []dto.FieldChange{}
+
+	if req.Name != nil {
+		changes = appendChange(changes, "name", project.Name, *req.Name)
+		project.Name = *req.Name
+	}
+	if req.Description != nil {
+		changes = appendChange(changes, "description", project.Description.String, *req.Description)
+		project.Description = sql.NullString{Valid: *req.Description != "", String: *req.Description}
+	}
+	if req.Tags != nil {
+		changes = appendChange(changes, "tags", strings.Join(project.Tags.StringArray, ","), strings.Join(*req.Tags, ","))
+		project.Tags.StringArray, project.Tags.Valid = *req.Tags, true
+	}
+	if req.Environment != nil {
+		changes = appendChange(changes, "environment", project.Environment.String, *req.Environment)
+		project.Environment = sql.NullString{Valid: *req.Environment != "", String: *req.Environment}
+	}
+	if req.ContactEmail != nil {
+		changes = appendChange(changes, "contact_email", project.ContactEmail.String, *req.ContactEmail)
+		project.ContactEmail = sql.NullString{Valid: *req.ContactEmail != "", String: *req.ContactEmail}
+	}
+
+	if len(changes) > 0 {
+		err = u.repo.UpdateProject(ctx, project)
+		if err != nil {
+			log.Println("Error gagal mengupdate project", err)
+			if err == sql.ErrNoRows {
+				errRes = dto.ErrorResponse{
+					Status: 404,
+					Error:  "Project dengan id tersebut tidak ditemukan",
+				}
+				return nil, &errRes
+			}
+			errRes = dto.ErrorResponse{
+				Status: 500,
+				Error:  "Gagal mengupdate project",
+			}
+			return nil, &errRes
+		}
+		u.recordHistory(ctx, projectID, tenantID, "update_project", changes)
+	}
+
+	return &dto.Project{
+		ID:           project.ID,
+		TenantID:     project.TenantID,
+		Name:         project.Name,
+		Description:  project.Description.String,
+		Tags:         project.Tags.StringArray,
+		Environment:  project.Environment.String,
+		ContactEmail: project.ContactEmail.String,
+	}, nil
+}
+
+func (u *Usecase) GetProjectHistory(ctx context.Context, projectID, tenantID string) (*dto.ListProjectHistoryResponse, *dto.ErrorResponse) {
+	var errRes dto.ErrorResponse
+
+	_, err := u.repo.GetTenantProjectByID(ctx, projectID, tenantID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			errRes = dto.ErrorResponse{
+				Status: 404,
+				Error:  "Project dengan id tersebut tidak ditemukan",
+			}
+			return nil, &errRes
+		}
+		errRes = dto.ErrorResponse{
+			Status: 500,
+			Error:  err.Error(),
+		}
+		return nil, &errRes
+	}
+
+	histories, err := u.historyRepo.GetProjectHistories(ctx, projectID, 100)
+	if err != nil {
+		errRes = dto.ErrorResponse{
+			Status: 500,
+			Error:  err.Error(),
+		}
+		return nil, &errRes
+	}
+	listHistories := make([]dto.ProjectHistory, len(histories))
+	for i, history := range histories {
+		listHistories[i] = dto.ProjectHistory{
+			ID:        history.ID,
+			ProjectID: history.ProjectID,
+			Actor:     history.Actor.String,
+			Action:    history.Action,
+			Detail:    history.Detail,
+			CreatedAt: history.CreatedAt,
+		}
+	}
+	return &dto.ListProjectHistoryResponse{
+		ProjectID: projectID,
+		Histories: listHistories,
+	}, nil
+}
@@ -0,0 +1,335 @@
+package provisioning
+
+import (
+	"antrein/bc-dashboard/internal/repository/event"
+	"antrein/bc-dashboard/internal/repository/infra"
+	"antrein/bc-dashboard/internal/repository/provisioning"
+	"antrein/bc-dashboard/model/config"
+	"antrein/bc-dashboard/model/dto"
+	"antrein/bc-dashboard/model/entity"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// provisioningTimeout is how long a deployment may take to come up or go
+	// away before it is marked failed.
+	provisioningTimeout = 10 * time.Minute
+	probeTimeout        = 5 * time.Second
+	transitionLimit     = 20
+)
+
+// nextStates is the provisioning state machine. Provisioning may be entered
+// again, which restarts the timeout of a deployment that is reconfigured.
+var nextStates = map[string][]string{
+	dto.ProvisioningPending:        {dto.ProvisioningProvisioning},
+	dto.ProvisioningProvisioning:   {dto.ProvisioningProvisioning, dto.ProvisioningReady, dto.ProvisioningFailed, dto.ProvisioningDeprovisioning},
+	dto.ProvisioningReady:          {dto.ProvisioningProvisioning, dto.ProvisioningFailed, dto.ProvisioningDeprovisioning},
+	dto.ProvisioningFailed:         {dto.ProvisioningProvisioning, dto.ProvisioningDeprovisioning},
+	dto.ProvisioningDeprovisioning: {dto.ProvisioningPending, dto.ProvisioningFailed},
+}
+
+var errInvalidTransition = errors.New("invalid provisioning transition")
+
+type Usecase struct {
+	cfg       *config.Config
+	repo      *provisioning.Repository
+	infraRepo infra.InfraManager
+	eventBus  *event.Bus
+
+	mu          sync.Mutex
+	subscribers map[string]map[chan dto.ProvisioningStatus]struct{}
+}
+
+func New(cfg *config.Config, repo *provisioning.Repository, infraRepo infra.InfraManager, eventBus *event.Bus) *Usecase {
+	return &Usecase{
+		cfg:         cfg,
+		repo:        repo,
+		infraRepo:   infraRepo,
+		eventBus:    eventBus,
+		subscribers: map[string]map[chan dto.ProvisioningStatus]struct{}{},
+	}
+}
+
+func canTransition(from, to string) bool {
+	for _, state := range nextStates[from] {
+		if state == to {
+			return true
+		}
+	}
+	return false
+}
+
+// current returns the provisioning of the project, projects that were never
+// provisioned are pending.
+func (u *Usecase) current(ctx context.Context, projectID string) (*entity.ProjectProvisioning, error) {
+	current, err := u.repo.GetProvisioning(ctx, projectID)
+	if err == sql.ErrNoRows {
+		return &entity.ProjectProvisioning{
+			ProjectID: projectID,
+			State:     dto.ProvisioningPending,
+		}, nil
+	}
+	return current, err
+}
+
+// transition moves the project to state and tells the subscribers, a failed
+// deployment also raises an alert. The last error is kept until the
+// deployment is ready or gone again.
+func (u *Usecase) transition(ctx context.Context, projectID, state string, cause error) error {
+	current, err := u.current(ctx, projectID)
+	if err != nil {
+		return err
+	}
+	if !canTransition(current.State, state) {
+		return fmt.Errorf("%w from %s to %s", errInvalidTransition, current.State, state)
+	}
+
+	transition := entity.ProvisioningTransition{
+		ProjectID: projectID,
+		FromState: current.State,
+		ToState:   state,
+		CreatedAt: time.Now(),
+	}
+	lastError := current.LastError
+	if cause != nil {
+		transition.Error = sql.NullString{Valid: true, String: cause.Error()}
+		lastError = transition.Error
+	}
+	if state == dto.ProvisioningReady || state == dto.ProvisioningPending {
+		lastError = sql.NullString{}
+	}
+
+	err = u.repo.Transition(ctx, transition, lastError)
+	if err != nil {
+		return err
+	}
+
+	if state == dto.ProvisioningFailed {
+		u.eventBus.Publish(ctx, dto.ProjectEvent{
+			Type:      dto.EventAlertFired,
+			ProjectID: projectID,
+			Data: dto.Alert{
+				Alert:   dto.AlertProvisioningFailed,
+				Message: transition.Error.String,
+			},
+		})
+	}
+
+	status, err := u.status(ctx, projectID)
+	if err != nil {
+		log.Println("Error gagal mendapatkan status provisioning", projectID, err)
+		return nil
+	}
+	u.publish(*status)
+	return nil
+}
+
+func (u *Usecase) status(ctx context.Context, projectID string) (*dto.ProvisioningStatus, error) {
+	current, err := u.current(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	transitions, err := u.repo.GetTransitions(ctx, projectID, transitionLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	status := dto.ProvisioningStatus{
+		ProjectID:   projectID,
+		State:       current.State,
+		LastError:   current.LastError.String,
+		Transitions: make([]dto.ProvisioningTransition, len(transitions)),
+	}
+	if !current.StateChangedAt.IsZero() {
+		status.StateChangedAt = &current.StateChangedAt
+	}
+	for i, transition := range transitions {
+		status.Transitions[i] = dto.ProvisioningTransition{
+			FromState: transition.FromState,
+			ToState:   transition.ToState,
+			Error:     transition.Error.String,
+			CreatedAt: transition.CreatedAt,
+		}
+	}
+	return &status, nil
+}
+
+// GetStatus returns the provisioning state with its latest transitions. The
+// caller is responsible for checking the project belongs to the tenant.
+func (u *Usecase) GetStatus(ctx context.Context, projectID string) (*dto.ProvisioningStatus, *dto.ErrorResponse) {
+	var errRes dto.ErrorResponse
+	status, err := u.status(ctx, projectID)
+	if err != nil {
+		log.Println("Error gagal mendapatkan status provisioning", projectID, err)
+		errRes = dto.ErrorResponse{
+			Status: 500,
+			Error:  "Gagal mendapatkan status provisioning project",
+		}
+		return nil, &errRes
+	}
+	return status, nil
+}
+
+// StartProvisioning is called right before the deployment is requested from
+// the infra manager.
+func (u *Usecase) StartProvisioning(ctx context.Context, projectID string) *dto.ErrorResponse {
+	var errRes dto.ErrorResponse
+	err := u.transition(ctx, projectID, dto.ProvisioningProvisioning, nil)
+	if err != nil {
+		if errors.Is(err, errInvalidTransition) || err == sql.ErrNoRows {
+			errRes = dto.ErrorResponse{
+				Status: 400,
+				Error:  "Deployment project sedang dihapus, coba lagi setelah selesai",
+			}
+			return &errRes
+		}
+		log.Println("Error gagal mengubah status provisioning", projectID, err)
+		errRes = dto.ErrorResponse{
+			Status: 500,
+			Error:  "Gagal mengubah status provisioning project",
+		}
+		return &errRes
+	}
+	return nil
+}
+
+// FinishProvisioning records the outcome of the infra manager request. A
+// request that went through leaves the project provisioning until the health
+// polling sees the deployment up.
+func (u *Usecase) FinishProvisioning(ctx context.Context, projectID string, cause error) {
+	if cause == nil {
+		return
+	}
+	err := u.transition(ctx, projectID, dto.ProvisioningFailed, cause)
+	if err != nil {
+		log.Println("Error gagal mengubah status provisioning", projectID, err)
+	}
+}
+
+// Deprovision asks the infra manager to remove the deployment of the project.
+func (u *Usecase) Deprovision(ctx context.Context, projectID string) *dto.ErrorResponse {
+	var errRes dto.ErrorResponse
+	err := u.transition(ctx, projectID, dto.ProvisioningDeprovisioning, nil)
+	if err != nil {
+		if errors.Is(err, errInvalidTransition) || err == sql.ErrNoRows {
+			errRes = dto.ErrorResponse{
+				Status: 400,
+				Error:  "Project tidak memiliki deployment untuk dihapus",
+			}
+			return &errRes
+		}
+		log.Println("Error gagal mengubah status provisioning", projectID, err)
+		errRes = dto.ErrorResponse{
+			Status: 500,
+			Error:  "Gagal menghapus deployment project",
+		}
+		return &errRes
+	}
+
+	err = u.infraRepo.DeleteInfraProject(ctx, projectID)
+	if err != nil {
+		log.Println("Error gagal menghapus deployment project", projectID, err)
+		u.FinishProvisioning(ctx, projectID, err)
+		status := http.StatusInternalServerError
+		if errors.Is(err, infra.ErrCircuitOpen) || errors.Is(err, infra.ErrUnavailable) {
+			status = http.StatusServiceUnavailable
+		}
+		errRes = dto.ErrorResponse{
+			Status: status,
+			Error:  "Gagal menghapus deployment project",
+		}
+		return &errRes
+	}
+	return nil
+}
+
+// PollProvisioning checks the health of deployments that are coming up or
+// going away and moves them on once the infra manager agrees.
+func (u *Usecase) PollProvisioning(ctx context.Context) {
+	provisionings, err := u.repo.GetProvisioningsByState(ctx, []string{dto.ProvisioningProvisioning, dto.ProvisioningDeprovisioning})
+	if err != nil {
+		log.Println("Error gagal mendapatkan project yang sedang provisioning", err)
+		return
+	}
+
+	for _, provisioning := range provisionings {
+		u.pollProvisioning(ctx, provisioning)
+	}
+}
+
+func (u *Usecase) pollProvisioning(ctx context.Context, provisioning entity.ProjectProvisioning) {
+	probeCtx, cancel := context.WithTimeout(ctx, probeTimeout)
+	healthiness, err := u.infraRepo.CheckHealthProject(probeCtx, provisioning.ProjectID)
+	cancel()
+
+	timedOut := time.Since(provisioning.StateChangedAt) > provisioningTimeout
+	next, cause := "", error(nil)
+	switch provisioning.State {
+	case dto.ProvisioningProvisioning:
+		switch {
+		case err == nil && healthiness:
+			next = dto.ProvisioningReady
+		case timedOut && err != nil:
+			next, cause = dto.ProvisioningFailed, fmt.Errorf("deployment tidak siap setelah %s: %w", provisioningTimeout, err)
+		case timedOut:
+			next, cause = dto.ProvisioningFailed, fmt.Errorf("deployment tidak sehat setelah %s", provisioningTimeout)
+		}
+	case dto.ProvisioningDeprovisioning:
+		switch {
+		case errors.Is(err, infra.ErrProjectNotFound):
+			next = dto.ProvisioningPending
+		case timedOut:
+			next, cause = dto.ProvisioningFailed, fmt.Errorf("deployment masih berjalan setelah %s", provisioningTimeout)
+		}
+	}
+	if next == "" {
+		return
+	}
+
+	// The state may have moved on since it was listed, that transition wins.
+	err = u.transition(ctx, provisioning.ProjectID, next, cause)
+	if err != nil && err != sql.ErrNoRows && !errors.Is(err, errInvalidTransition) {
+		log.Println("Error gagal mengubah status provisioning", provisioning.ProjectID, err)
+	}
+}
+
+// Subscribe returns the provisioning updates of the project until cancel is
+// called. Updates are dropped for subscribers that do not keep up.
+func (u *Usecase) Subscribe(projectID string) (<-chan dto.ProvisioningStatus, func()) {
+	updates := make(chan dto.ProvisioningStatus, 8)
+
+	u.mu.Lock()
+	if u.subscribers[projectID] == nil {
+		u.subscribers[projectID] = map[chan dto.ProvisioningStatus]struct{}{}
+	}
+	u.subscribers[projectID][updates] = struct{}{}
+	u.mu.Unlock()
+
+	cancel := func() {
+		u.mu.Lock()
+		defer u.mu.Unlock()
+		delete(u.subscribers[projectID], updates)
+		if len(u.subscribers[projectID]) == 0 {
+			delete(u.subscribers, projectID)
+		}
+	}
+	return updates, cancel
+}
+
+func (u *Usecase) publish(status dto.ProvisioningStatus) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	for updates := range u.subscribers[status.ProjectID] {
+		select {
+		case updates <- status:
+		default:
+		}
+	}
+}
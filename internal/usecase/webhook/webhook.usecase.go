@@ -0,0 +1,402 @@
+package webhook
+
+import (
+	"antrein/bc-dashboard/internal/repository/webhook"
+	"antrein/bc-dashboard/internal/utils/generator"
+	"antrein/bc-dashboard/internal/utils/parser"
+	"antrein/bc-dashboard/model/config"
+	"antrein/bc-dashboard/model/dto"
+	"antrein/bc-dashboard/model/entity"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const (
+	// A delivery is attempted maxDeliveryAttempts times, waiting retryBaseDelay
+	// after the first failure and twice as long after every next one, which
+	// spreads the attempts over a bit more than an hour.
+	maxDeliveryAttempts = 8
+	retryBaseDelay      = 30 * time.Second
+	maxRetryDelay       = time.Hour
+
+	deliveryBatch         = 50
+	maxConcurrentDelivery = 10
+	// deliveryLease keeps a claimed delivery from being sent twice, it has to
+	// outlast the send timeout.
+	deliveryLease = 2 * time.Minute
+	deliveryLimit = 100
+)
+
+type Usecase struct {
+	cfg    *config.Config
+	repo   *webhook.Repository
+	sender *webhook.Sender
+}
+
+func New(cfg *config.Config, repo *webhook.Repository, sender *webhook.Sender) *Usecase {
+	return &Usecase{
+		cfg:    cfg,
+		repo:   repo,
+		sender: sender,
+	}
+}
+
+func handleError(status int, message string) *dto.ErrorResponse {
+	return &dto.ErrorResponse{
+		Status: status,
+		Error:  message,
+	}
+}
+
+func toWebhookDTO(webhook entity.Webhook) dto.Webhook {
+	return dto.Webhook{
+		ID:          webhook.ID,
+		TenantID:    webhook.TenantID,
+		URL:         webhook.URL,
+		Events:      webhook.Events,
+		Description: webhook.Description.String,
+		IsActive:    webhook.IsActive,
+		CreatedAt:   webhook.CreatedAt,
+	}
+}
+
+func toDeliveryDTO(delivery entity.WebhookDelivery) dto.WebhookDelivery {
+	resp := dto.WebhookDelivery{
+		ID:            delivery.ID,
+		WebhookID:     delivery.WebhookID,
+		EventID:       delivery.EventID,
+		EventType:     delivery.EventType,
+		Status:        delivery.Status,
+		Attempts:      delivery.Attempts,
+		LastError:     delivery.LastError.String,
+		DeliveredAt:   parser.ParseNullTime(delivery.DeliveredAt),
+		CreatedAt:     delivery.CreatedAt,
+		NextAttemptAt: parser.ParseNullTime(delivery.NextAttemptAt),
+	}
+	if delivery.Status != dto.WebhookDeliveryPending {
+		resp.NextAttemptAt = nil
+	}
+	if delivery.LastStatusCode.Valid {
+		statusCode := int(delivery.LastStatusCode.Int32)
+		resp.LastStatusCode = &statusCode
+	}
+	return resp
+}
+
+func retryDelay(attempts int) time.Duration {
+	delay := retryBaseDelay
+	for i := 1; i < attempts && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, maxRetryDelay)
+}
+
+func (u *Usecase) CreateWebhook(ctx context.Context, req dto.CreateWebhookRequest, tenantID string) (*dto.Webhook, *dto.ErrorResponse) {
+	secret, err := generator.GenerateWebhookSecret()
+	if err != nil {
+		log.Println("Error gagal membuat secret webhook", err)
+		return nil, handleError(http.StatusInternalServerError, "Gagal membuat webhook")
+	}
+
+	webhook := entity.Webhook{
+		TenantID:  tenantID,
+		URL:       req.URL,
+		Secret:    secret,
+		Events:    pq.StringArray(req.Events),
+		IsActive:  true,
+		CreatedAt: time.Now(),
+	}
+	if req.Description != "" {
+		webhook.Description = sql.NullString{Valid: true, String: req.Description}
+	}
+
+	created, err := u.repo.CreateNewWebhook(ctx, webhook)
+	if err != nil {
+		log.Println("Error gagal membuat webhook", err)
+		return nil, handleError(http.StatusInternalServerError, "Gagal membuat webhook")
+	}
+
+	// The secret is only shown when the webhook is created.
+	resp := toWebhookDTO(*created)
+	resp.Secret = secret
+	return &resp, nil
+}
+
+func (u *Usecase) GetListWebhook(ctx context.Context, tenantID string) (*dto.ListWebhookResponse, *dto.ErrorResponse) {
+	webhooks, err := u.repo.GetTenantWebhooks(ctx, tenantID)
+	if err != nil {
+		log.Println("Error gagal mendapatkan webhook", err)
+		return nil, handleError(http.StatusInternalServerError, "Gagal mendapatkan webhook")
+	}
+	listWebhooks := make([]dto.Webhook, len(webhooks))
+	for i, webhook := range webhooks {
+		listWebhooks[i] = toWebhookDTO(webhook)
+	}
+	return &dto.ListWebhookResponse{
+		TenantID: tenantID,
+		Webhooks: listWebhooks,
+	}, nil
+}
+
+func (u *Usecase) getTenantWebhook(ctx context.Context, webhookID, tenantID string) (*entity.Webhook, *dto.ErrorResponse) {
+	webhook, err := u.repo.GetTenantWebhookByID(ctx, webhookID, tenantID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, handleError(http.StatusNotFound, "Webhook tidak ditemukan")
+		}
+		log.Println("Error gagal mendapatkan webhook", err)
+		return nil, handleError(http.StatusInternalServerError, "Gagal mendapatkan webhook")
+	}
+	return webhook, nil
+}
+
+func (u *Usecase) UpdateWebhook(ctx context.Context, webhookID string, req dto.UpdateWebhookRequest, tenantID string) (*dto.Webhook, *dto.ErrorResponse) {
+	webhook, errRes := u.getTenantWebhook(ctx, webhookID, tenantID)
+	if errRes != nil {
+		return nil, errRes
+	}
+
+	if req.URL != nil {
+		webhook.URL = *req.URL
+	}
+	if req.Events != nil {
+		webhook.Events = pq.StringArray(*req.Events)
+	}
+	if req.Description != nil {
+		webhook.Description = sql.NullString{Valid: *req.Description != "", String: *req.Description}
+	}
+	if req.IsActive != nil {
+		webhook.IsActive = *req.IsActive
+	}
+
+	err := u.repo.UpdateWebhook(ctx, *webhook)
+	if err != nil {
+		log.Println("Error gagal mengupdate webhook", err)
+		return nil, handleError(http.StatusInternalServerError, "Gagal mengupdate webhook")
+	}
+
+	resp := toWebhookDTO(*webhook)
+	return &resp, nil
+}
+
+func (u *Usecase) DeleteWebhook(ctx context.Context, webhookID, tenantID string) *dto.ErrorResponse {
+	if _, errRes := u.getTenantWebhook(ctx, webhookID, tenantID); errRes != nil {
+		return errRes
+	}
+	err := u.repo.DeleteWebhook(ctx, webhookID)
+	if err != nil {
+		log.Println("Error gagal menghapus webhook", err)
+		return handleError(http.StatusInternalServerError, "Gagal menghapus webhook")
+	}
+	return nil
+}
+
+// GetListDelivery returns the latest deliveries of the webhook, newest first.
+func (u *Usecase) GetListDelivery(ctx context.Context, webhookID, tenantID string) (*dto.ListWebhookDeliveryResponse, *dto.ErrorResponse) {
+	if _, errRes := u.getTenantWebhook(ctx, webhookID, tenantID); errRes != nil {
+		return nil, errRes
+	}
+
+	deliveries, err := u.repo.GetDeliveries(ctx, webhookID, deliveryLimit)
+	if err != nil {
+		log.Println("Error gagal mendapatkan riwayat pengiriman webhook", err)
+		return nil, handleError(http.StatusInternalServerError, "Gagal mendapatkan riwayat pengiriman webhook")
+	}
+	listDeliveries := make([]dto.WebhookDelivery, len(deliveries))
+	for i, delivery := range deliveries {
+		listDeliveries[i] = toDeliveryDTO(delivery)
+	}
+	return &dto.ListWebhookDeliveryResponse{
+		WebhookID:  webhookID,
+		Deliveries: listDeliveries,
+	}, nil
+}
+
+// Redeliver queues the payload of an earlier delivery again as a new
+// delivery. The event id stays the same, so receivers can tell it apart from
+// a new event.
+func (u *Usecase) Redeliver(ctx context.Context, deliveryID, tenantID string) (*dto.WebhookDelivery, *dto.ErrorResponse) {
+	delivery, err := u.repo.GetTenantDeliveryByID(ctx, deliveryID, tenantID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, handleError(http.StatusNotFound, "Pengiriman webhook tidak ditemukan")
+		}
+		log.Println("Error gagal mendapatkan pengiriman webhook", err)
+		return nil, handleError(http.StatusInternalServerError, "Gagal mengirim ulang webhook")
+	}
+
+	now := time.Now()
+	created, err := u.repo.CreateDelivery(ctx, entity.WebhookDelivery{
+		WebhookID:     delivery.WebhookID,
+		EventID:       delivery.EventID,
+		EventType:     delivery.EventType,
+		Payload:       delivery.Payload,
+		Status:        dto.WebhookDeliveryPending,
+		NextAttemptAt: sql.NullTime{Valid: true, Time: now},
+		CreatedAt:     now,
+	})
+	if err != nil {
+		log.Println("Error gagal mengirim ulang webhook", err)
+		return nil, handleError(http.StatusInternalServerError, "Gagal mengirim ulang webhook")
+	}
+
+	resp := toDeliveryDTO(*created)
+	return &resp, nil
+}
+
+// Ping sends a webhook.ping event right away and returns how it went. A ping
+// is attempted once and recorded like any other delivery.
+func (u *Usecase) Ping(ctx context.Context, webhookID, tenantID string) (*dto.WebhookDelivery, *dto.ErrorResponse) {
+	webhook, errRes := u.getTenantWebhook(ctx, webhookID, tenantID)
+	if errRes != nil {
+		return nil, errRes
+	}
+
+	eventID, err := generator.GenerateEventID()
+	if err != nil {
+		log.Println("Error gagal membuat id event", err)
+		return nil, handleError(http.StatusInternalServerError, "Gagal mengirim ping webhook")
+	}
+	now := time.Now()
+	payload, err := json.Marshal(dto.ProjectEvent{
+		ID:         eventID,
+		Type:       dto.EventWebhookPing,
+		Version:    dto.EventVersion,
+		TenantID:   tenantID,
+		OccurredAt: now,
+	})
+	if err != nil {
+		log.Println("Error gagal membuat payload webhook", err)
+		return nil, handleError(http.StatusInternalServerError, "Gagal mengirim ping webhook")
+	}
+
+	delivery, err := u.repo.CreateDelivery(ctx, entity.WebhookDelivery{
+		WebhookID: webhook.ID,
+		EventID:   eventID,
+		EventType: dto.EventWebhookPing,
+		Payload:   payload,
+		Status:    dto.WebhookDeliveryPending,
+		CreatedAt: now,
+	})
+	if err != nil {
+		log.Println("Error gagal mencatat pengiriman webhook", err)
+		return nil, handleError(http.StatusInternalServerError, "Gagal mengirim ping webhook")
+	}
+
+	attempted := u.attempt(ctx, entity.WebhookTarget{
+		WebhookDelivery: *delivery,
+		URL:             webhook.URL,
+		Secret:          webhook.Secret,
+	}, 1)
+	resp := toDeliveryDTO(attempted)
+	return &resp, nil
+}
+
+// EnqueueEvent is subscribed to every event on the bus and queues a delivery
+// for each active webhook of the tenant that subscribed to the event type.
+// Events that only name their project are matched to its tenant here.
+func (u *Usecase) EnqueueEvent(ctx context.Context, event dto.ProjectEvent) {
+	if event.TenantID == "" {
+		if event.ProjectID == "" {
+			return
+		}
+		tenantID, err := u.repo.GetProjectTenantID(ctx, event.ProjectID)
+		if err != nil {
+			log.Println("Error gagal mendapatkan tenant project", event.ProjectID, err)
+			return
+		}
+		event.TenantID = tenantID
+	}
+
+	webhooks, err := u.repo.GetSubscribedWebhooks(ctx, event.TenantID, event.Type)
+	if err != nil {
+		log.Println("Error gagal mendapatkan webhook", err)
+		return
+	}
+	if len(webhooks) == 0 {
+		return
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		log.Println("Error gagal membuat payload webhook", event.Type, err)
+		return
+	}
+	webhookIDs := make([]string, len(webhooks))
+	for i, webhook := range webhooks {
+		webhookIDs[i] = webhook.ID
+	}
+	err = u.repo.CreateDeliveries(ctx, webhookIDs, event.ID, event.Type, payload, time.Now())
+	if err != nil {
+		log.Println("Error gagal mencatat pengiriman webhook", event.ID, err)
+	}
+}
+
+// DeliverDueWebhooks is run by the worker and sends the deliveries that are
+// due, a few at a time.
+func (u *Usecase) DeliverDueWebhooks(ctx context.Context) {
+	now := time.Now()
+	targets, err := u.repo.ClaimDueDeliveries(ctx, now, now.Add(deliveryLease), deliveryBatch)
+	if err != nil {
+		log.Println("Error gagal mendapatkan pengiriman webhook", err)
+		return
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, maxConcurrentDelivery)
+	for _, target := range targets {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(target entity.WebhookTarget) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			u.attempt(ctx, target, maxDeliveryAttempts)
+		}(target)
+	}
+	wg.Wait()
+}
+
+// attempt sends the delivery once and records the result. A failed delivery
+// is retried with backoff until it has been attempted maxAttempts times.
+func (u *Usecase) attempt(ctx context.Context, target entity.WebhookTarget, maxAttempts int) entity.WebhookDelivery {
+	delivery := target.WebhookDelivery
+	statusCode, err := u.sender.Send(ctx, webhook.Message{
+		DeliveryID: delivery.ID,
+		EventType:  delivery.EventType,
+		Version:    dto.EventVersion,
+		URL:        target.URL,
+		Secret:     target.Secret,
+		Payload:    delivery.Payload,
+	}, time.Now())
+
+	now := time.Now()
+	delivery.Attempts++
+	delivery.LastStatusCode = sql.NullInt32{Valid: statusCode != 0, Int32: int32(statusCode)}
+	delivery.LastError = sql.NullString{}
+	delivery.NextAttemptAt = sql.NullTime{}
+	switch {
+	case err == nil:
+		delivery.Status = dto.WebhookDeliverySucceeded
+		delivery.DeliveredAt = sql.NullTime{Valid: true, Time: now}
+	case delivery.Attempts >= maxAttempts:
+		delivery.Status = dto.WebhookDeliveryFailed
+		delivery.LastError = sql.NullString{Valid: true, String: err.Error()}
+	default:
+		delivery.Status = dto.WebhookDeliveryPending
+		delivery.LastError = sql.NullString{Valid: true, String: err.Error()}
+		delivery.NextAttemptAt = sql.NullTime{Valid: true, Time: now.Add(retryDelay(delivery.Attempts))}
+	}
+
+	err = u.repo.RecordAttempt(ctx, delivery)
+	if err != nil {
+		log.Println("Error gagal mencatat pengiriman webhook", delivery.ID, err)
+	}
+	return delivery
+}
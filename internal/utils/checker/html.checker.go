@@ -0,0 +1,244 @@
+package checker
+
+import (
+	"antrein/bc-dashboard/model/dto"
+	"bytes"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+const DefaultMaxPageSize = 512 << 10
+
+// RequiredPlaceholders are the element ids the queue runtime writes into.
+var RequiredPlaceholders = []string{"countdown", "lastUpdated"}
+
+// defaultOrigin stands in for the page origin when the project has no host
+// yet, relative URLs then resolve to a host that is never on a list.
+const defaultOrigin = "https://queue.invalid/"
+
+type HTMLPolicy struct {
+	// Origin is the URL the page is served from, relative URLs in the page
+	// are resolved against it.
+	Origin           string
+	ScriptAllowlist  []string
+	AllowedFormHosts []string
+	MaxPageSize      int
+	Strip            bool
+}
+
+// CheckHTMLPolicy reports what a custom queue page does against the policy.
+// With Strip set, removable violations are taken out of the returned page,
+// otherwise the page is returned unchanged.
+func CheckHTMLPolicy(content []byte, policy HTMLPolicy) ([]byte, []dto.HTMLViolation) {
+	violations := []dto.HTMLViolation{}
+
+	maxPageSize := policy.MaxPageSize
+	if maxPageSize <= 0 {
+		maxPageSize = DefaultMaxPageSize
+	}
+	if len(content) > maxPageSize {
+		violations = append(violations, dto.HTMLViolation{
+			Rule:   "oversize_page",
+			Detail: fmt.Sprintf("Ukuran halaman %d byte melebihi batas %d byte", len(content), maxPageSize),
+		})
+		return content, violations
+	}
+
+	origin, err := url.Parse(policy.Origin)
+	if err != nil || origin.Hostname() == "" {
+		origin, _ = url.Parse(defaultOrigin)
+	}
+
+	var out bytes.Buffer
+	foundIDs := map[string]bool{}
+	skipScript := false
+	z := html.NewTokenizer(bytes.NewReader(content))
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			if z.Err() != io.EOF {
+				violations = append(violations, dto.HTMLViolation{
+					Rule:   "invalid_html",
+					Detail: z.Err().Error(),
+				})
+			}
+			break
+		}
+		raw := append([]byte{}, z.Raw()...)
+
+		if skipScript {
+			if tt == html.EndTagToken {
+				if name, _ := z.TagName(); string(name) == "script" {
+					skipScript = false
+				}
+			}
+			continue
+		}
+
+		if tt != html.StartTagToken && tt != html.SelfClosingTagToken {
+			out.Write(raw)
+			continue
+		}
+
+		token := z.Token()
+
+		// A base element changes what every relative URL on the page points
+		// to, so it is never allowed and is dropped as a whole.
+		if token.Data == "base" {
+			violations = append(violations, dto.HTMLViolation{
+				Rule:     "base_element",
+				Element:  token.Data,
+				Detail:   "Elemen base tidak diperbolehkan",
+				Stripped: policy.Strip,
+			})
+			if !policy.Strip {
+				out.Write(raw)
+			}
+			continue
+		}
+
+		changed := false
+		attrs := []html.Attribute{}
+		for _, attr := range token.Attr {
+			key := strings.ToLower(attr.Key)
+			if key == "id" {
+				foundIDs[attr.Val] = true
+			}
+
+			var violation *dto.HTMLViolation
+			switch {
+			case strings.HasPrefix(key, "on"):
+				violation = &dto.HTMLViolation{
+					Rule:   "inline_event_handler",
+					Detail: fmt.Sprintf("Atribut %s tidak diperbolehkan", attr.Key),
+				}
+			case (key == "href" || key == "src" || key == "action" || key == "formaction") && isJavascriptURL(attr.Val):
+				violation = &dto.HTMLViolation{
+					Rule:   "javascript_url",
+					Detail: fmt.Sprintf("URL javascript pada atribut %s tidak diperbolehkan", attr.Key),
+				}
+			case token.Data == "script" && key == "src" && !isAllowedHost(attr.Val, origin, policy.ScriptAllowlist):
+				violation = &dto.HTMLViolation{
+					Rule:   "external_script",
+					Detail: fmt.Sprintf("Script dari %s tidak ada di daftar yang diizinkan", attr.Val),
+				}
+			case (token.Data == "form" && key == "action" || key == "formaction") && !isAllowedHost(attr.Val, origin, policy.AllowedFormHosts):
+				violation = &dto.HTMLViolation{
+					Rule:   "offsite_form",
+					Detail: fmt.Sprintf("Form mengirim data ke %s di luar domain project", attr.Val),
+				}
+			}
+			if violation == nil {
+				attrs = append(attrs, attr)
+				continue
+			}
+
+			violation.Element = token.Data
+			violation.Stripped = policy.Strip
+			violations = append(violations, *violation)
+			changed = true
+
+			// A disallowed script is dropped as a whole, other elements only
+			// lose the attribute.
+			if token.Data == "script" && violation.Rule == "external_script" && policy.Strip {
+				skipScript = tt == html.StartTagToken
+				attrs = nil
+				break
+			}
+		}
+
+		switch {
+		case !changed || !policy.Strip:
+			out.Write(raw)
+		case attrs == nil && token.Data == "script":
+		default:
+			token.Attr = attrs
+			out.WriteString(token.String())
+		}
+	}
+
+	for _, id := range RequiredPlaceholders {
+		if !foundIDs[id] {
+			violations = append(violations, dto.HTMLViolation{
+				Rule:   "missing_placeholder",
+				Detail: fmt.Sprintf("Elemen dengan id %s wajib ada", id),
+			})
+		}
+	}
+
+	if !policy.Strip {
+		return content, violations
+	}
+	return out.Bytes(), violations
+}
+
+// HasBlockingViolation reports violations that were not stripped from the page.
+func HasBlockingViolation(violations []dto.HTMLViolation) bool {
+	for _, violation := range violations {
+		if !violation.Stripped {
+			return true
+		}
+	}
+	return false
+}
+
+func isJavascriptURL(value string) bool {
+	cleaned := strings.Map(func(r rune) rune {
+		if r <= ' ' {
+			return -1
+		}
+		return r
+	}, value)
+	return strings.HasPrefix(strings.ToLower(cleaned), "javascript:")
+}
+
+// isAllowedHost resolves the URL against the page origin the way a browser
+// does and accepts it when it stays on the origin or reaches a host on the
+// list. An entry starting with *. also matches every subdomain.
+func isAllowedHost(value string, origin *url.URL, allowlist []string) bool {
+	// Browsers drop tabs and newlines anywhere in a URL and read a backslash
+	// as a slash, so /\evil.com and \\evil.com point to another host.
+	value = strings.Map(func(r rune) rune {
+		if r == '\t' || r == '\n' || r == '\r' {
+			return -1
+		}
+		return r
+	}, strings.TrimSpace(value))
+	if strings.HasPrefix(value, `\`) || strings.HasPrefix(value, `/\`) {
+		return false
+	}
+	value = strings.ReplaceAll(value, `\`, "/")
+
+	ref, err := url.Parse(value)
+	if err != nil || ref.Opaque != "" {
+		return false
+	}
+	u := origin.ResolveReference(ref)
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return false
+	}
+	host := strings.ToLower(u.Hostname())
+	if host == "" {
+		return false
+	}
+	if host == strings.ToLower(origin.Hostname()) {
+		return true
+	}
+	for _, allowed := range allowlist {
+		allowed = strings.ToLower(allowed)
+		if strings.HasPrefix(allowed, "*.") {
+			if strings.HasSuffix(host, allowed[1:]) || host == allowed[2:] {
+				return true
+			}
+			continue
+		}
+		if host == allowed {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,201 @@
+package checker
+
+import (
+	"antrein/bc-dashboard/model/dto"
+	"strings"
+	"testing"
+)
+
+const placeholders = `<p id="countdown"></p><p id="lastUpdated"></p>`
+
+func testPolicy(strip bool) HTMLPolicy {
+	return HTMLPolicy{
+		Origin:           "https://konser.antrein.test",
+		ScriptAllowlist:  []string{"cdn.jsdelivr.net", "*.cdn.example.com"},
+		AllowedFormHosts: []string{"konser.antrein.test"},
+		MaxPageSize:      1024,
+		Strip:            strip,
+	}
+}
+
+func rules(violations []dto.HTMLViolation) []string {
+	got := []string{}
+	for _, violation := range violations {
+		got = append(got, violation.Rule)
+	}
+	return got
+}
+
+func TestCheckHTMLPolicy(t *testing.T) {
+	tests := []struct {
+		name string
+		page string
+		want []string
+	}{
+		{
+			name: "clean page",
+			page: `<script src="https://cdn.jsdelivr.net/x.js"></script><form action="/join"></form>` + placeholders,
+			want: []string{},
+		},
+		{
+			name: "relative script",
+			page: `<script src="/static/x.js"></script>` + placeholders,
+			want: []string{},
+		},
+		{
+			name: "subdomain script",
+			page: `<script src="https://assets.cdn.example.com/x.js"></script>` + placeholders,
+			want: []string{},
+		},
+		{
+			name: "external script",
+			page: `<script src="https://evil.com/x.js"></script>` + placeholders,
+			want: []string{"external_script"},
+		},
+		{
+			name: "protocol relative script",
+			page: `<script src="//evil.com/x.js"></script>` + placeholders,
+			want: []string{"external_script"},
+		},
+		{
+			name: "slash backslash script",
+			page: `<script src="/\evil.com/x.js"></script>` + placeholders,
+			want: []string{"external_script"},
+		},
+		{
+			name: "double backslash script",
+			page: `<script src="\\evil.com/x.js"></script>` + placeholders,
+			want: []string{"external_script"},
+		},
+		{
+			name: "backslash after scheme",
+			page: `<script src="https:\\evil.com/x.js"></script>` + placeholders,
+			want: []string{"external_script"},
+		},
+		{
+			name: "scheme without slashes",
+			page: `<script src="https:evil.com/x.js"></script>` + placeholders,
+			want: []string{"external_script"},
+		},
+		{
+			name: "tab in protocol relative script",
+			page: "<script src=\"/\t/evil.com/x.js\"></script>" + placeholders,
+			want: []string{"external_script"},
+		},
+		{
+			name: "data script",
+			page: `<script src="data:text/javascript,alert(1)"></script>` + placeholders,
+			want: []string{"external_script"},
+		},
+		{
+			name: "suffix is not a subdomain",
+			page: `<script src="https://evilcdn.jsdelivr.net.evil.com/x.js"></script>` + placeholders,
+			want: []string{"external_script"},
+		},
+		{
+			name: "inline handler",
+			page: `<body onload="alert(1)">` + placeholders + `</body>`,
+			want: []string{"inline_event_handler"},
+		},
+		{
+			name: "javascript url",
+			page: `<a href=" JaVaScRiPt:alert(1)">x</a>` + placeholders,
+			want: []string{"javascript_url"},
+		},
+		{
+			name: "javascript url with tab",
+			page: "<a href=\"java\tscript:alert(1)\">x</a>" + placeholders,
+			want: []string{"javascript_url"},
+		},
+		{
+			name: "off-domain form",
+			page: `<form action="https://evil.com/steal"></form>` + placeholders,
+			want: []string{"offsite_form"},
+		},
+		{
+			name: "backslash form",
+			page: `<form action="/\evil.com/steal"></form>` + placeholders,
+			want: []string{"offsite_form"},
+		},
+		{
+			name: "off-domain formaction",
+			page: `<form action="/join"><button formaction="https://evil.com/steal">x</button></form>` + placeholders,
+			want: []string{"offsite_form"},
+		},
+		{
+			name: "base element",
+			page: `<base href="https://evil.com/"><script src="/x.js"></script>` + placeholders,
+			want: []string{"base_element"},
+		},
+		{
+			name: "missing placeholder",
+			page: `<p id="countdown"></p>`,
+			want: []string{"missing_placeholder"},
+		},
+		{
+			name: "oversize page",
+			page: placeholders + strings.Repeat("a", 1024),
+			want: []string{"oversize_page"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, violations := CheckHTMLPolicy([]byte(tt.page), testPolicy(false))
+			got := rules(violations)
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("CheckHTMLPolicy() rules = %v, want %v", got, tt.want)
+			}
+			if len(violations) > 0 && !HasBlockingViolation(violations) {
+				t.Error("HasBlockingViolation() = false for a strict policy")
+			}
+		})
+	}
+}
+
+func TestCheckHTMLPolicyStrip(t *testing.T) {
+	tests := []struct {
+		name    string
+		page    string
+		removed []string
+		kept    []string
+	}{
+		{
+			name:    "external script",
+			page:    `<script src="https://evil.com/x.js">alert(1)</script><p>ok</p>`,
+			removed: []string{"evil.com", "alert(1)", "<script"},
+			kept:    []string{"<p>ok</p>"},
+		},
+		{
+			name:    "inline handler",
+			page:    `<img src="/logo.png" onerror="alert(1)">`,
+			removed: []string{"onerror"},
+			kept:    []string{`src="/logo.png"`},
+		},
+		{
+			name:    "base element",
+			page:    `<base href="https://evil.com/"><a href="/x">x</a>`,
+			removed: []string{"<base", "evil.com"},
+			kept:    []string{`<a href="/x">`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, violations := CheckHTMLPolicy([]byte(tt.page+placeholders), testPolicy(true))
+			if HasBlockingViolation(violations) {
+				t.Errorf("HasBlockingViolation() = true, violations %v", rules(violations))
+			}
+			for _, s := range tt.removed {
+				if strings.Contains(string(out), s) {
+					t.Errorf("CheckHTMLPolicy() page still contains %q:\n%s", s, out)
+				}
+			}
+			for _, s := range tt.kept {
+				if !strings.Contains(string(out), s) {
+					t.Errorf("CheckHTMLPolicy() page lost %q:\n%s", s, out)
+				}
+			}
+		})
+	}
+}
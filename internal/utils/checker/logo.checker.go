@@ -0,0 +1,297 @@
+package checker
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+const (
+	DefaultMaxLogoSize      = 2 << 20
+	DefaultMaxLogoDimension = 512
+
+	// maxLogoPixels guards the decoder against images that are small on disk
+	// but huge once decoded, a 16-bit RGBA image at the limit still takes
+	// 32MB.
+	maxLogoPixels = 4_000_000
+)
+
+const (
+	LogoRuleFileSize    = "file_size"
+	LogoRuleContentType = "content_type"
+	LogoRuleImageData   = "image_data"
+	LogoRuleDimensions  = "dimensions"
+	LogoRuleSVGMarkup   = "svg_markup"
+)
+
+// LogoError names the constraint an uploaded logo was rejected for.
+type LogoError struct {
+	Rule   string
+	Detail string
+}
+
+func (e *LogoError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Rule, e.Detail)
+}
+
+type LogoPolicy struct {
+	MaxFileSize  int
+	MaxDimension int
+}
+
+// Logo is an uploaded logo that is safe to publish. Hash is the hex SHA-256
+// of Content, which is the processed file and not the upload.
+type Logo struct {
+	Content     []byte
+	ContentType string
+	Extension   string
+	Hash        string
+}
+
+// CheckLogo sniffs the upload and only accepts PNG, JPEG, WebP and SVG.
+// Raster images are decoded, scaled down to MaxDimension and re-encoded,
+// which also drops any metadata. WebP is re-encoded as PNG. SVGs are
+// sanitized instead.
+func CheckLogo(content []byte, policy LogoPolicy) (*Logo, error) {
+	maxFileSize := policy.MaxFileSize
+	if maxFileSize <= 0 {
+		maxFileSize = DefaultMaxLogoSize
+	}
+	maxDimension := policy.MaxDimension
+	if maxDimension <= 0 {
+		maxDimension = DefaultMaxLogoDimension
+	}
+	if len(content) > maxFileSize {
+		return nil, &LogoError{
+			Rule:   LogoRuleFileSize,
+			Detail: fmt.Sprintf("Ukuran logo %d byte melebihi batas %d byte", len(content), maxFileSize),
+		}
+	}
+
+	var logo *Logo
+	var err error
+	switch contentType := http.DetectContentType(content); contentType {
+	case "image/png", "image/jpeg", "image/webp":
+		logo, err = processRasterLogo(content, contentType, maxDimension)
+	default:
+		if !looksLikeSVG(content) {
+			return nil, &LogoError{
+				Rule:   LogoRuleContentType,
+				Detail: fmt.Sprintf("Tipe file %s tidak didukung, gunakan PNG, JPEG, WebP atau SVG", contentType),
+			}
+		}
+		logo, err = sanitizeSVGLogo(content)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sum := sha256.Sum256(logo.Content)
+	logo.Hash = hex.EncodeToString(sum[:])
+	return logo, nil
+}
+
+func processRasterLogo(content []byte, contentType string, maxDimension int) (*Logo, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(content))
+	if err != nil {
+		return nil, &LogoError{Rule: LogoRuleImageData, Detail: "Gambar logo tidak dapat dibaca"}
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > maxLogoPixels {
+		return nil, &LogoError{
+			Rule:   LogoRuleDimensions,
+			Detail: fmt.Sprintf("Dimensi logo %dx%d tidak didukung", cfg.Width, cfg.Height),
+		}
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(content))
+	if err != nil {
+		return nil, &LogoError{Rule: LogoRuleImageData, Detail: "Gambar logo tidak dapat dibaca"}
+	}
+
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width > maxDimension || height > maxDimension {
+		if width >= height {
+			height = max(1, height*maxDimension/width)
+			width = maxDimension
+		} else {
+			width = max(1, width*maxDimension/height)
+			height = maxDimension
+		}
+		scaled := image.NewRGBA(image.Rect(0, 0, width, height))
+		draw.CatmullRom.Scale(scaled, scaled.Bounds(), img, bounds, draw.Over, nil)
+		img = scaled
+	}
+
+	var buf bytes.Buffer
+	logo := &Logo{}
+	if contentType == "image/jpeg" {
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: 85})
+		logo.ContentType, logo.Extension = "image/jpeg", ".jpg"
+	} else {
+		err = png.Encode(&buf, img)
+		logo.ContentType, logo.Extension = "image/png", ".png"
+	}
+	if err != nil {
+		return nil, err
+	}
+	logo.Content = buf.Bytes()
+	return logo, nil
+}
+
+func looksLikeSVG(content []byte) bool {
+	d := xml.NewDecoder(bytes.NewReader(content))
+	for {
+		token, err := d.RawToken()
+		if err != nil {
+			return false
+		}
+		if start, ok := token.(xml.StartElement); ok {
+			return start.Name.Local == "svg"
+		}
+	}
+}
+
+var (
+	// svgDroppedElements are removed together with everything inside them.
+	svgDroppedElements = map[string]bool{
+		"script":        true,
+		"foreignobject": true,
+		"iframe":        true,
+		"embed":         true,
+		"object":        true,
+		"audio":         true,
+		"video":         true,
+	}
+
+	// urlSchemeRegex finds a scheme in a value or in any entry of a
+	// semicolon separated animation value list.
+	urlSchemeRegex = regexp.MustCompile(`(?i)(?:^|;)[a-z][a-z0-9+.-]*:`)
+
+	unsafeCSSRegex      = regexp.MustCompile(`(?i)@import|expression\s*\(|javascript:|url\(\s*['"]?\s*(?:[a-z][a-z0-9+.-]*:|//)`)
+	safeSVGDataURIRegex = regexp.MustCompile(`(?i)^data:image/(?:png|jpeg|gif|webp);base64,`)
+)
+
+// sanitizeSVGLogo rewrites the SVG from its tokens, keeping only markup that
+// cannot run script or load anything from another origin. Comments,
+// processing instructions and doctypes are dropped.
+func sanitizeSVGLogo(content []byte) (*Logo, error) {
+	d := xml.NewDecoder(bytes.NewReader(content))
+	d.Strict = true
+
+	var out bytes.Buffer
+	depth := 0
+	skipDepth := 0
+	inStyle := false
+	for {
+		token, err := d.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, &LogoError{Rule: LogoRuleSVGMarkup, Detail: "SVG tidak valid: " + err.Error()}
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			depth++
+			if skipDepth > 0 {
+				continue
+			}
+			name := strings.ToLower(t.Name.Local)
+			if depth == 1 && name != "svg" {
+				return nil, &LogoError{Rule: LogoRuleSVGMarkup, Detail: "Elemen utama SVG harus svg"}
+			}
+			if svgDroppedElements[name] {
+				skipDepth = depth
+				continue
+			}
+			inStyle = name == "style"
+			out.WriteString("<" + qualifiedName(t.Name))
+			for _, attr := range t.Attr {
+				if !isSafeSVGAttr(attr) {
+					continue
+				}
+				out.WriteString(" " + qualifiedName(attr.Name) + `="`)
+				xml.EscapeText(&out, []byte(attr.Value))
+				out.WriteString(`"`)
+			}
+			out.WriteString(">")
+		case xml.EndElement:
+			depth--
+			if skipDepth > 0 {
+				if depth < skipDepth {
+					skipDepth = 0
+				}
+				continue
+			}
+			inStyle = false
+			out.WriteString("</" + qualifiedName(t.Name) + ">")
+		case xml.CharData:
+			if skipDepth > 0 || depth == 0 {
+				continue
+			}
+			if inStyle && unsafeCSSRegex.Match(t) {
+				continue
+			}
+			xml.EscapeText(&out, t)
+		}
+	}
+	if out.Len() == 0 {
+		return nil, &LogoError{Rule: LogoRuleSVGMarkup, Detail: "SVG tidak memiliki isi"}
+	}
+
+	return &Logo{
+		Content:     out.Bytes(),
+		ContentType: "image/svg+xml",
+		Extension:   ".svg",
+	}, nil
+}
+
+func isSafeSVGAttr(attr xml.Attr) bool {
+	key := strings.ToLower(attr.Name.Local)
+	value := strings.TrimSpace(attr.Value)
+	switch {
+	case strings.HasPrefix(key, "on"):
+		return false
+	case key == "href" || key == "src":
+		return strings.HasPrefix(value, "#") || safeSVGDataURIRegex.MatchString(value)
+	case key == "to" || key == "from" || key == "by" || key == "values":
+		// Animation elements set another attribute while the image is shown,
+		// so a value carrying a URL is refused whatever it animates.
+		if urlSchemeRegex.MatchString(stripControl(value)) {
+			return false
+		}
+	}
+	return !unsafeCSSRegex.MatchString(value)
+}
+
+func qualifiedName(name xml.Name) string {
+	if name.Space == "" {
+		return name.Local
+	}
+	return name.Space + ":" + name.Local
+}
+
+// stripControl drops whitespace and control characters, which browsers skip
+// when they read a URL scheme.
+func stripControl(value string) string {
+	return strings.Map(func(r rune) rune {
+		if r <= ' ' {
+			return -1
+		}
+		return r
+	}, value)
+}
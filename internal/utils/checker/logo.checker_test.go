@@ -0,0 +1,223 @@
+package checker
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+func encodePNG(t *testing.T, width, height int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, width, height))); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// pngHeader returns a small PNG whose header claims the given dimensions, the
+// pixel data does not match it.
+func pngHeader(t *testing.T, width, height uint32) []byte {
+	t.Helper()
+	content := encodePNG(t, 1, 1)
+	// The IHDR chunk follows the 8 byte signature, its data starts after the
+	// length and type and its CRC covers type and data.
+	binary.BigEndian.PutUint32(content[16:], width)
+	binary.BigEndian.PutUint32(content[20:], height)
+	binary.BigEndian.PutUint32(content[29:], crc32.ChecksumIEEE(content[12:29]))
+	return content
+}
+
+func logoRule(err error) string {
+	var logoErr *LogoError
+	if errors.As(err, &logoErr) {
+		return logoErr.Rule
+	}
+	return ""
+}
+
+func TestCheckLogoRaster(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  []byte
+		wantRule string
+		wantSize image.Point
+	}{
+		{
+			name:     "small image is kept",
+			content:  encodePNG(t, 64, 32),
+			wantSize: image.Pt(64, 32),
+		},
+		{
+			name:     "large image is scaled down",
+			content:  encodePNG(t, 1024, 256),
+			wantSize: image.Pt(512, 128),
+		},
+		{
+			name:     "decompression bomb",
+			content:  pngHeader(t, 20000, 20000),
+			wantRule: LogoRuleDimensions,
+		},
+		{
+			name:     "just over the pixel limit",
+			content:  pngHeader(t, 2001, 2000),
+			wantRule: LogoRuleDimensions,
+		},
+		{
+			name:     "not an image",
+			content:  []byte("GIF89a not really"),
+			wantRule: LogoRuleContentType,
+		},
+		{
+			name:     "oversize file",
+			content:  make([]byte, DefaultMaxLogoSize+1),
+			wantRule: LogoRuleFileSize,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logo, err := CheckLogo(tt.content, LogoPolicy{})
+			if tt.wantRule != "" {
+				if logoRule(err) != tt.wantRule {
+					t.Fatalf("CheckLogo() error = %v, want rule %s", err, tt.wantRule)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CheckLogo() error = %v", err)
+			}
+			cfg, err := png.DecodeConfig(bytes.NewReader(logo.Content))
+			if err != nil {
+				t.Fatalf("CheckLogo() returned an unreadable PNG: %v", err)
+			}
+			if got := image.Pt(cfg.Width, cfg.Height); got != tt.wantSize {
+				t.Errorf("CheckLogo() size = %v, want %v", got, tt.wantSize)
+			}
+		})
+	}
+}
+
+func TestCheckLogoSVG(t *testing.T) {
+	tests := []struct {
+		name    string
+		svg     string
+		removed []string
+		kept    []string
+	}{
+		{
+			name:    "script",
+			svg:     `<svg xmlns="http://www.w3.org/2000/svg"><script>alert(1)</script><circle r="4"/></svg>`,
+			removed: []string{"script", "alert"},
+			kept:    []string{`<circle r="4">`},
+		},
+		{
+			name:    "foreign object",
+			svg:     `<svg xmlns="http://www.w3.org/2000/svg"><foreignObject><body xmlns="http://www.w3.org/1999/xhtml"><img src="x"/></body></foreignObject></svg>`,
+			removed: []string{"foreignObject", "body", "img"},
+		},
+		{
+			name:    "event handler",
+			svg:     `<svg xmlns="http://www.w3.org/2000/svg" onload="alert(1)"><rect ONCLICK="alert(2)" width="4"/></svg>`,
+			removed: []string{"onload", "ONCLICK", "alert"},
+			kept:    []string{`width="4"`},
+		},
+		{
+			name:    "javascript href",
+			svg:     `<svg xmlns="http://www.w3.org/2000/svg"><a href="javascript:alert(1)"><text>x</text></a></svg>`,
+			removed: []string{"javascript"},
+			kept:    []string{"<text>x</text>"},
+		},
+		{
+			name:    "data xlink href",
+			svg:     `<svg xmlns="http://www.w3.org/2000/svg" xmlns:xlink="http://www.w3.org/1999/xlink"><a xlink:href="data:text/html,&lt;script&gt;alert(1)&lt;/script&gt;"><text>x</text></a></svg>`,
+			removed: []string{"data:", "alert"},
+		},
+		{
+			name:    "remote image",
+			svg:     `<svg xmlns="http://www.w3.org/2000/svg"><image href="https://evil.com/track.png"/></svg>`,
+			removed: []string{"evil.com"},
+		},
+		{
+			name: "local references",
+			svg:  `<svg xmlns="http://www.w3.org/2000/svg"><use href="#dot"/><image href="data:image/png;base64,iVBORw0KGgo="/></svg>`,
+			kept: []string{`href="#dot"`, `href="data:image/png;base64,iVBORw0KGgo="`},
+		},
+		{
+			name:    "url in style attribute",
+			svg:     `<svg xmlns="http://www.w3.org/2000/svg"><rect style="fill: url(https://evil.com/x)" width="4"/></svg>`,
+			removed: []string{"evil.com"},
+			kept:    []string{`width="4"`},
+		},
+		{
+			name:    "url in style element",
+			svg:     `<svg xmlns="http://www.w3.org/2000/svg"><style>rect { fill: url('//evil.com/x') }</style></svg>`,
+			removed: []string{"evil.com"},
+		},
+		{
+			name:    "set to javascript",
+			svg:     `<svg xmlns="http://www.w3.org/2000/svg"><a><set attributeName="href" to="javascript:alert(1)"/><text>x</text></a></svg>`,
+			removed: []string{"javascript"},
+		},
+		{
+			name:    "set to data",
+			svg:     `<svg xmlns="http://www.w3.org/2000/svg"><a><set attributeName="href" to="data:text/html,x"/><text>x</text></a></svg>`,
+			removed: []string{"data:"},
+		},
+		{
+			name:    "animate values with split scheme",
+			svg:     "<svg xmlns=\"http://www.w3.org/2000/svg\"><a><animate attributeName=\"href\" values=\"#a; java\tscript:alert(1)\"/></a></svg>",
+			removed: []string{"script:"},
+		},
+		{
+			name:    "animate url value",
+			svg:     `<svg xmlns="http://www.w3.org/2000/svg"><rect><animate attributeName="fill" to="url(https://evil.com/x)"/></rect></svg>`,
+			removed: []string{"evil.com"},
+		},
+		{
+			name:    "doctype and entities",
+			svg:     `<!DOCTYPE svg [<!ENTITY x "y">]><svg xmlns="http://www.w3.org/2000/svg"><!-- note --><rect/></svg>`,
+			removed: []string{"DOCTYPE", "ENTITY", "note"},
+		},
+		{
+			name: "plain animation",
+			svg:  `<svg xmlns="http://www.w3.org/2000/svg"><rect><animate attributeName="opacity" values="0;1;0" dur="2s"/></rect></svg>`,
+			kept: []string{`values="0;1;0"`, `dur="2s"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logo, err := CheckLogo([]byte(tt.svg), LogoPolicy{})
+			if err != nil {
+				t.Fatalf("CheckLogo() error = %v", err)
+			}
+			if logo.ContentType != "image/svg+xml" {
+				t.Errorf("CheckLogo() content type = %s, want image/svg+xml", logo.ContentType)
+			}
+			got := string(logo.Content)
+			for _, s := range tt.removed {
+				if strings.Contains(got, s) {
+					t.Errorf("CheckLogo() kept %q:\n%s", s, got)
+				}
+			}
+			for _, s := range tt.kept {
+				if !strings.Contains(got, s) {
+					t.Errorf("CheckLogo() lost %q:\n%s", s, got)
+				}
+			}
+		})
+	}
+}
+
+func TestCheckLogoRejectsInvalidSVG(t *testing.T) {
+	svg := `<svg xmlns="http://www.w3.org/2000/svg"><rect width="4></svg>`
+	if _, err := CheckLogo([]byte(svg), LogoPolicy{}); logoRule(err) != LogoRuleSVGMarkup {
+		t.Errorf("CheckLogo() error = %v, want rule %s", err, LogoRuleSVGMarkup)
+	}
+}
@@ -2,7 +2,13 @@ package generator
 
 import (
 	"antrein/bc-dashboard/model/entity"
+	"crypto/hmac"
+	crand "crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"math/rand"
+	"strings"
 
 	"github.com/golang-jwt/jwt/v5"
 )
@@ -20,3 +26,57 @@ func GenerateRandomString(lenStr int) string {
 	}
 	return string(b)
 }
+
+func signCode(key, scope, nonce string) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(scope + "." + nonce))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))[:16]
+}
+
+// GenerateSignedCode returns a random code bound to scope by an HMAC, in the
+// form <nonce>.<signature>.
+func GenerateSignedCode(key, scope string) (string, error) {
+	b := make([]byte, 18)
+	if _, err := crand.Read(b); err != nil {
+		return "", err
+	}
+	nonce := base64.RawURLEncoding.EncodeToString(b)
+	return nonce + "." + signCode(key, scope, nonce), nil
+}
+
+func VerifySignedCode(key, scope, code string) bool {
+	nonce, signature, found := strings.Cut(code, ".")
+	if !found {
+		return false
+	}
+	return hmac.Equal([]byte(signature), []byte(signCode(key, scope, nonce)))
+}
+
+func HashString(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
+
+func GenerateEventID() (string, error) {
+	b := make([]byte, 12)
+	if _, err := crand.Read(b); err != nil {
+		return "", err
+	}
+	return "evt_" + hex.EncodeToString(b), nil
+}
+
+func GenerateSeed() (string, error) {
+	b := make([]byte, 16)
+	if _, err := crand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func GenerateWebhookSecret() (string, error) {
+	b := make([]byte, 24)
+	if _, err := crand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
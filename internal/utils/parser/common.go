@@ -1,11 +1,32 @@
 package parser
 
-import "strings"
+import (
+	"database/sql"
+	"strings"
+	"time"
+)
 
 func ParseStringArray(arrayStr string) ([]string, error) {
 	trimmed := strings.Trim(arrayStr, "{}")
+	if trimmed == "" {
+		return []string{}, nil
+	}
 
 	items := strings.Split(trimmed, ",")
 
 	return items, nil
 }
+
+func ParseNullTime(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
+
+func ParseNullInt(v sql.NullInt32, fallback int) int {
+	if !v.Valid {
+		return fallback
+	}
+	return int(v.Int32)
+}
@@ -0,0 +1,135 @@
+package renderer
+
+import (
+	"antrein/bc-dashboard/internal/utils/validator"
+	"antrein/bc-dashboard/model/dto"
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"html/template"
+	"net/http"
+	"strings"
+)
+
+const (
+	QueuePageTemplate = "./files/templates/queue.html"
+	DefaultBaseColor  = "#f1f1f1"
+	DefaultLocale     = "en"
+)
+
+// DefaultTexts are the built-in strings of the base queue page per language.
+var DefaultTexts = map[string]dto.QueuePageText{
+	"en": {
+		PageTitle:          "Waiting Room",
+		WaitingMessage:     "Please wait a moment you are in queue.",
+		EstimatedTimeLabel: "Estimated time remaining:",
+		CalculatingLabel:   "calculating...",
+		LastUpdatedLabel:   "Last updated:",
+		LogoAlt:            "Logo",
+	},
+	"id": {
+		PageTitle:          "Ruang Tunggu",
+		WaitingMessage:     "Mohon tunggu sebentar, Anda sedang dalam antrian.",
+		EstimatedTimeLabel: "Perkiraan waktu tersisa:",
+		CalculatingLabel:   "menghitung...",
+		LastUpdatedLabel:   "Terakhir diperbarui:",
+		LogoAlt:            "Logo",
+	},
+}
+
+var (
+	ErrInvalidBaseColor = errors.New("Warna dasar tidak valid")
+	ErrInvalidLogo      = errors.New("File logo harus berupa gambar")
+)
+
+// QueuePageData is everything the base queue page template can show. Text is
+// completed from the built-in strings of Locale. An inline logo is embedded
+// as a data URI instead of LogoURL, which is used by previews that must not
+// upload anything. InlineLogoType is sniffed when it is empty.
+type QueuePageData struct {
+	Title          string
+	LogoURL        string
+	InlineLogo     []byte
+	InlineLogoType string
+	BaseColor      string
+	Locale         string
+	Text           dto.QueuePageText
+}
+
+// LocalizedText fills the empty fields of text with the built-in strings of
+// the locale language, and with English when the language is unknown.
+func LocalizedText(locale string, text dto.QueuePageText) dto.QueuePageText {
+	language := strings.ToLower(strings.SplitN(locale, "-", 2)[0])
+	defaults, ok := DefaultTexts[language]
+	if !ok {
+		defaults = DefaultTexts[DefaultLocale]
+	}
+	fill := func(value *string, fallback string) {
+		if *value == "" {
+			*value = fallback
+		}
+	}
+	fill(&text.PageTitle, defaults.PageTitle)
+	fill(&text.WaitingMessage, defaults.WaitingMessage)
+	fill(&text.EstimatedTimeLabel, defaults.EstimatedTimeLabel)
+	fill(&text.CalculatingLabel, defaults.CalculatingLabel)
+	fill(&text.LastUpdatedLabel, defaults.LastUpdatedLabel)
+	fill(&text.LogoAlt, defaults.LogoAlt)
+	return text
+}
+
+// RenderQueuePage renders the base queue page. The title and logo are escaped
+// by html/template, the base color is only written after it is validated as
+// a CSS color.
+func RenderQueuePage(data QueuePageData) ([]byte, error) {
+	baseColor := DefaultBaseColor
+	if data.BaseColor != "" {
+		if !validator.IsCSSColor(data.BaseColor) {
+			return nil, ErrInvalidBaseColor
+		}
+		baseColor = data.BaseColor
+	}
+
+	tmpl, err := template.ParseFiles(QueuePageTemplate)
+	if err != nil {
+		return nil, err
+	}
+
+	// A plain string is filtered by html/template, only the data URI built
+	// here is marked as safe.
+	var logo interface{} = data.LogoURL
+	if len(data.InlineLogo) > 0 {
+		contentType := data.InlineLogoType
+		if contentType == "" {
+			contentType = http.DetectContentType(data.InlineLogo)
+		}
+		if !strings.HasPrefix(contentType, "image/") {
+			return nil, ErrInvalidLogo
+		}
+		logo = template.URL("data:" + contentType + ";base64," + base64.StdEncoding.EncodeToString(data.InlineLogo))
+	}
+
+	locale := data.Locale
+	if locale == "" {
+		locale = DefaultLocale
+	}
+
+	var buf bytes.Buffer
+	err = tmpl.Execute(&buf, struct {
+		Lang      string
+		Title     string
+		LogoURL   interface{}
+		BaseColor template.CSS
+		Text      dto.QueuePageText
+	}{
+		Lang:      locale,
+		Title:     data.Title,
+		LogoURL:   logo,
+		BaseColor: template.CSS(baseColor),
+		Text:      LocalizedText(locale, data.Text),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,124 @@
+package renderer
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"antrein/bc-dashboard/model/dto"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files")
+
+var testdata string
+
+// The template path is relative to the repository root, which is where the
+// service runs from.
+func TestMain(m *testing.M) {
+	flag.Parse()
+	dir, err := filepath.Abs("testdata")
+	if err != nil {
+		panic(err)
+	}
+	testdata = dir
+	if err := os.Chdir("../../.."); err != nil {
+		panic(err)
+	}
+	os.Exit(m.Run())
+}
+
+// 1x1 transparent PNG.
+var pixel = []byte{
+	0x89, 0x50, 0x4e, 0x47, 0x0d, 0x0a, 0x1a, 0x0a, 0x00, 0x00, 0x00, 0x0d,
+	0x49, 0x48, 0x44, 0x52, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x01,
+	0x08, 0x06, 0x00, 0x00, 0x00, 0x1f, 0x15, 0xc4, 0x89, 0x00, 0x00, 0x00,
+	0x0a, 0x49, 0x44, 0x41, 0x54, 0x78, 0x9c, 0x63, 0x00, 0x01, 0x00, 0x00,
+	0x05, 0x00, 0x01, 0x0d, 0x0a, 0x2d, 0xb4, 0x00, 0x00, 0x00, 0x00, 0x49,
+	0x45, 0x4e, 0x44, 0xae, 0x42, 0x60, 0x82,
+}
+
+func TestRenderQueuePage(t *testing.T) {
+	tests := []struct {
+		name string
+		data QueuePageData
+	}{
+		{
+			name: "hostile_title",
+			data: QueuePageData{
+				Title:     `</h1><script>alert("x")</script>`,
+				LogoURL:   `javascript:alert(1)`,
+				BaseColor: "#123abc",
+			},
+		},
+		{
+			name: "inline_logo",
+			data: QueuePageData{
+				Title:      "Konser",
+				LogoURL:    "https://cdn.example.com/logo.png",
+				InlineLogo: pixel,
+			},
+		},
+		{
+			name: "locale_fallback",
+			data: QueuePageData{
+				Title:  "Konser",
+				Locale: "id-ID",
+				Text:   dto.QueuePageText{WaitingMessage: "Sebentar lagi giliran Anda."},
+			},
+		},
+		{
+			name: "unknown_locale",
+			data: QueuePageData{
+				Title:  "Concert",
+				Locale: "xx",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RenderQueuePage(tt.data)
+			if err != nil {
+				t.Fatalf("RenderQueuePage() error = %v", err)
+			}
+
+			golden := filepath.Join(testdata, tt.name+".golden")
+			if *update {
+				if err := os.WriteFile(golden, got, 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("RenderQueuePage() differs from %s:\n%s", golden, got)
+			}
+		})
+	}
+}
+
+func TestRenderQueuePageRejectsInvalidColor(t *testing.T) {
+	colors := []string{
+		"red; background: url(https://evil.example.com)",
+		"#12345g",
+		"expression(alert(1))",
+		"</style><script>alert(1)</script>",
+	}
+	for _, color := range colors {
+		_, err := RenderQueuePage(QueuePageData{Title: "Konser", BaseColor: color})
+		if err != ErrInvalidBaseColor {
+			t.Errorf("RenderQueuePage(BaseColor: %q) error = %v, want %v", color, err, ErrInvalidBaseColor)
+		}
+	}
+}
+
+func TestRenderQueuePageRejectsNonImageLogo(t *testing.T) {
+	_, err := RenderQueuePage(QueuePageData{Title: "Konser", InlineLogo: []byte("<svg onload=alert(1)>")})
+	if err != ErrInvalidLogo {
+		t.Errorf("RenderQueuePage() error = %v, want %v", err, ErrInvalidLogo)
+	}
+}
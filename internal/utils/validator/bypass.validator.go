@@ -0,0 +1,54 @@
+package validator
+
+import (
+	"antrein/bc-dashboard/model/dto"
+	"errors"
+	"net"
+	"regexp"
+)
+
+var headerNameRegex = regexp.MustCompile("^[A-Za-z0-9-]{1,100}$")
+
+func IsIPOrCIDR(input string) bool {
+	if net.ParseIP(input) != nil {
+		return true
+	}
+	_, _, err := net.ParseCIDR(input)
+	return err == nil
+}
+
+func ValidateCreateBypassRule(req dto.CreateBypassRuleRequest) error {
+	if req.Name == "" || len(req.Name) > 155 {
+		return errors.New("Nama aturan bypass harus terdiri dari 1 sampai 155 karakter")
+	}
+	if req.MaxUses < 0 {
+		return errors.New("Batas penggunaan tidak boleh negatif")
+	}
+	switch req.Type {
+	case dto.BypassInviteCode:
+	case dto.BypassIP:
+		if !IsIPOrCIDR(req.CIDR) {
+			return errors.New("Alamat IP atau CIDR tidak valid")
+		}
+	case dto.BypassHeader:
+		if !headerNameRegex.MatchString(req.HeaderName) {
+			return errors.New("Nama header tidak valid")
+		}
+		if req.HeaderValue == "" || len(req.HeaderValue) > 255 {
+			return errors.New("Nilai header harus terdiri dari 1 sampai 255 karakter")
+		}
+	default:
+		return errors.New("Tipe aturan bypass tidak valid")
+	}
+	return nil
+}
+
+func ValidateUpdateBypassRule(req dto.UpdateBypassRuleRequest) error {
+	if req.Name != nil && (*req.Name == "" || len(*req.Name) > 155) {
+		return errors.New("Nama aturan bypass harus terdiri dari 1 sampai 155 karakter")
+	}
+	if req.MaxUses != nil && *req.MaxUses < 0 {
+		return errors.New("Batas penggunaan tidak boleh negatif")
+	}
+	return nil
+}
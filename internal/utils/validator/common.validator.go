@@ -45,3 +45,17 @@ func IsUsername(input string) bool {
 	regex := regexp.MustCompile(usernameRegex)
 	return regex.MatchString(input)
 }
+
+// IsCSSColor accepts hex colors, rgb/rgba/hsl/hsla functions with numeric
+// arguments and named colors.
+func IsCSSColor(input string) bool {
+	colorRegex := `^(#[0-9a-fA-F]{3,4}|#[0-9a-fA-F]{6}|#[0-9a-fA-F]{8}|(rgb|rgba|hsl|hsla)\(\s*[0-9.%]+(\s*[,/ ]\s*[0-9.%]+(deg)?){2,3}\s*\)|[a-zA-Z]{3,20})$`
+	regex := regexp.MustCompile(colorRegex)
+	return regex.MatchString(input)
+}
+
+func IsLocale(input string) bool {
+	localeRegex := `^[a-z]{2,3}(-[A-Z]{2})?$`
+	regex := regexp.MustCompile(localeRegex)
+	return regex.MatchString(input)
+}
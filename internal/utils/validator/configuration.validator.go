@@ -0,0 +1,102 @@
+package validator
+
+import (
+	"antrein/bc-dashboard/model/dto"
+	"errors"
+	"fmt"
+)
+
+// ValidatePriorityTiers checks the tiers against the room capacity. Reserved
+// shares are percentages of threshold and must leave at least one seat for
+// every tier that reserves any.
+func ValidatePriorityTiers(tiers []dto.PriorityTier, threshold, maxUsersInQueue int) error {
+	if len(tiers) > 10 {
+		return errors.New("Tier prioritas maksimal 10")
+	}
+	names := map[string]bool{}
+	totalShare := 0
+	for _, tier := range tiers {
+		if !IsAlphanumericWithSpace(tier.Name) || len(tier.Name) > 50 {
+			return errors.New("Nama tier hanya boleh terdiri dari huruf, angka dan spasi, maksimal 50 karakter")
+		}
+		if names[tier.Name] {
+			return fmt.Errorf("Nama tier %s duplikat", tier.Name)
+		}
+		names[tier.Name] = true
+		if tier.Weight < 1 {
+			return fmt.Errorf("Bobot tier %s minimal 1", tier.Name)
+		}
+		if tier.ReservedShare < 0 || tier.ReservedShare > 100 {
+			return fmt.Errorf("Kapasitas khusus tier %s harus di antara 0 sampai 100 persen", tier.Name)
+		}
+		if tier.ReservedShare > 0 && threshold*tier.ReservedShare/100 < 1 {
+			return fmt.Errorf("Kapasitas khusus tier %s kurang dari 1 user untuk threshold %d", tier.Name, threshold)
+		}
+		if tier.MaxUsersInQueue < 0 {
+			return fmt.Errorf("Maksimal antrian tier %s tidak boleh negatif", tier.Name)
+		}
+		if maxUsersInQueue > 0 && tier.MaxUsersInQueue > maxUsersInQueue {
+			return fmt.Errorf("Maksimal antrian tier %s melebihi maksimal antrian project", tier.Name)
+		}
+		totalShare += tier.ReservedShare
+	}
+	if totalShare > 100 {
+		return errors.New("Total kapasitas khusus tier melebihi 100 persen")
+	}
+	return nil
+}
+
+func ValidateAutoscalePolicy(req dto.UpdateAutoscalePolicy) error {
+	if req.MinThreshold < 1 {
+		return errors.New("Threshold minimal autoscale minimal 1")
+	}
+	if req.MaxThreshold < req.MinThreshold {
+		return errors.New("Threshold maksimal autoscale tidak boleh lebih kecil dari threshold minimal")
+	}
+	if req.StepDown < 1 || req.StepUp < 1 {
+		return errors.New("Langkah autoscale minimal 1")
+	}
+	if req.StepDown > req.MaxThreshold-req.MinThreshold+1 || req.StepUp > req.MaxThreshold-req.MinThreshold+1 {
+		return errors.New("Langkah autoscale melebihi rentang threshold")
+	}
+	return nil
+}
+
+// ValidateAutoscaleThreshold checks that the configured threshold lies within
+// the bounds of an enabled autoscale policy.
+func ValidateAutoscaleThreshold(threshold, minThreshold, maxThreshold int) error {
+	if threshold < minThreshold || threshold > maxThreshold {
+		return errors.New("Threshold project harus berada di antara threshold minimal dan maksimal autoscale")
+	}
+	return nil
+}
+
+func ValidateCreateTheme(req dto.CreateThemeRequest) error {
+	if !IsAlphanumericWithSpace(req.Name) || len(req.Name) > 50 {
+		return errors.New("Nama tema hanya boleh terdiri dari huruf, angka dan spasi, maksimal 50 karakter")
+	}
+	if req.QueuePageStyle != "base" && req.QueuePageStyle != "custom" {
+		return errors.New("Tipe style tidak valid")
+	}
+	return nil
+}
+
+func ValidateUpdateProjectLocale(req dto.UpdateProjectLocaleRequest) error {
+	if !IsLocale(req.Locale) {
+		return errors.New("Kode bahasa tidak valid, gunakan format seperti id atau en-US")
+	}
+	texts := []string{
+		req.Texts.PageTitle,
+		req.Texts.WaitingMessage,
+		req.Texts.EstimatedTimeLabel,
+		req.Texts.CalculatingLabel,
+		req.Texts.LastUpdatedLabel,
+		req.Texts.LogoAlt,
+	}
+	for _, text := range texts {
+		if len(text) > 255 {
+			return errors.New("Teks bahasa maksimal 255 karakter")
+		}
+	}
+	return nil
+}
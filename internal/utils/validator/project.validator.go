@@ -3,11 +3,98 @@ package validator
 import (
 	"antrein/bc-dashboard/model/dto"
 	"errors"
+	"regexp"
 )
 
-func ValidateCreateProject(req dto.CreateProjectRequest) error {
-	if !IsUsername(req.ID) {
+// reservedProjectIDs are the fixed segments of /bc/dashboard/project routes,
+// a project with one of them as id would be shadowed by the route.
+var reservedProjectIDs = map[string]bool{
+	"list": true, "health": true, "detail": true, "config": true, "style": true,
+	"state": true, "tier": true, "page": true, "locale": true, "clear": true,
+	"template": true, "history": true, "import": true, "theme": true,
+	"bypass": true, "autoscale": true,
+}
+
+func validateProjectID(id string) error {
+	if !IsUsername(id) {
 		return errors.New("ID project minimal 5 karakter, terdiri dari huruf kecil, angka, underscore(_) dan strip(-)")
 	}
+	if reservedProjectIDs[id] {
+		return errors.New("ID project tersebut tidak dapat digunakan")
+	}
+	return nil
+}
+
+func ValidateCreateProject(req dto.CreateProjectRequest) error {
+	if err := validateProjectID(req.ID); err != nil {
+		return err
+	}
+	if req.InfraMode != "" && req.InfraMode != "multi_tenant" && req.InfraMode != "single_tenant" {
+		return errors.New("Mode infra harus multi_tenant atau single_tenant")
+	}
+	if req.ResourceSize != "" && !labelRegex.MatchString(req.ResourceSize) {
+		return errors.New("Ukuran resource tidak valid")
+	}
+	return nil
+}
+
+func ValidateCloneProject(req dto.CloneProjectRequest) error {
+	return validateProjectID(req.ID)
+}
+
+func ValidateCreateProjectTemplate(req dto.CreateProjectTemplateRequest) error {
+	if req.Name == "" {
+		return errors.New("Nama template tidak boleh kosong")
+	}
+	if req.QueuePageStyle != "" && req.QueuePageStyle != "base" && req.QueuePageStyle != "custom" {
+		return errors.New("Tipe style tidak valid")
+	}
+	return nil
+}
+
+var labelRegex = regexp.MustCompile("^[a-z0-9_-]{1,50}$")
+
+func ValidateUpdateProject(req dto.UpdateProjectRequest) error {
+	if req.Name != nil && (*req.Name == "" || len(*req.Name) > 155) {
+		return errors.New("Nama project harus terdiri dari 1 sampai 155 karakter")
+	}
+	if req.Description != nil && len(*req.Description) > 1000 {
+		return errors.New("Deskripsi project maksimal 1000 karakter")
+	}
+	if req.Tags != nil {
+		if len(*req.Tags) > 20 {
+			return errors.New("Tag project maksimal 20")
+		}
+		for _, tag := range *req.Tags {
+			if !labelRegex.MatchString(tag) {
+				return errors.New("Tag project hanya boleh terdiri dari huruf kecil, angka, underscore(_) dan strip(-)")
+			}
+		}
+	}
+	if req.Environment != nil && *req.Environment != "" && !labelRegex.MatchString(*req.Environment) {
+		return errors.New("Label environment tidak valid")
+	}
+	if req.ContactEmail != nil && *req.ContactEmail != "" && !IsEmail(*req.ContactEmail) {
+		return errors.New("Email kontak tidak valid")
+	}
+	return nil
+}
+
+func ValidateListProject(req dto.ListProjectRequest) error {
+	if req.Page < 1 {
+		return errors.New("Parameter page minimal 1")
+	}
+	if req.PageSize < 1 || req.PageSize > 100 {
+		return errors.New("Parameter page_size harus di antara 1 sampai 100")
+	}
+	if req.Health != "" && req.Health != "healthy" && req.Health != "unhealthy" {
+		return errors.New("Parameter health harus healthy atau unhealthy")
+	}
+	if req.SortBy != "" && req.SortBy != "name" && req.SortBy != "created_at" && req.SortBy != "updated_at" {
+		return errors.New("Parameter sort_by harus name, created_at atau updated_at")
+	}
+	if req.SortOrder != "" && req.SortOrder != "asc" && req.SortOrder != "desc" {
+		return errors.New("Parameter sort_order harus asc atau desc")
+	}
 	return nil
 }
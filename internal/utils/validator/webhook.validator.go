@@ -0,0 +1,81 @@
+package validator
+
+import (
+	"antrein/bc-dashboard/model/dto"
+	"errors"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// IsPublicIP reports whether ip is reachable on the internet, so webhooks
+// can not be pointed at the dashboard host, the cluster network or cloud
+// metadata endpoints.
+func IsPublicIP(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	return ip.IsValid() && ip.IsGlobalUnicast() && !ip.IsPrivate() && !sharedAddressSpace.Contains(ip)
+}
+
+// IsWebhookURL accepts https URLs without credentials. Plain http is only
+// accepted with allowHTTP, which the development stage sets, and the host
+// still has to be public there, a receiver on localhost or a private network
+// is reached through a public tunnel. Hosts given as an address must be
+// public, names are checked again when they are dialed.
+func IsWebhookURL(input string, allowHTTP bool) bool {
+	if len(input) > 500 {
+		return false
+	}
+	u, err := url.Parse(input)
+	if err != nil {
+		return false
+	}
+	if u.Scheme != "https" && !(allowHTTP && u.Scheme == "http") {
+		return false
+	}
+	host := strings.ToLower(u.Hostname())
+	if host == "" || u.User != nil || host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return false
+	}
+	if ip, err := netip.ParseAddr(host); err == nil {
+		return IsPublicIP(ip)
+	}
+	return true
+}
+
+func validateWebhookEvents(events []string) error {
+	if len(events) == 0 {
+		return errors.New("Webhook harus berlangganan minimal satu event")
+	}
+	for _, event := range events {
+		if !slices.Contains(dto.EventTypes, event) {
+			return errors.New("Event " + event + " tidak dikenal")
+		}
+	}
+	return nil
+}
+
+func ValidateCreateWebhook(req dto.CreateWebhookRequest, allowHTTP bool) error {
+	if !IsWebhookURL(req.URL, allowHTTP) {
+		return errors.New("URL webhook harus berupa url https publik yang valid")
+	}
+	if len(req.Description) > 255 {
+		return errors.New("Deskripsi webhook maksimal 255 karakter")
+	}
+	return validateWebhookEvents(req.Events)
+}
+
+func ValidateUpdateWebhook(req dto.UpdateWebhookRequest, allowHTTP bool) error {
+	if req.URL != nil && !IsWebhookURL(*req.URL, allowHTTP) {
+		return errors.New("URL webhook harus berupa url https publik yang valid")
+	}
+	if req.Description != nil && len(*req.Description) > 255 {
+		return errors.New("Deskripsi webhook maksimal 255 karakter")
+	}
+	if req.Events != nil {
+		return validateWebhookEvents(*req.Events)
+	}
+	return nil
+}
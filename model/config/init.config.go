@@ -2,6 +2,7 @@ package config
 
 import (
 	"encoding/json"
+	"errors"
 	"io"
 	"os"
 )
@@ -25,5 +26,14 @@ func New() (*Config, error) {
 
 	// Parse the JSON data into the Config struct
 	err = json.Unmarshal(fileData, &config)
-	return &config, err
+	if err != nil {
+		return nil, err
+	}
+
+	// Bypass codes are signed with their own key, so a leaked one can not be
+	// turned into a dashboard token and the other way around
+	if config.Secrets.BypassSecret == "" {
+		return nil, errors.New("secrets.bypass_secret is required")
+	}
+	return &config, nil
 }
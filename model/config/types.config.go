@@ -1,13 +1,19 @@
 package config
 
+// StageDevelopment is the stage of local setups.
+const StageDevelopment = "development"
+
 type Config struct {
-	Server     ServerConfig   `json:"server"`
-	Database   DatabaseConfig `json:"database"`
-	Secrets    SecretConfig   `json:"secrets"`
-	Stage      string         `json:"stage"`
-	Infra      InfraConfig    `json:"infra"`
-	SMTP       SMTPConfig     `json:"smtp"`
-	GRPCConfig GRPCConfig     `json:"grpc"`
+	Server     ServerConfig             `json:"server"`
+	Database   DatabaseConfig           `json:"database"`
+	Secrets    SecretConfig             `json:"secrets"`
+	Stage      string                   `json:"stage"`
+	Infra      InfraConfig              `json:"infra"`
+	SMTP       SMTPConfig               `json:"smtp"`
+	GRPCConfig GRPCConfig               `json:"grpc"`
+	HTMLPolicy HTMLPolicyConfig         `json:"html_policy"`
+	Logo       LogoConfig               `json:"logo"`
+	Storage    map[string]StorageConfig `json:"storage"`
 }
 
 type PostgreConfig struct {
@@ -37,7 +43,8 @@ type DatabaseConfig struct {
 }
 
 type SecretConfig struct {
-	JWTSecret string `json:"jwt_secret"`
+	JWTSecret    string `json:"jwt_secret"`
+	BypassSecret string `json:"bypass_secret"`
 }
 
 type SMTPConfig struct {
@@ -47,11 +54,71 @@ type SMTPConfig struct {
 	Password string `json:"password"`
 }
 
+// InfraConfig with UseFake set runs against an in-memory infra manager
+// instead of the one at ManagerURL. TimeoutSeconds overrides the timeout of
+// a manager operation by its name, e.g. check_health. Mode is multi_tenant or
+// single_tenant, ResourceSizes overrides the single tenant deployment sizes.
 type InfraConfig struct {
-	Mode       string `json:"mode"`
-	ManagerURL string `json:"manager_url"`
+	Mode                   string                        `json:"mode"`
+	ResourceSizes          map[string]ResourceSizeConfig `json:"resource_sizes"`
+	ManagerURL             string                        `json:"manager_url"`
+	UseFake                bool                          `json:"use_fake"`
+	MaxRetries             int                           `json:"max_retries"`
+	BreakerThreshold       int                           `json:"breaker_threshold"`
+	BreakerCooldownSeconds int                           `json:"breaker_cooldown_seconds"`
+	TimeoutSeconds         map[string]int                `json:"timeout_seconds"`
+	Auth                   InfraAuthConfig               `json:"auth"`
+}
+
+// InfraAuthConfig is how requests to the infra manager are authenticated.
+// Mode is none, bearer, hmac or mtls. CAFile is only needed when the manager
+// certificate is not signed by a system CA.
+type InfraAuthConfig struct {
+	Mode           string `json:"mode"`
+	Token          string `json:"token"`
+	HMACSecret     string `json:"hmac_secret"`
+	MaxSkewSeconds int    `json:"max_skew_seconds"`
+	ClientCertFile string `json:"client_cert_file"`
+	ClientKeyFile  string `json:"client_key_file"`
+	CAFile         string `json:"ca_file"`
+}
+
+type ResourceSizeConfig struct {
+	CPU      string `json:"cpu"`
+	Memory   string `json:"memory"`
+	Replicas int    `json:"replicas"`
 }
 
 type GRPCConfig struct {
 	DashboardQueue string `json:"dashboard_queue"`
 }
+
+type HTMLPolicyConfig struct {
+	ScriptAllowlist []string `json:"script_allowlist"`
+	MaxPageSize     int      `json:"max_page_size"`
+}
+
+type LogoConfig struct {
+	MaxFileSize  int `json:"max_file_size"`
+	MaxDimension int `json:"max_dimension"`
+}
+
+// StorageConfig is the asset storage of one stage, Config.Storage is keyed by
+// stage name.
+type StorageConfig struct {
+	Backend        string   `json:"backend"`
+	PublicURL      string   `json:"public_url"`
+	DefaultLogoURL string   `json:"default_logo_url"`
+	LocalDir       string   `json:"local_dir"`
+	RetentionHours int      `json:"retention_hours"`
+	S3             S3Config `json:"s3"`
+}
+
+type S3Config struct {
+	Endpoint  string `json:"endpoint"`
+	Region    string `json:"region"`
+	Bucket    string `json:"bucket"`
+	AccessKey string `json:"access_key"`
+	SecretKey string `json:"secret_key"`
+	PathStyle bool   `json:"path_style"`
+}
@@ -0,0 +1,23 @@
+package dto
+
+import "time"
+
+type PageVersion struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	URL         string    `json:"url"`
+	Hash        string    `json:"hash"`
+	IsCurrent   bool      `json:"is_current"`
+	PublishedAt time.Time `json:"published_at"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type ListPageVersionResponse struct {
+	ProjectID string        `json:"project_id"`
+	Versions  []PageVersion `json:"versions"`
+}
+
+type RollbackPageRequest struct {
+	ProjectID string `json:"project_id"`
+	VersionID string `json:"version_id"`
+}
@@ -0,0 +1,39 @@
+package dto
+
+import "time"
+
+type AutoscalePolicy struct {
+	ProjectID           string     `json:"project_id"`
+	Enabled             bool       `json:"enabled"`
+	MinThreshold        int        `json:"min_threshold"`
+	MaxThreshold        int        `json:"max_threshold"`
+	StepDown            int        `json:"step_down"`
+	StepUp              int        `json:"step_up"`
+	LastAdjustedAt      *time.Time `json:"last_adjusted_at,omitempty"`
+	ConfiguredThreshold int        `json:"configured_threshold"`
+	EffectiveThreshold  int        `json:"effective_threshold"`
+}
+
+type UpdateAutoscalePolicy struct {
+	ProjectID    string `json:"project_id"`
+	Enabled      bool   `json:"enabled"`
+	MinThreshold int    `json:"min_threshold"`
+	MaxThreshold int    `json:"max_threshold"`
+	StepDown     int    `json:"step_down"`
+	StepUp       int    `json:"step_up"`
+}
+
+type ThresholdAdjustment struct {
+	FromThreshold int       `json:"from_threshold"`
+	ToThreshold   int       `json:"to_threshold"`
+	Healthiness   bool      `json:"healthiness"`
+	UsersInRoom   int       `json:"users_in_room"`
+	UsersInQueue  int       `json:"users_in_queue"`
+	Reason        string    `json:"reason"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type AutoscaleDetailResponse struct {
+	Policy      *AutoscalePolicy      `json:"policy"`
+	Adjustments []ThresholdAdjustment `json:"adjustments"`
+}
@@ -0,0 +1,54 @@
+package dto
+
+import "time"
+
+const (
+	BypassInviteCode = "invite_code"
+	BypassIP         = "ip"
+	BypassHeader     = "header"
+)
+
+type BypassRule struct {
+	ID          string     `json:"id"`
+	ProjectID   string     `json:"project_id"`
+	Type        string     `json:"type"`
+	Name        string     `json:"name"`
+	Code        string     `json:"code,omitempty"`
+	CIDR        string     `json:"cidr,omitempty"`
+	HeaderName  string     `json:"header_name,omitempty"`
+	HeaderValue string     `json:"header_value,omitempty"`
+	MaxUses     int        `json:"max_uses"`
+	UsedCount   int        `json:"used_count"`
+	LastUsedAt  *time.Time `json:"last_used_at,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	IsActive    bool       `json:"is_active"`
+}
+
+type CreateBypassRuleRequest struct {
+	ProjectID   string `json:"project_id"`
+	Type        string `json:"type"`
+	Name        string `json:"name"`
+	CIDR        string `json:"cidr,omitempty"`
+	HeaderName  string `json:"header_name,omitempty"`
+	HeaderValue string `json:"header_value,omitempty"`
+	MaxUses     int    `json:"max_uses"`
+	ExpiresAt   string `json:"expires_at,omitempty"`
+}
+
+type UpdateBypassRuleRequest struct {
+	Name      *string `json:"name,omitempty"`
+	MaxUses   *int    `json:"max_uses,omitempty"`
+	ExpiresAt *string `json:"expires_at,omitempty"`
+	IsActive  *bool   `json:"is_active,omitempty"`
+}
+
+type ListBypassRuleResponse struct {
+	ProjectID string       `json:"project_id"`
+	Rules     []BypassRule `json:"rules"`
+}
+
+type RedeemBypassCodeResponse struct {
+	Allowed bool   `json:"allowed"`
+	RuleID  string `json:"rule_id,omitempty"`
+	Reason  string `json:"reason,omitempty"`
+}
@@ -3,20 +3,71 @@ package dto
 import "time"
 
 type ProjectConfig struct {
-	ProjectID          string    `json:"project_id"`
-	Threshold          int       `json:"threshold"`
-	SessionTime        int       `json:"session_time"`
-	Host               string    `json:"host"`
-	BaseURL            string    `json:"base_url"`
-	MaxUsersInQueue    int       `json:"max_users_in_queue"`
-	QueueStart         time.Time `json:"queue_start"`
-	QueueEnd           time.Time `json:"queue_end"`
-	QueuePageStyle     string    `json:"queue_page_style"`
-	QueueHTMLPage      string    `json:"queue_html_page"`
-	QueuePageBaseColor string    `json:"queue_page_base_color"`
-	QueuePageTitle     string    `json:"queue_page_title"`
-	QueuePageLogo      string    `json:"queue_page_logo"`
-	IsConfigure        bool      `json:"is_configure"`
+	ProjectID          string            `json:"project_id"`
+	Threshold          int               `json:"threshold"`
+	EffectiveThreshold int               `json:"effective_threshold"`
+	SessionTime        int               `json:"session_time"`
+	Host               string            `json:"host"`
+	BaseURL            string            `json:"base_url"`
+	MaxUsersInQueue    int               `json:"max_users_in_queue"`
+	QueueStart         time.Time         `json:"queue_start"`
+	QueueEnd           time.Time         `json:"queue_end"`
+	QueuePageStyle     string            `json:"queue_page_style"`
+	QueueHTMLPage      string            `json:"queue_html_page"`
+	QueuePageBaseColor string            `json:"queue_page_base_color"`
+	QueuePageTitle     string            `json:"queue_page_title"`
+	QueuePageLogo      string            `json:"queue_page_logo"`
+	IsConfigure        bool              `json:"is_configure"`
+	OperationalState   string            `json:"operational_state"`
+	StateReason        string            `json:"state_reason,omitempty"`
+	StateChangedAt     *time.Time        `json:"state_changed_at,omitempty"`
+	AutoResumeAt       *time.Time        `json:"auto_resume_at,omitempty"`
+	PriorityTiers      []PriorityTier    `json:"priority_tiers,omitempty"`
+	QueueMode          string            `json:"queue_mode"`
+	PreQueueOpenAt     *time.Time        `json:"pre_queue_open_at,omitempty"`
+	LotterySeed        string            `json:"lottery_seed,omitempty"`
+	ActiveThemeID      string            `json:"active_theme_id,omitempty"`
+	Locales            []string          `json:"locales,omitempty"`
+	LocalizedPages     map[string]string `json:"localized_pages,omitempty"`
+}
+
+type PriorityTier struct {
+	Name            string `json:"name"`
+	Priority        int    `json:"priority"`
+	Weight          int    `json:"weight"`
+	ReservedShare   int    `json:"reserved_share"`
+	MaxUsersInQueue int    `json:"max_users_in_queue"`
+}
+
+type UpdatePriorityTiers struct {
+	ProjectID string         `json:"project_id"`
+	Tiers     []PriorityTier `json:"tiers"`
+}
+
+type ListPriorityTierResponse struct {
+	ProjectID string         `json:"project_id"`
+	Threshold int            `json:"threshold"`
+	Tiers     []PriorityTier `json:"tiers"`
+}
+
+const (
+	QueueModeFIFO            = "fifo"
+	QueueModePreQueueLottery = "pre_queue_lottery"
+)
+
+const (
+	StateActive      = "active"
+	StatePaused      = "paused"
+	StateDrainOnly   = "drain_only"
+	StateClosed      = "closed"
+	StateMaintenance = "maintenance"
+)
+
+type UpdateProjectState struct {
+	ProjectID    string `json:"project_id"`
+	State        string `json:"state"`
+	Reason       string `json:"reason,omitempty"`
+	AutoResumeAt string `json:"auto_resume_at,omitempty"`
 }
 
 type UpdateProjectConfig struct {
@@ -28,6 +79,8 @@ type UpdateProjectConfig struct {
 	MaxUsersInQueue int    `json:"max_users_in_queue"`
 	QueueStart      string `json:"queue_start"`
 	QueueEnd        string `json:"queue_end"`
+	QueueMode       string `json:"queue_mode,omitempty"`
+	PreQueueOpen    string `json:"pre_queue_open,omitempty"`
 }
 
 type UpdateProjectStyle struct {
@@ -35,4 +88,27 @@ type UpdateProjectStyle struct {
 	QueuePageStyle     string `json:"queue_page_style"`
 	QueuePageBaseColor string `json:"queue_page_base_color,omitempty"`
 	QueuePageTitle     string `json:"queue_page_title,omitempty"`
+	QueuePageLogo      string `json:"queue_page_logo,omitempty"`
+}
+
+type StylePreviewResponse struct {
+	ProjectID      string          `json:"project_id"`
+	QueuePageStyle string          `json:"queue_page_style"`
+	HTML           string          `json:"html"`
+	Violations     []HTMLViolation `json:"violations"`
+}
+
+type UpdateProjectStyleResponse struct {
+	ProjectID      string          `json:"project_id"`
+	QueuePageStyle string          `json:"queue_page_style"`
+	Violations     []HTMLViolation `json:"violations"`
+}
+
+// HTMLViolation is a custom queue page finding. Stripped violations were
+// removed from the page, the others have to be fixed by the operator.
+type HTMLViolation struct {
+	Rule     string `json:"rule"`
+	Element  string `json:"element,omitempty"`
+	Detail   string `json:"detail"`
+	Stripped bool   `json:"stripped"`
 }
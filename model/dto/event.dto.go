@@ -0,0 +1,58 @@
+package dto
+
+import "time"
+
+// EventVersion is bumped whenever the shape of an event payload changes in a
+// way consumers have to know about.
+const EventVersion = "1"
+
+const (
+	EventProjectCreated    = "project.created"
+	EventProjectDeleted    = "project.deleted"
+	EventConfigPublished   = "project.config_published"
+	EventQueueWindowOpened = "queue.window_opened"
+	EventQueueWindowClosed = "queue.window_closed"
+	EventHealthChanged     = "project.health_changed"
+	EventAlertFired        = "alert.fired"
+	// EventWebhookPing is only sent by the webhook test ping, it cannot be
+	// subscribed to.
+	EventWebhookPing = "webhook.ping"
+)
+
+// EventTypes are the event types webhooks can subscribe to.
+var EventTypes = []string{
+	EventProjectCreated,
+	EventProjectDeleted,
+	EventConfigPublished,
+	EventQueueWindowOpened,
+	EventQueueWindowClosed,
+	EventHealthChanged,
+	EventAlertFired,
+}
+
+const (
+	AlertProvisioningFailed = "provisioning_failed"
+	AlertOriginUnhealthy    = "origin_unhealthy"
+)
+
+// ProjectEvent may be published without TenantID by code that only knows the
+// project, subscribers that need the tenant look it up from ProjectID.
+type ProjectEvent struct {
+	ID         string      `json:"id"`
+	Type       string      `json:"type"`
+	Version    string      `json:"version"`
+	TenantID   string      `json:"tenant_id"`
+	ProjectID  string      `json:"project_id,omitempty"`
+	OccurredAt time.Time   `json:"occurred_at"`
+	Data       interface{} `json:"data,omitempty"`
+}
+
+type QueueWindow struct {
+	QueueStart time.Time `json:"queue_start"`
+	QueueEnd   time.Time `json:"queue_end"`
+}
+
+type Alert struct {
+	Alert   string `json:"alert"`
+	Message string `json:"message"`
+}
@@ -0,0 +1,47 @@
+package dto
+
+import "time"
+
+type HealthCheck struct {
+	Healthy   bool      `json:"healthy"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+type HealthIncident struct {
+	ID              string     `json:"id"`
+	StartedAt       time.Time  `json:"started_at"`
+	EndedAt         *time.Time `json:"ended_at,omitempty"`
+	DurationSeconds int64      `json:"duration_seconds"`
+	Ongoing         bool       `json:"ongoing"`
+}
+
+// HealthChange is the data of a project.health_changed event.
+type HealthChange struct {
+	Healthy    bool      `json:"healthy"`
+	IncidentID string    `json:"incident_id,omitempty"`
+	ChangedAt  time.Time `json:"changed_at"`
+}
+
+// HealthUptimeResponse covers the window from From to To. Monitoring may
+// have started later than From, uptime is only computed over MonitoredFrom.
+type HealthUptimeResponse struct {
+	ProjectID       string     `json:"project_id"`
+	From            time.Time  `json:"from"`
+	To              time.Time  `json:"to"`
+	MonitoredFrom   *time.Time `json:"monitored_from,omitempty"`
+	TotalChecks     int        `json:"total_checks"`
+	HealthyChecks   int        `json:"healthy_checks"`
+	DowntimeSeconds int64      `json:"downtime_seconds"`
+	UptimePercent   *float64   `json:"uptime_percent"`
+	Incidents       int        `json:"incidents"`
+}
+
+type ListHealthIncidentResponse struct {
+	ProjectID string           `json:"project_id"`
+	Incidents []HealthIncident `json:"incidents"`
+}
+
+type HealthTimelineResponse struct {
+	ProjectID string        `json:"project_id"`
+	Checks    []HealthCheck `json:"checks"`
+}
@@ -0,0 +1,26 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type ProjectHistory struct {
+	ID        string          `json:"id"`
+	ProjectID string          `json:"project_id"`
+	Actor     string          `json:"actor,omitempty"`
+	Action    string          `json:"action"`
+	Detail    json.RawMessage `json:"detail,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+type FieldChange struct {
+	Field string `json:"field"`
+	From  string `json:"from"`
+	To    string `json:"to"`
+}
+
+type ListProjectHistoryResponse struct {
+	ProjectID string           `json:"project_id"`
+	Histories []ProjectHistory `json:"histories"`
+}
@@ -0,0 +1,28 @@
+package dto
+
+// QueuePageText holds every fixed string of the base queue page. Empty fields
+// fall back to the built-in text of the locale.
+type QueuePageText struct {
+	PageTitle          string `json:"page_title,omitempty"`
+	WaitingMessage     string `json:"waiting_message,omitempty"`
+	EstimatedTimeLabel string `json:"estimated_time_label,omitempty"`
+	CalculatingLabel   string `json:"calculating_label,omitempty"`
+	LastUpdatedLabel   string `json:"last_updated_label,omitempty"`
+	LogoAlt            string `json:"logo_alt,omitempty"`
+}
+
+type ProjectLocale struct {
+	Locale string        `json:"locale"`
+	Texts  QueuePageText `json:"texts"`
+}
+
+type UpdateProjectLocaleRequest struct {
+	ProjectID string        `json:"project_id"`
+	Locale    string        `json:"locale"`
+	Texts     QueuePageText `json:"texts"`
+}
+
+type ListProjectLocaleResponse struct {
+	ProjectID string          `json:"project_id"`
+	Locales   []ProjectLocale `json:"locales"`
+}
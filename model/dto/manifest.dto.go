@@ -0,0 +1,56 @@
+package dto
+
+const (
+	ManifestAPIVersion = "antrein/v1"
+	ManifestKind       = "Project"
+)
+
+type ProjectManifest struct {
+	APIVersion    string                `json:"api_version" yaml:"api_version"`
+	Kind          string                `json:"kind" yaml:"kind"`
+	Metadata      ManifestMetadata      `json:"metadata" yaml:"metadata"`
+	Configuration ManifestConfiguration `json:"configuration" yaml:"configuration"`
+	Schedule      ManifestSchedule      `json:"schedule" yaml:"schedule"`
+	Style         ManifestStyle         `json:"style" yaml:"style"`
+}
+
+type ManifestMetadata struct {
+	ID   string `json:"id" yaml:"id"`
+	Name string `json:"name" yaml:"name"`
+}
+
+type ManifestConfiguration struct {
+	Threshold       int    `json:"threshold" yaml:"threshold"`
+	SessionTime     int    `json:"session_time" yaml:"session_time"`
+	Host            string `json:"host" yaml:"host"`
+	BaseURL         string `json:"base_url" yaml:"base_url"`
+	MaxUsersInQueue int    `json:"max_users_in_queue" yaml:"max_users_in_queue"`
+	QueueMode       string `json:"queue_mode,omitempty" yaml:"queue_mode,omitempty"`
+}
+
+type ManifestSchedule struct {
+	QueueStart   string `json:"queue_start" yaml:"queue_start"`
+	QueueEnd     string `json:"queue_end" yaml:"queue_end"`
+	PreQueueOpen string `json:"pre_queue_open,omitempty" yaml:"pre_queue_open,omitempty"`
+}
+
+type ManifestStyle struct {
+	QueuePageStyle     string `json:"queue_page_style" yaml:"queue_page_style"`
+	QueuePageBaseColor string `json:"queue_page_base_color,omitempty" yaml:"queue_page_base_color,omitempty"`
+	QueuePageTitle     string `json:"queue_page_title,omitempty" yaml:"queue_page_title,omitempty"`
+	QueuePageLogo      string `json:"queue_page_logo,omitempty" yaml:"queue_page_logo,omitempty"`
+	QueueHTMLPage      string `json:"queue_html_page,omitempty" yaml:"queue_html_page,omitempty"`
+}
+
+type ManifestChange struct {
+	Field string `json:"field"`
+	From  string `json:"from"`
+	To    string `json:"to"`
+}
+
+type ImportProjectResponse struct {
+	ProjectID string           `json:"project_id"`
+	Action    string           `json:"action"`
+	DryRun    bool             `json:"dry_run"`
+	Changes   []ManifestChange `json:"changes"`
+}
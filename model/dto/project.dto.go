@@ -1,26 +1,67 @@
 package dto
 
+import "time"
+
 type Project struct {
-	ID       string `json:"id"`
-	TenantID string `json:"tenant_id"`
-	Name     string `json:"name"`
+	ID           string   `json:"id"`
+	TenantID     string   `json:"tenant_id"`
+	Name         string   `json:"name"`
+	Description  string   `json:"description,omitempty"`
+	Tags         []string `json:"tags,omitempty"`
+	Environment  string   `json:"environment,omitempty"`
+	ContactEmail string   `json:"contact_email,omitempty"`
+	IsConfigure  *bool    `json:"is_configure,omitempty"`
+	Healthiness  *bool    `json:"healthiness,omitempty"`
+}
+
+type ListProjectRequest struct {
+	Page       int
+	PageSize   int
+	Search     string
+	Configured *bool
+	Health     string
+	SortBy     string
+	SortOrder  string
 }
 
+// CreateProjectRequest with InfraMode set overrides the infra mode for the
+// project, which only premium tenants may do. ResourceSize sizes the
+// dedicated deployment of single tenant projects.
 type CreateProjectRequest struct {
-	ID   string `json:"id"`
-	Name string `json:"name"`
+	ID           string `json:"id"`
+	Name         string `json:"name"`
+	TemplateID   string `json:"template_id,omitempty"`
+	InfraMode    string `json:"infra_mode,omitempty"`
+	ResourceSize string `json:"resource_size,omitempty"`
 }
 
-type ProjectDetailResponse struct {
-	ID            string        `json:"id"`
-	TenantID      string        `json:"tenant_id"`
-	Name          string        `json:"name"`
-	Configuration ProjectConfig `json:"configuration"`
+type CloneProjectRequest struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Host    string `json:"host,omitempty"`
+	BaseURL string `json:"base_url,omitempty"`
 }
 
-type ListProjectResponse struct {
-	TenantID string    `json:"tenant_id"`
-	Projects []Project `json:"projects"`
+type UpdateProjectRequest struct {
+	Name         *string   `json:"name,omitempty"`
+	Description  *string   `json:"description,omitempty"`
+	Tags         *[]string `json:"tags,omitempty"`
+	Environment  *string   `json:"environment,omitempty"`
+	ContactEmail *string   `json:"contact_email,omitempty"`
+}
+
+type ProjectDetailResponse struct {
+	ID            string             `json:"id"`
+	TenantID      string             `json:"tenant_id"`
+	Name          string             `json:"name"`
+	Description   string             `json:"description,omitempty"`
+	Tags          []string           `json:"tags,omitempty"`
+	Environment   string             `json:"environment,omitempty"`
+	ContactEmail  string             `json:"contact_email,omitempty"`
+	InfraMode     string             `json:"infra_mode"`
+	ResourceSize  string             `json:"resource_size,omitempty"`
+	Provisioning  ProvisioningStatus `json:"provisioning"`
+	Configuration ProjectConfig      `json:"configuration"`
 }
 
 type CheckHealthProjectResponse struct {
@@ -28,6 +69,40 @@ type CheckHealthProjectResponse struct {
 	Healthiness bool   `json:"healthiness"`
 }
 
+const (
+	HealthStatusHealthy      = "healthy"
+	HealthStatusUnhealthy    = "unhealthy"
+	HealthStatusError        = "error"
+	HealthStatusUnconfigured = "unconfigured"
+)
+
+// Why the infra manager could not tell the health of a project.
+const (
+	HealthErrorTimeout     = "timeout"
+	HealthErrorUnavailable = "unavailable"
+	HealthErrorRejected    = "rejected"
+)
+
+// ProjectHealthStatus is error when the infra manager could not tell whether
+// the project is healthy, Healthiness is only set when it could.
+type ProjectHealthStatus struct {
+	ID          string `json:"id"`
+	Status      string `json:"status"`
+	Healthiness *bool  `json:"healthiness,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+type CheckHealthProjectsResponse struct {
+	TenantID     string                `json:"tenant_id"`
+	Total        int                   `json:"total"`
+	Healthy      int                   `json:"healthy"`
+	Unhealthy    int                   `json:"unhealthy"`
+	Errors       int                   `json:"errors"`
+	Unconfigured int                   `json:"unconfigured"`
+	CheckedAt    time.Time             `json:"checked_at"`
+	Projects     []ProjectHealthStatus `json:"projects"`
+}
+
 type CreateProjectResponse struct {
 	Project
 }
@@ -0,0 +1,26 @@
+package dto
+
+import "time"
+
+const (
+	ProvisioningPending        = "pending"
+	ProvisioningProvisioning   = "provisioning"
+	ProvisioningReady          = "ready"
+	ProvisioningFailed         = "failed"
+	ProvisioningDeprovisioning = "deprovisioning"
+)
+
+type ProvisioningStatus struct {
+	ProjectID      string                   `json:"project_id"`
+	State          string                   `json:"state"`
+	LastError      string                   `json:"last_error,omitempty"`
+	StateChangedAt *time.Time               `json:"state_changed_at,omitempty"`
+	Transitions    []ProvisioningTransition `json:"transitions"`
+}
+
+type ProvisioningTransition struct {
+	FromState string    `json:"from_state"`
+	ToState   string    `json:"to_state"`
+	Error     string    `json:"error,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
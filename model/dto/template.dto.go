@@ -0,0 +1,32 @@
+package dto
+
+type ProjectTemplate struct {
+	ID                 string `json:"id"`
+	TenantID           string `json:"tenant_id"`
+	Name               string `json:"name"`
+	Threshold          int    `json:"threshold"`
+	SessionTime        int    `json:"session_time"`
+	MaxUsersInQueue    int    `json:"max_users_in_queue"`
+	QueuePageStyle     string `json:"queue_page_style"`
+	QueueHTMLPage      string `json:"queue_html_page"`
+	QueuePageBaseColor string `json:"queue_page_base_color"`
+	QueuePageTitle     string `json:"queue_page_title"`
+	QueuePageLogo      string `json:"queue_page_logo"`
+}
+
+type CreateProjectTemplateRequest struct {
+	Name               string `json:"name"`
+	ProjectID          string `json:"project_id,omitempty"`
+	Threshold          int    `json:"threshold"`
+	SessionTime        int    `json:"session_time"`
+	MaxUsersInQueue    int    `json:"max_users_in_queue"`
+	QueuePageStyle     string `json:"queue_page_style"`
+	QueuePageBaseColor string `json:"queue_page_base_color,omitempty"`
+	QueuePageTitle     string `json:"queue_page_title,omitempty"`
+	QueuePageLogo      string `json:"queue_page_logo,omitempty"`
+}
+
+type ListProjectTemplateResponse struct {
+	TenantID  string            `json:"tenant_id"`
+	Templates []ProjectTemplate `json:"templates"`
+}
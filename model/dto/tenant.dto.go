@@ -22,3 +22,18 @@ type CreateTenantResponse struct {
 	Tenant Tenant `json:"tenant"`
 	Token  string `json:"token"`
 }
+
+const (
+	HTMLPolicyStrict     = "strict"
+	HTMLPolicyPermissive = "permissive"
+)
+
+type UpdateHTMLPolicyRequest struct {
+	Mode string `json:"mode"`
+}
+
+type HTMLPolicyResponse struct {
+	Mode            string   `json:"mode"`
+	ScriptAllowlist []string `json:"script_allowlist"`
+	MaxPageSize     int      `json:"max_page_size"`
+}
@@ -0,0 +1,58 @@
+package dto
+
+import "time"
+
+type Theme struct {
+	ID                 string          `json:"id"`
+	ProjectID          string          `json:"project_id,omitempty"`
+	Name               string          `json:"name"`
+	QueuePageStyle     string          `json:"queue_page_style"`
+	QueueHTMLPage      string          `json:"queue_html_page"`
+	QueuePageBaseColor string          `json:"queue_page_base_color"`
+	QueuePageTitle     string          `json:"queue_page_title"`
+	QueuePageLogo      string          `json:"queue_page_logo"`
+	IsActive           bool            `json:"is_active"`
+	CreatedAt          time.Time       `json:"created_at"`
+	Violations         []HTMLViolation `json:"violations,omitempty"`
+}
+
+// CreateThemeRequest creates a project theme, or a tenant theme shared by all
+// projects when ProjectID is empty.
+type CreateThemeRequest struct {
+	ProjectID          string `json:"project_id,omitempty"`
+	Name               string `json:"name"`
+	QueuePageStyle     string `json:"queue_page_style"`
+	QueuePageBaseColor string `json:"queue_page_base_color,omitempty"`
+	QueuePageTitle     string `json:"queue_page_title,omitempty"`
+	QueuePageLogo      string `json:"queue_page_logo,omitempty"`
+}
+
+type ListThemeResponse struct {
+	ProjectID     string  `json:"project_id"`
+	ActiveThemeID string  `json:"active_theme_id"`
+	Themes        []Theme `json:"themes"`
+}
+
+type ActivateThemeRequest struct {
+	ProjectID string `json:"project_id"`
+	ThemeID   string `json:"theme_id"`
+}
+
+type ScheduleThemeRequest struct {
+	ProjectID string `json:"project_id"`
+	ThemeID   string `json:"theme_id"`
+	SwitchAt  string `json:"switch_at"`
+}
+
+type ThemeSchedule struct {
+	ID        string     `json:"id"`
+	ProjectID string     `json:"project_id"`
+	ThemeID   string     `json:"theme_id"`
+	SwitchAt  time.Time  `json:"switch_at"`
+	AppliedAt *time.Time `json:"applied_at,omitempty"`
+}
+
+type ListThemeScheduleResponse struct {
+	ProjectID string          `json:"project_id"`
+	Schedules []ThemeSchedule `json:"schedules"`
+}
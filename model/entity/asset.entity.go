@@ -0,0 +1,28 @@
+package entity
+
+import (
+	"database/sql"
+	"time"
+)
+
+type LogoAsset struct {
+	Hash        string    `db:"hash"`
+	URL         string    `db:"url"`
+	ContentType string    `db:"content_type"`
+	Size        int       `db:"size"`
+	CreatedAt   time.Time `db:"created_at"`
+	Name        string    `db:"name"`
+}
+
+type PageVersion struct {
+	ID          string         `db:"id"`
+	TenantID    string         `db:"tenant_id"`
+	ProjectID   sql.NullString `db:"project_id"`
+	Name        string         `db:"name"`
+	ObjectName  string         `db:"object_name"`
+	Hash        string         `db:"hash"`
+	URL         string         `db:"url"`
+	LogoURL     sql.NullString `db:"logo_url"`
+	PublishedAt time.Time      `db:"published_at"`
+	CreatedAt   time.Time      `db:"created_at"`
+}
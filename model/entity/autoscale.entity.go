@@ -0,0 +1,36 @@
+package entity
+
+import (
+	"database/sql"
+	"time"
+)
+
+type AutoscalePolicy struct {
+	ProjectID      string       `db:"project_id"`
+	Enabled        bool         `db:"enabled"`
+	MinThreshold   int          `db:"min_threshold"`
+	MaxThreshold   int          `db:"max_threshold"`
+	StepDown       int          `db:"step_down"`
+	StepUp         int          `db:"step_up"`
+	LastAdjustedAt sql.NullTime `db:"last_adjusted_at"`
+	CreatedAt      time.Time    `db:"created_at"`
+	UpdatedAt      sql.NullTime `db:"updated_at,omitempty"`
+}
+
+type AutoscalePolicyWithConfig struct {
+	AutoscalePolicy
+	Threshold          int           `db:"threshold"`
+	EffectiveThreshold sql.NullInt32 `db:"effective_threshold"`
+}
+
+type ThresholdAdjustment struct {
+	ID            string         `db:"id"`
+	ProjectID     string         `db:"project_id"`
+	FromThreshold int            `db:"from_threshold"`
+	ToThreshold   int            `db:"to_threshold"`
+	Healthiness   bool           `db:"healthiness"`
+	UsersInRoom   sql.NullInt32  `db:"users_in_room"`
+	UsersInQueue  sql.NullInt32  `db:"users_in_queue"`
+	Reason        sql.NullString `db:"reason"`
+	CreatedAt     time.Time      `db:"created_at"`
+}
@@ -0,0 +1,24 @@
+package entity
+
+import (
+	"database/sql"
+	"time"
+)
+
+type BypassRule struct {
+	ID          string         `db:"id"`
+	ProjectID   string         `db:"project_id"`
+	Type        string         `db:"type"`
+	Name        string         `db:"name"`
+	CodeHash    sql.NullString `db:"code_hash"`
+	CIDR        sql.NullString `db:"cidr"`
+	HeaderName  sql.NullString `db:"header_name"`
+	HeaderValue sql.NullString `db:"header_value"`
+	MaxUses     int            `db:"max_uses"`
+	UsedCount   int            `db:"used_count"`
+	LastUsedAt  sql.NullTime   `db:"last_used_at"`
+	ExpiresAt   sql.NullTime   `db:"expires_at"`
+	IsActive    bool           `db:"is_active"`
+	CreatedAt   time.Time      `db:"created_at"`
+	UpdatedAt   sql.NullTime   `db:"updated_at,omitempty"`
+}
@@ -2,6 +2,7 @@ package entity
 
 import (
 	"database/sql"
+	"time"
 )
 
 type Configuration struct {
@@ -20,5 +21,24 @@ type Configuration struct {
 	QueuePageTitle     sql.NullString `db:"queue_page_title"`
 	QueuePageLogo      sql.NullString `db:"queue_page_logo"`
 	IsConfigure        bool           `db:"is_configure"`
+	OperationalState   string         `db:"operational_state"`
+	StateReason        sql.NullString `db:"state_reason"`
+	StateChangedAt     sql.NullTime   `db:"state_changed_at"`
+	AutoResumeAt       sql.NullTime   `db:"auto_resume_at"`
+	QueueMode          string         `db:"queue_mode"`
+	PreQueueOpenAt     sql.NullTime   `db:"pre_queue_open_at"`
+	LotterySeed        sql.NullString `db:"lottery_seed"`
+	EffectiveThreshold sql.NullInt32  `db:"effective_threshold"`
+	ActiveThemeID      sql.NullString `db:"active_theme_id"`
+	NotifiedQueueStart sql.NullTime   `db:"notified_queue_start"`
+	NotifiedQueueEnd   sql.NullTime   `db:"notified_queue_end"`
 	UpdatedAt          sql.NullTime   `db:"updated_at,omitempty"`
 }
+
+// QueueWindow is the queue window of a configured project.
+type QueueWindow struct {
+	ProjectID  string    `db:"project_id"`
+	TenantID   string    `db:"tenant_id"`
+	QueueStart time.Time `db:"queue_start"`
+	QueueEnd   time.Time `db:"queue_end"`
+}
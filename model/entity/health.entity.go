@@ -0,0 +1,34 @@
+package entity
+
+import (
+	"database/sql"
+	"time"
+)
+
+type HealthCheck struct {
+	ID        string    `db:"id"`
+	ProjectID string    `db:"project_id"`
+	Healthy   bool      `db:"healthy"`
+	CheckedAt time.Time `db:"checked_at"`
+}
+
+type HealthIncident struct {
+	ID        string       `db:"id"`
+	ProjectID string       `db:"project_id"`
+	StartedAt time.Time    `db:"started_at"`
+	EndedAt   sql.NullTime `db:"ended_at"`
+}
+
+// HealthTarget is a project the prober checks, LastHealthy is the result of
+// its latest check.
+type HealthTarget struct {
+	ProjectID   string       `db:"project_id"`
+	TenantID    string       `db:"tenant_id"`
+	LastHealthy sql.NullBool `db:"last_healthy"`
+}
+
+type HealthSummary struct {
+	Total          int          `db:"total"`
+	Healthy        int          `db:"healthy"`
+	FirstCheckedAt sql.NullTime `db:"first_checked_at"`
+}
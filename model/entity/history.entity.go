@@ -0,0 +1,15 @@
+package entity
+
+import (
+	"database/sql"
+	"time"
+)
+
+type ProjectHistory struct {
+	ID        string         `db:"id"`
+	ProjectID string         `db:"project_id"`
+	Actor     sql.NullString `db:"actor"`
+	Action    string         `db:"action"`
+	Detail    []byte         `db:"detail"`
+	CreatedAt time.Time      `db:"created_at"`
+}
@@ -0,0 +1,15 @@
+package entity
+
+import (
+	"database/sql"
+	"time"
+)
+
+type ProjectLocale struct {
+	ID        string       `db:"id"`
+	ProjectID string       `db:"project_id"`
+	Locale    string       `db:"locale"`
+	Texts     []byte       `db:"texts"`
+	CreatedAt time.Time    `db:"created_at"`
+	UpdatedAt sql.NullTime `db:"updated_at,omitempty"`
+}
@@ -1,36 +1,79 @@
 package entity
 
 import (
+	"antrein/bc-dashboard/model/types"
 	"database/sql"
 	"time"
 )
 
 type Project struct {
-	ID        string       `db:"id"`
-	Name      string       `db:"name"`
-	TenantID  string       `db:"tenant_id"`
-	CreatedAt time.Time    `db:"created_at"`
-	UpdatedAt sql.NullTime `db:"updated_at,omitempty"`
+	ID           string                `db:"id"`
+	Name         string                `db:"name"`
+	TenantID     string                `db:"tenant_id"`
+	Description  sql.NullString        `db:"description"`
+	Tags         types.NullStringArray `db:"tags"`
+	Environment  sql.NullString        `db:"environment"`
+	ContactEmail sql.NullString        `db:"contact_email"`
+	InfraMode    sql.NullString        `db:"infra_mode"`
+	ResourceSize sql.NullString        `db:"resource_size"`
+	CreatedAt    time.Time             `db:"created_at"`
+	UpdatedAt    sql.NullTime          `db:"updated_at,omitempty"`
+}
+
+// ProjectSummary is a project in the listing, LastHealthy is the result of
+// its latest recorded health check.
+type ProjectSummary struct {
+	Project
+	IsConfigure bool         `db:"is_configure"`
+	LastHealthy sql.NullBool `db:"last_healthy"`
+}
+
+type ProjectFilter struct {
+	TenantID   string
+	Search     string
+	Configured sql.NullBool
+	Healthy    sql.NullBool
+	SortBy     string
+	SortOrder  string
+	Limit      int
+	Offset     int
 }
 
 type ProjectWithConfig struct {
-	ID                 string         `db:"id"`
-	Name               string         `db:"name"`
-	TenantID           string         `db:"tenant_id"`
-	ProjectID          string         `db:"project_id"`
-	Threshold          int            `db:"threshold"`
-	SessionTime        int            `db:"session_time"`
-	Host               sql.NullString `db:"host"`
-	BaseURL            sql.NullString `db:"base_url"`
-	MaxUsersInQueue    int            `db:"max_users_in_queue"`
-	QueueStart         sql.NullTime   `db:"queue_start"`
-	QueueEnd           sql.NullTime   `db:"queue_end"`
-	QueuePageStyle     string         `db:"queue_page_style"`
-	QueueHTMLPage      sql.NullString `db:"queue_html_page"`
-	QueuePageBaseColor sql.NullString `db:"queue_page_base_color"`
-	QueuePageTitle     sql.NullString `db:"queue_page_title"`
-	QueuePageLogo      sql.NullString `db:"queue_page_logo"`
-	IsConfigure        bool           `db:"is_configure"`
-	CreatedAt          time.Time      `db:"created_at"`
-	UpdatedAt          sql.NullTime   `db:"updated_at,omitempty"`
+	ID                 string                `db:"id"`
+	Name               string                `db:"name"`
+	TenantID           string                `db:"tenant_id"`
+	Description        sql.NullString        `db:"description"`
+	Tags               types.NullStringArray `db:"tags"`
+	Environment        sql.NullString        `db:"environment"`
+	ContactEmail       sql.NullString        `db:"contact_email"`
+	InfraMode          sql.NullString        `db:"infra_mode"`
+	ResourceSize       sql.NullString        `db:"resource_size"`
+	ProjectID          string                `db:"project_id"`
+	Threshold          int                   `db:"threshold"`
+	SessionTime        int                   `db:"session_time"`
+	Host               sql.NullString        `db:"host"`
+	BaseURL            sql.NullString        `db:"base_url"`
+	MaxUsersInQueue    int                   `db:"max_users_in_queue"`
+	QueueStart         sql.NullTime          `db:"queue_start"`
+	QueueEnd           sql.NullTime          `db:"queue_end"`
+	QueuePageStyle     string                `db:"queue_page_style"`
+	QueueHTMLPage      sql.NullString        `db:"queue_html_page"`
+	QueuePageBaseColor sql.NullString        `db:"queue_page_base_color"`
+	QueuePageTitle     sql.NullString        `db:"queue_page_title"`
+	QueuePageLogo      sql.NullString        `db:"queue_page_logo"`
+	IsConfigure        bool                  `db:"is_configure"`
+	OperationalState   string                `db:"operational_state"`
+	StateReason        sql.NullString        `db:"state_reason"`
+	StateChangedAt     sql.NullTime          `db:"state_changed_at"`
+	AutoResumeAt       sql.NullTime          `db:"auto_resume_at"`
+	QueueMode          string                `db:"queue_mode"`
+	PreQueueOpenAt     sql.NullTime          `db:"pre_queue_open_at"`
+	LotterySeed        sql.NullString        `db:"lottery_seed"`
+	EffectiveThreshold sql.NullInt32         `db:"effective_threshold"`
+	ActiveThemeID      sql.NullString        `db:"active_theme_id"`
+	NotifiedQueueStart sql.NullTime          `db:"notified_queue_start"`
+	NotifiedQueueEnd   sql.NullTime          `db:"notified_queue_end"`
+	CreatedAt          time.Time             `db:"created_at"`
+	UpdatedAt          sql.NullTime          `db:"updated_at,omitempty"`
 }
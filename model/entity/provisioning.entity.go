@@ -0,0 +1,24 @@
+package entity
+
+import (
+	"database/sql"
+	"time"
+)
+
+type ProjectProvisioning struct {
+	ProjectID      string         `db:"project_id"`
+	State          string         `db:"state"`
+	LastError      sql.NullString `db:"last_error"`
+	StateChangedAt time.Time      `db:"state_changed_at"`
+	CreatedAt      time.Time      `db:"created_at"`
+	UpdatedAt      sql.NullTime   `db:"updated_at,omitempty"`
+}
+
+type ProvisioningTransition struct {
+	ID        string         `db:"id"`
+	ProjectID string         `db:"project_id"`
+	FromState string         `db:"from_state"`
+	ToState   string         `db:"to_state"`
+	Error     sql.NullString `db:"error"`
+	CreatedAt time.Time      `db:"created_at"`
+}
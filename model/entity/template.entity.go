@@ -0,0 +1,22 @@
+package entity
+
+import (
+	"database/sql"
+	"time"
+)
+
+type ProjectTemplate struct {
+	ID                 string         `db:"id"`
+	TenantID           string         `db:"tenant_id"`
+	Name               string         `db:"name"`
+	Threshold          int            `db:"threshold"`
+	SessionTime        int            `db:"session_time"`
+	MaxUsersInQueue    int            `db:"max_users_in_queue"`
+	QueuePageStyle     string         `db:"queue_page_style"`
+	QueueHTMLPage      sql.NullString `db:"queue_html_page"`
+	QueuePageBaseColor sql.NullString `db:"queue_page_base_color"`
+	QueuePageTitle     sql.NullString `db:"queue_page_title"`
+	QueuePageLogo      sql.NullString `db:"queue_page_logo"`
+	CreatedAt          time.Time      `db:"created_at"`
+	UpdatedAt          sql.NullTime   `db:"updated_at,omitempty"`
+}
@@ -6,10 +6,12 @@ import (
 )
 
 type Tenant struct {
-	ID        string       `db:"id"`
-	Email     string       `db:"email"`
-	Password  string       `db:"password"`
-	Name      string       `db:"name"`
-	CreatedAt time.Time    `db:"created_at"`
-	UpdatedAt sql.NullTime `db:"updated_at,omitempty"`
+	ID             string       `db:"id"`
+	Email          string       `db:"email"`
+	Password       string       `db:"password"`
+	Name           string       `db:"name"`
+	HTMLPolicyMode string       `db:"html_policy_mode"`
+	IsPremium      bool         `db:"is_premium"`
+	CreatedAt      time.Time    `db:"created_at"`
+	UpdatedAt      sql.NullTime `db:"updated_at,omitempty"`
 }
@@ -0,0 +1,29 @@
+package entity
+
+import (
+	"database/sql"
+	"time"
+)
+
+type Theme struct {
+	ID                 string         `db:"id"`
+	TenantID           string         `db:"tenant_id"`
+	ProjectID          sql.NullString `db:"project_id"`
+	Name               string         `db:"name"`
+	QueuePageStyle     string         `db:"queue_page_style"`
+	QueueHTMLPage      sql.NullString `db:"queue_html_page"`
+	QueuePageBaseColor sql.NullString `db:"queue_page_base_color"`
+	QueuePageTitle     sql.NullString `db:"queue_page_title"`
+	QueuePageLogo      sql.NullString `db:"queue_page_logo"`
+	CreatedAt          time.Time      `db:"created_at"`
+	UpdatedAt          sql.NullTime   `db:"updated_at,omitempty"`
+}
+
+type ThemeSchedule struct {
+	ID        string       `db:"id"`
+	ProjectID string       `db:"project_id"`
+	ThemeID   string       `db:"theme_id"`
+	SwitchAt  time.Time    `db:"switch_at"`
+	AppliedAt sql.NullTime `db:"applied_at"`
+	CreatedAt time.Time    `db:"created_at"`
+}
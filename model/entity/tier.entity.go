@@ -0,0 +1,14 @@
+package entity
+
+import "time"
+
+type PriorityTier struct {
+	ID              string    `db:"id"`
+	ProjectID       string    `db:"project_id"`
+	Name            string    `db:"name"`
+	Priority        int       `db:"priority"`
+	Weight          int       `db:"weight"`
+	ReservedShare   int       `db:"reserved_share"`
+	MaxUsersInQueue int       `db:"max_users_in_queue"`
+	CreatedAt       time.Time `db:"created_at"`
+}
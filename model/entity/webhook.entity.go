@@ -0,0 +1,44 @@
+package entity
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type Webhook struct {
+	ID          string         `db:"id"`
+	TenantID    string         `db:"tenant_id"`
+	URL         string         `db:"url"`
+	Secret      string         `db:"secret"`
+	Events      pq.StringArray `db:"events"`
+	Description sql.NullString `db:"description"`
+	IsActive    bool           `db:"is_active"`
+	CreatedAt   time.Time      `db:"created_at"`
+	UpdatedAt   sql.NullTime   `db:"updated_at,omitempty"`
+}
+
+type WebhookDelivery struct {
+	ID             string         `db:"id"`
+	WebhookID      string         `db:"webhook_id"`
+	EventID        string         `db:"event_id"`
+	EventType      string         `db:"event_type"`
+	Payload        []byte         `db:"payload"`
+	Status         string         `db:"status"`
+	Attempts       int            `db:"attempts"`
+	NextAttemptAt  sql.NullTime   `db:"next_attempt_at"`
+	LastStatusCode sql.NullInt32  `db:"last_status_code"`
+	LastError      sql.NullString `db:"last_error"`
+	DeliveredAt    sql.NullTime   `db:"delivered_at"`
+	CreatedAt      time.Time      `db:"created_at"`
+	UpdatedAt      sql.NullTime   `db:"updated_at,omitempty"`
+}
+
+// WebhookTarget is a delivery that is due together with where and how it is
+// sent.
+type WebhookTarget struct {
+	WebhookDelivery
+	URL    string `db:"url"`
+	Secret string `db:"secret"`
+}